@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Neokil/Gallery/internal/service"
+)
+
+// hashPassword implements the hash-password command, which reads a password
+// from stdin and prints a hash of it for GALLERY_PASSWORD_HASH. It returns the
+// exit code.
+func hashPassword(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("hash-password", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	algorithm := flags.String("algorithm", string(service.PasswordHashArgon2id), `hash algorithm, "argon2id" or "bcrypt"`)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gallery hash-password [-algorithm argon2id|bcrypt]")
+		fmt.Fprintln(stderr, "Reads the gallery password from stdin and prints its hash for GALLERY_PASSWORD_HASH.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *algorithm != string(service.PasswordHashArgon2id) && *algorithm != string(service.PasswordHashBcrypt) {
+		fmt.Fprintf(stderr, "Unknown algorithm %q\n", *algorithm)
+		flags.Usage()
+		return 2
+	}
+
+	fmt.Fprint(stderr, "Password: ")
+	password, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		fmt.Fprintln(stderr, "Failed to read password:", err)
+		return 1
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	hash, err := service.HashPassword(password, service.PasswordHashAlgorithm(*algorithm))
+	if err != nil {
+		fmt.Fprintln(stderr, "Failed to hash password:", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, hash)
+	return 0
+}
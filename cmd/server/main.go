@@ -1,15 +1,21 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net/http"
+	"net/netip"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
 
 	"github.com/Neokil/Gallery/internal/api"
 	"github.com/Neokil/Gallery/internal/handlers"
+	"github.com/Neokil/Gallery/internal/middleware"
 	"github.com/Neokil/Gallery/internal/service"
 )
 
@@ -18,16 +24,53 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "hash-password" {
+		os.Exit(hashPassword(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	// Environment variables
 	siteTitle := getEnv("SITE_TITLE", "Photo Gallery")
 	password := getEnv("GALLERY_PASSWORD", "")
+	passwordHash := getEnv("GALLERY_PASSWORD_HASH", "")
+	adminUsername := getEnv("ADMIN_USERNAME", "")
+	adminPassword := getEnv("ADMIN_PASSWORD", "")
 	sessionKey := getEnv("SESSION_KEY", "")
 	uploadDir := getEnv("UPLOAD_DIR", "./uploads")
 	metadataDir := getEnv("METADATA_DIR", "./metadata")
 	port := getEnv("PORT", "8080")
+	watchUploads := getEnv("WATCH_UPLOAD_DIR", "false") == "true"
+	rotatedCopies := getEnv("ROTATED_DISPLAY_COPIES", "false") == "true"
+	blobStore := getEnv("BLOB_STORE", "file")
+	trashRetentionDays, err := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "30"))
+	if err != nil || trashRetentionDays < 0 {
+		log.Fatal("TRASH_RETENTION_DAYS must be a non-negative number of days")
+	}
+	resampling := service.Resampling(getEnv("THUMBNAIL_RESAMPLING", string(service.ResampleCatmullRom)))
+	if !resampling.Valid() {
+		log.Fatal("THUMBNAIL_RESAMPLING must be nearest, bilinear, catmull-rom or lanczos")
+	}
+	renditionSizes, err := parseSizes(getEnv("RENDITION_SIZES", ""))
+	if err != nil {
+		log.Fatalf("Invalid RENDITION_SIZES: %v", err)
+	}
+	trustedProxies, err := parsePrefixes(getEnv("TRUSTED_PROXIES", ""))
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
-	if password == "" {
-		log.Fatal("GALLERY_PASSWORD environment variable is required")
+	if password == "" && passwordHash == "" && adminUsername == "" {
+		log.Fatal("GALLERY_PASSWORD, GALLERY_PASSWORD_HASH or ADMIN_USERNAME environment variable is required")
+	}
+	if password != "" && passwordHash != "" {
+		log.Fatal("Only one of GALLERY_PASSWORD and GALLERY_PASSWORD_HASH may be set")
+	}
+	if passwordHash != "" {
+		if err := service.ValidatePasswordHash(passwordHash); err != nil {
+			log.Fatalf("Invalid GALLERY_PASSWORD_HASH: %v", err)
+		}
+	}
+	if (adminUsername == "") != (adminPassword == "") {
+		log.Fatal("ADMIN_USERNAME and ADMIN_PASSWORD must be set together")
 	}
 
 	// Create directories
@@ -40,8 +83,74 @@ func main() {
 
 	// Initialize services
 	log.Printf("Initializing gallery service...")
-	galleryService := service.NewGalleryService(uploadDir, metadataDir)
-	authService := service.NewAuthService(password, sessionKey)
+	galleryOptions := []service.GalleryOption{service.WithResampling(resampling)}
+	if renditionSizes != nil {
+		galleryOptions = append(galleryOptions, service.WithRenditionSizes(renditionSizes...))
+	} else {
+		renditionSizes = service.DefaultRenditionSizes
+	}
+	if rotatedCopies {
+		galleryOptions = append(galleryOptions, service.WithRotatedDisplayCopies())
+	}
+	switch blobStore {
+	case "file":
+	case "s3":
+		prefixes := []string{"photos", "thumbnails", "trash/photos", "trash/thumbnails", "derivatives"}
+		for _, size := range renditionSizes {
+			prefixes = append(prefixes, fmt.Sprintf("renditions/%d", size))
+		}
+		stores, err := newS3BlobStores(prefixes...)
+		if err != nil {
+			log.Fatal("Failed to initialize S3 blob store:", err)
+		}
+		renditions := make(map[int]service.BlobStore, len(renditionSizes))
+		for i, size := range renditionSizes {
+			renditions[size] = stores[5+i]
+		}
+		galleryOptions = append(galleryOptions,
+			service.WithBlobStores(stores[0], stores[1]),
+			service.WithTrashStores(stores[2], stores[3]),
+			service.WithDerivativeStore(stores[4]),
+			service.WithRenditionStores(renditions))
+	default:
+		log.Fatalf("Unknown BLOB_STORE %q (expected \"file\" or \"s3\")", blobStore)
+	}
+	galleryService, err := service.NewGalleryService(uploadDir, metadataDir, galleryOptions...)
+	if err != nil {
+		log.Fatal("Failed to initialize gallery service:", err)
+	}
+	defer func() {
+		if err := galleryService.Close(); err != nil {
+			log.Printf("Failed to close gallery service: %v", err)
+		}
+	}()
+	if watchUploads {
+		if err := galleryService.WatchUploadDir(); err != nil {
+			log.Fatal("Failed to watch upload directory:", err)
+		}
+	}
+	if trashRetentionDays > 0 {
+		galleryService.StartTrashPurge(time.Duration(trashRetentionDays) * 24 * time.Hour)
+	}
+	if adminUsername != "" {
+		if err := galleryService.EnsureAdmin(adminUsername, adminPassword); err != nil {
+			log.Fatal("Failed to create admin user:", err)
+		}
+	}
+	authOptions := []service.AuthOption{
+		service.WithAccounts(galleryService),
+		service.WithEventPasswords(galleryService),
+		service.WithShares(galleryService),
+		service.WithSessions(galleryService),
+		service.WithAPITokens(galleryService),
+	}
+	if passwordHash != "" {
+		authOptions = append(authOptions, service.WithPasswordHash(passwordHash))
+	}
+	if trustedProxies != nil {
+		authOptions = append(authOptions, service.WithTrustedProxies(trustedProxies))
+	}
+	authService := service.NewAuthService(password, sessionKey, authOptions...)
 
 	// Initialize handlers
 	h, err := handlers.NewHandlers(galleryService, authService, siteTitle)
@@ -53,8 +162,8 @@ func main() {
 	r := chi.NewRouter()
 
 	// Add middleware
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	r.Use(chimiddleware.Logger)
+	r.Use(chimiddleware.Recoverer)
 	r.Use(addSecurityHeaders)
 
 	// Create regular server wrapper for session handling
@@ -62,8 +171,16 @@ func main() {
 		handlers: h,
 	}
 
-	// Mount the API routes
-	api.HandlerFromMux(serverWrapper, r)
+	// Mount the API routes; the security requirements of the spec are
+	// enforced for every operation
+	spec, err := api.GetSwagger()
+	if err != nil {
+		log.Fatal("Failed to load API specification:", err)
+	}
+	api.HandlerWithOptions(serverWrapper, api.ChiServerOptions{
+		BaseRouter:  r,
+		Middlewares: []api.MiddlewareFunc{middleware.AuthMiddleware(authService, spec, http.HandlerFunc(h.HandleCSRFFailure))},
+	})
 
 	log.Printf("Server starting on port %s", port)
 	log.Printf("Site title: %s", siteTitle)
@@ -77,6 +194,73 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// parsePrefixes parses a comma-separated list of CIDR networks or single IP
+// addresses. It returns nil for an empty list.
+func parsePrefixes(list string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// parseSizes parses a comma-separated list of sizes in pixels. It returns nil
+// for an empty list.
+func parseSizes(list string) ([]int, error) {
+	var sizes []int
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		size, err := strconv.Atoi(item)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("%q is not a size in pixels", item)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// newS3BlobStores creates one blob store per key prefix in the S3 bucket
+// configured through the S3_* environment variables.
+func newS3BlobStores(prefixes ...string) ([]service.BlobStore, error) {
+	cfg := service.S3Config{
+		Endpoint:  getEnv("S3_ENDPOINT", ""),
+		Bucket:    getEnv("S3_BUCKET", ""),
+		AccessKey: getEnv("S3_ACCESS_KEY", ""),
+		SecretKey: getEnv("S3_SECRET_KEY", ""),
+		Region:    getEnv("S3_REGION", ""),
+		UseSSL:    getEnv("S3_USE_SSL", "true") == "true",
+	}
+
+	stores := make([]service.BlobStore, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		store, err := service.NewS3BlobStore(cfg, prefix)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+	return stores, nil
+}
+
 func addSecurityHeaders(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-Content-Type-Options", "nosniff")
@@ -108,6 +292,14 @@ func (s *ServerWrapper) PostLogout(w http.ResponseWriter, r *http.Request) {
 	s.handlers.HandlePostLogout(w, r)
 }
 
+func (s *ServerWrapper) UnlockEvents(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleUnlockEvents(w, r)
+}
+
+func (s *ServerWrapper) OpenShare(w http.ResponseWriter, r *http.Request, token string, params api.OpenShareParams) {
+	s.handlers.HandleOpenShare(w, r, token, params)
+}
+
 func (s *ServerWrapper) UploadPhotos(w http.ResponseWriter, r *http.Request) {
 	s.handlers.HandleUpload(w, r)
 }
@@ -116,8 +308,144 @@ func (s *ServerWrapper) DownloadAllPhotos(w http.ResponseWriter, r *http.Request
 	s.handlers.HandleDownloadAll(w, r, params)
 }
 
-func (s *ServerWrapper) ServePhoto(w http.ResponseWriter, r *http.Request, filename string) {
-	s.handlers.HandleServePhoto(w, r, filename)
+func (s *ServerWrapper) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleListDuplicates(w, r)
+}
+
+func (s *ServerWrapper) ResolveDuplicates(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleResolveDuplicates(w, r)
+}
+
+func (s *ServerWrapper) UpdatePhotos(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleUpdatePhotos(w, r)
+}
+
+func (s *ServerWrapper) UpdatePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	s.handlers.HandleUpdatePhoto(w, r, filename)
+}
+
+func (s *ServerWrapper) DeletePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	s.handlers.HandleDeletePhoto(w, r, filename)
+}
+
+func (s *ServerWrapper) ListEvents(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleListEvents(w, r)
+}
+
+func (s *ServerWrapper) UpdateEvent(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleUpdateEvent(w, r, id)
+}
+
+func (s *ServerWrapper) MergeEvents(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleMergeEvents(w, r, id)
+}
+
+func (s *ServerWrapper) ListAlbums(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleListAlbums(w, r)
+}
+
+func (s *ServerWrapper) CreateAlbum(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleCreateAlbum(w, r)
+}
+
+func (s *ServerWrapper) GetAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleGetAlbum(w, r, id)
+}
+
+func (s *ServerWrapper) UpdateAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleUpdateAlbum(w, r, id)
+}
+
+func (s *ServerWrapper) DeleteAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleDeleteAlbum(w, r, id)
+}
+
+func (s *ServerWrapper) AddAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleAddAlbumPhotos(w, r, id)
+}
+
+func (s *ServerWrapper) ReorderAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleReorderAlbumPhotos(w, r, id)
+}
+
+func (s *ServerWrapper) RemoveAlbumPhoto(w http.ResponseWriter, r *http.Request, id int64, filename string) {
+	s.handlers.HandleRemoveAlbumPhoto(w, r, id, filename)
+}
+
+func (s *ServerWrapper) SuggestTags(w http.ResponseWriter, r *http.Request, params api.SuggestTagsParams) {
+	s.handlers.HandleSuggestTags(w, r, params)
+}
+
+func (s *ServerWrapper) ListTrash(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleListTrash(w, r)
+}
+
+func (s *ServerWrapper) RestorePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	s.handlers.HandleRestorePhoto(w, r, filename)
+}
+
+func (s *ServerWrapper) PurgePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	s.handlers.HandlePurgePhoto(w, r, filename)
+}
+
+func (s *ServerWrapper) ListUsers(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleListUsers(w, r)
+}
+
+func (s *ServerWrapper) CreateUser(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleCreateUser(w, r)
+}
+
+func (s *ServerWrapper) UpdateUser(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleUpdateUser(w, r, id)
+}
+
+func (s *ServerWrapper) DeleteUser(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleDeleteUser(w, r, id)
+}
+
+func (s *ServerWrapper) ListShares(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleListShares(w, r)
+}
+
+func (s *ServerWrapper) CreateShare(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleCreateShare(w, r)
+}
+
+func (s *ServerWrapper) DeleteShare(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleDeleteShare(w, r, id)
+}
+
+func (s *ServerWrapper) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleListAPITokens(w, r)
+}
+
+func (s *ServerWrapper) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleCreateAPIToken(w, r)
+}
+
+func (s *ServerWrapper) RevokeAPIToken(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleRevokeAPIToken(w, r, id)
+}
+
+func (s *ServerWrapper) ListSessions(w http.ResponseWriter, r *http.Request) {
+	s.handlers.HandleListSessions(w, r)
+}
+
+func (s *ServerWrapper) RevokeSession(w http.ResponseWriter, r *http.Request, id int64) {
+	s.handlers.HandleRevokeSession(w, r, id)
+}
+
+func (s *ServerWrapper) GetShareQRCode(w http.ResponseWriter, r *http.Request, id int64, params api.GetShareQRCodeParams) {
+	s.handlers.HandleGetShareQRCode(w, r, id, params)
+}
+
+func (s *ServerWrapper) ServeRendition(w http.ResponseWriter, r *http.Request, size int, filename string) {
+	s.handlers.HandleServeRendition(w, r, size, filename)
+}
+
+func (s *ServerWrapper) ServePhoto(w http.ResponseWriter, r *http.Request, filename string, params api.ServePhotoParams) {
+	s.handlers.HandleServePhoto(w, r, filename, params)
 }
 
 func (s *ServerWrapper) ServeThumbnail(w http.ResponseWriter, r *http.Request, filename string) {
@@ -8,6 +8,7 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -15,6 +16,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
@@ -24,901 +26,6179 @@ import (
 )
 
 const (
+	BearerAuthScopes  = "bearerAuth.Scopes"
 	SessionAuthScopes = "sessionAuth.Scopes"
+	ShareAuthScopes   = "shareAuth.Scopes"
 )
 
-// GetGalleryParams defines parameters for GetGallery.
-type GetGalleryParams struct {
-	// Event Filter photos by event name
-	Event *string `form:"event,omitempty" json:"event,omitempty"`
+// Defines values for NewShareKind.
+const (
+	NewShareKindUpload NewShareKind = "upload"
+)
 
-	// Uploader Filter photos by uploader name
-	Uploader *string `form:"uploader,omitempty" json:"uploader,omitempty"`
-}
+// Defines values for Role.
+const (
+	RoleAdmin       Role = "admin"
+	RoleContributor Role = "contributor"
+	RoleViewer      Role = "viewer"
+)
 
-// DownloadAllPhotosParams defines parameters for DownloadAllPhotos.
-type DownloadAllPhotosParams struct {
-	// Event Filter photos by event name
-	Event *string `form:"event,omitempty" json:"event,omitempty"`
+// Defines values for SessionKind.
+const (
+	SessionKindEvent SessionKind = "event"
+	SessionKindGuest SessionKind = "guest"
+	SessionKindShare SessionKind = "share"
+	SessionKindUser  SessionKind = "user"
+)
 
-	// Uploader Filter photos by uploader name
-	Uploader *string `form:"uploader,omitempty" json:"uploader,omitempty"`
-}
+// Defines values for ShareKind.
+const (
+	ShareKindAlbum  ShareKind = "album"
+	ShareKindEvent  ShareKind = "event"
+	ShareKindPhoto  ShareKind = "photo"
+	ShareKindUpload ShareKind = "upload"
+)
 
-// PostLoginFormdataBody defines parameters for PostLogin.
-type PostLoginFormdataBody struct {
-	// Password Gallery password
-	Password string `form:"password" json:"password"`
-}
+// Defines values for TokenScope.
+const (
+	TokenScopeAdmin  TokenScope = "admin"
+	TokenScopeRead   TokenScope = "read"
+	TokenScopeUpload TokenScope = "upload"
+)
 
-// UploadPhotosMultipartBody defines parameters for UploadPhotos.
-type UploadPhotosMultipartBody struct {
-	// EventName Event name for organizing photos
-	EventName *string `json:"event_name,omitempty"`
+// Defines values for GetGalleryParamsTagMode.
+const (
+	GetGalleryParamsTagModeAll GetGalleryParamsTagMode = "all"
+	GetGalleryParamsTagModeAny GetGalleryParamsTagMode = "any"
+)
 
-	// Photos Photo files to upload
-	Photos []openapi_types.File `json:"photos"`
+// Defines values for DownloadAllPhotosParamsTagMode.
+const (
+	DownloadAllPhotosParamsTagModeAll DownloadAllPhotosParamsTagMode = "all"
+	DownloadAllPhotosParamsTagModeAny DownloadAllPhotosParamsTagMode = "any"
+)
 
-	// UploaderName Name of the person uploading photos
-	UploaderName *string `json:"uploader_name,omitempty"`
-}
+// Defines values for DownloadAllPhotosParamsFormat.
+const (
+	DownloadAllPhotosParamsFormatJpeg     DownloadAllPhotosParamsFormat = "jpeg"
+	DownloadAllPhotosParamsFormatOriginal DownloadAllPhotosParamsFormat = "original"
+)
 
-// PostLoginFormdataRequestBody defines body for PostLogin for application/x-www-form-urlencoded ContentType.
-type PostLoginFormdataRequestBody PostLoginFormdataBody
+// Defines values for GetShareQRCodeParamsFormat.
+const (
+	Png GetShareQRCodeParamsFormat = "png"
+	Svg GetShareQRCodeParamsFormat = "svg"
+)
 
-// UploadPhotosMultipartRequestBody defines body for UploadPhotos for multipart/form-data ContentType.
-type UploadPhotosMultipartRequestBody UploadPhotosMultipartBody
+// Defines values for ServePhotoParamsFormat.
+const (
+	ServePhotoParamsFormatJpeg     ServePhotoParamsFormat = "jpeg"
+	ServePhotoParamsFormatOriginal ServePhotoParamsFormat = "original"
+)
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Gallery page
-	// (GET /)
-	GetGallery(w http.ResponseWriter, r *http.Request, params GetGalleryParams)
-	// Download all photos as ZIP
-	// (GET /download-all)
-	DownloadAllPhotos(w http.ResponseWriter, r *http.Request, params DownloadAllPhotosParams)
-	// Login page
-	// (GET /login)
-	GetLogin(w http.ResponseWriter, r *http.Request)
-	// Authenticate user
-	// (POST /login)
-	PostLogin(w http.ResponseWriter, r *http.Request)
-	// Serve static assets
-	// (GET /static/{filename})
-	ServeStatic(w http.ResponseWriter, r *http.Request, filename string)
-	// Serve photo thumbnail
-	// (GET /thumbnails/{filename})
-	ServeThumbnail(w http.ResponseWriter, r *http.Request, filename string)
-	// Upload photos
-	// (POST /upload)
-	UploadPhotos(w http.ResponseWriter, r *http.Request)
-	// Serve uploaded photo
-	// (GET /uploads/{filename})
-	ServePhoto(w http.ResponseWriter, r *http.Request, filename string)
-}
+// APIToken defines model for APIToken.
+type APIToken struct {
+	// Created Creation time of the token
+	Created time.Time `json:"created"`
 
-// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+	// Id Database ID of the token
+	Id int64 `json:"id"`
 
-type Unimplemented struct{}
+	// LastUsed Time of the last request with the token, updated at most once a minute
+	LastUsed *time.Time `json:"last_used,omitempty"`
 
-// Gallery page
-// (GET /)
-func (_ Unimplemented) GetGallery(w http.ResponseWriter, r *http.Request, params GetGalleryParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	// Name Name of the token, recorded as uploader of its photos
+	Name string `json:"name"`
 
-// Download all photos as ZIP
-// (GET /download-all)
-func (_ Unimplemented) DownloadAllPhotos(w http.ResponseWriter, r *http.Request, params DownloadAllPhotosParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	// Scope What an API token may do: browse and download (read), also upload
+	// photos (upload), or everything (admin)
+	Scope TokenScope `json:"scope"`
 }
 
-// Login page
-// (GET /login)
-func (_ Unimplemented) GetLogin(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+// APITokenList defines model for APITokenList.
+type APITokenList struct {
+	Tokens []APIToken `json:"tokens"`
 }
 
-// Authenticate user
-// (POST /login)
-func (_ Unimplemented) PostLogin(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+// Album defines model for Album.
+type Album struct {
+	// CoverPhoto Filename of the first photo of the album
+	CoverPhoto *string `json:"cover_photo,omitempty"`
 
-// Serve static assets
-// (GET /static/{filename})
-func (_ Unimplemented) ServeStatic(w http.ResponseWriter, r *http.Request, filename string) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	// Created Creation time of the album
+	Created time.Time `json:"created"`
 
-// Serve photo thumbnail
-// (GET /thumbnails/{filename})
-func (_ Unimplemented) ServeThumbnail(w http.ResponseWriter, r *http.Request, filename string) {
-	w.WriteHeader(http.StatusNotImplemented)
+	// Id Database ID of the album
+	Id int64 `json:"id"`
+
+	// Name Name of the album
+	Name string `json:"name"`
+
+	// PhotoCount Number of photos in the album
+	PhotoCount int `json:"photo_count"`
 }
 
-// Upload photos
-// (POST /upload)
-func (_ Unimplemented) UploadPhotos(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+// AlbumDetail defines model for AlbumDetail.
+type AlbumDetail struct {
+	// CoverPhoto Filename of the first photo of the album
+	CoverPhoto *string `json:"cover_photo,omitempty"`
+
+	// Created Creation time of the album
+	Created time.Time `json:"created"`
+
+	// Id Database ID of the album
+	Id int64 `json:"id"`
+
+	// Name Name of the album
+	Name string `json:"name"`
+
+	// PhotoCount Number of photos in the album
+	PhotoCount int `json:"photo_count"`
+
+	// Photos Photos of the album in album order
+	Photos []PhotoInfo `json:"photos"`
 }
 
-// Serve uploaded photo
-// (GET /uploads/{filename})
-func (_ Unimplemented) ServePhoto(w http.ResponseWriter, r *http.Request, filename string) {
-	w.WriteHeader(http.StatusNotImplemented)
+// AlbumInput defines model for AlbumInput.
+type AlbumInput struct {
+	// Name Name of the album
+	Name string `json:"name"`
 }
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+// AlbumList defines model for AlbumList.
+type AlbumList struct {
+	Albums []Album `json:"albums"`
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
+// BulkPhotoUpdate defines model for BulkPhotoUpdate.
+type BulkPhotoUpdate struct {
+	// AddTags Tags to add, applied after tags
+	AddTags *[]string `json:"add_tags,omitempty"`
 
-// GetGallery operation middleware
-func (siw *ServerInterfaceWrapper) GetGallery(w http.ResponseWriter, r *http.Request) {
+	// Caption New caption, empty to clear it
+	Caption *string `json:"caption,omitempty"`
 
-	var err error
+	// Event New event name, empty to clear it
+	Event *string `json:"event,omitempty"`
 
-	ctx := r.Context()
+	// Names Names of the photos to update
+	Names []string `json:"names"`
 
-	ctx = context.WithValue(ctx, SessionAuthScopes, []string{})
+	// RemoveTags Tags to remove, applied after tags
+	RemoveTags *[]string `json:"remove_tags,omitempty"`
 
-	r = r.WithContext(ctx)
+	// Tags Replaces all tags, an empty list removes them
+	Tags *[]string `json:"tags,omitempty"`
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetGalleryParams
+	// Uploader New uploader name, must not be empty
+	Uploader *string `json:"uploader,omitempty"`
+}
 
-	// ------------- Optional query parameter "event" -------------
+// CreatedAPIToken defines model for CreatedAPIToken.
+type CreatedAPIToken struct {
+	// Created Creation time of the token
+	Created time.Time `json:"created"`
 
-	err = runtime.BindQueryParameter("form", true, false, "event", r.URL.Query(), &params.Event)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "event", Err: err})
-		return
-	}
+	// Id Database ID of the token
+	Id int64 `json:"id"`
 
-	// ------------- Optional query parameter "uploader" -------------
+	// LastUsed Time of the last request with the token, updated at most once a minute
+	LastUsed *time.Time `json:"last_used,omitempty"`
 
-	err = runtime.BindQueryParameter("form", true, false, "uploader", r.URL.Query(), &params.Uploader)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "uploader", Err: err})
-		return
-	}
+	// Name Name of the token, recorded as uploader of its photos
+	Name string `json:"name"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetGallery(w, r, params)
-	}))
+	// Scope What an API token may do: browse and download (read), also upload
+	// photos (upload), or everything (admin)
+	Scope TokenScope `json:"scope"`
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// Token Secret value of the token; it cannot be retrieved again
+	Token string `json:"token"`
+}
 
-	handler.ServeHTTP(w, r)
+// DuplicateCluster defines model for DuplicateCluster.
+type DuplicateCluster struct {
+	// Photos Photos that look alike, newest first
+	Photos []PhotoInfo `json:"photos"`
 }
 
-// DownloadAllPhotos operation middleware
-func (siw *ServerInterfaceWrapper) DownloadAllPhotos(w http.ResponseWriter, r *http.Request) {
+// DuplicateClusters defines model for DuplicateClusters.
+type DuplicateClusters struct {
+	Clusters []DuplicateCluster `json:"clusters"`
+}
 
-	var err error
+// DuplicateResolution defines model for DuplicateResolution.
+type DuplicateResolution struct {
+	// Keep Names of the photos to keep
+	Keep []string `json:"keep"`
 
-	ctx := r.Context()
+	// Remove Names of the photos to delete
+	Remove []string `json:"remove"`
+}
 
-	ctx = context.WithValue(ctx, SessionAuthScopes, []string{})
+// Event defines model for Event.
+type Event struct {
+	// CoverPhoto Filename of the cover photo, the newest photo unless chosen
+	CoverPhoto *string `json:"cover_photo,omitempty"`
 
-	r = r.WithContext(ctx)
+	// Description Free-text description
+	Description string `json:"description"`
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params DownloadAllPhotosParams
+	// EndDate Last day of the event, derived from the photos unless set
+	EndDate *openapi_types.Date `json:"end_date,omitempty"`
 
-	// ------------- Optional query parameter "event" -------------
+	// Id Database ID of the event
+	Id int64 `json:"id"`
 
-	err = runtime.BindQueryParameter("form", true, false, "event", r.URL.Query(), &params.Event)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "event", Err: err})
-		return
-	}
+	// Name Display name, also the event of its photos
+	Name string `json:"name"`
 
-	// ------------- Optional query parameter "uploader" -------------
+	// PhotoCount Number of photos in the event
+	PhotoCount int `json:"photo_count"`
 
-	err = runtime.BindQueryParameter("form", true, false, "uploader", r.URL.Query(), &params.Uploader)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "uploader", Err: err})
-		return
-	}
+	// Protected Whether the event's photos need the event password
+	Protected bool `json:"protected"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DownloadAllPhotos(w, r, params)
-	}))
+	// StartDate First day of the event, derived from the photos unless set
+	StartDate *openapi_types.Date `json:"start_date,omitempty"`
+}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+// EventList defines model for EventList.
+type EventList struct {
+	Events []Event `json:"events"`
+}
 
-	handler.ServeHTTP(w, r)
+// EventMerge defines model for EventMerge.
+type EventMerge struct {
+	// Events IDs of the events to merge into this one
+	Events []int64 `json:"events"`
 }
 
-// GetLogin operation middleware
-func (siw *ServerInterfaceWrapper) GetLogin(w http.ResponseWriter, r *http.Request) {
+// EventUpdate defines model for EventUpdate.
+type EventUpdate struct {
+	// CoverPhoto Filename of a photo of the event, empty to use the newest photo
+	CoverPhoto *string `json:"cover_photo,omitempty"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetLogin(w, r)
-	}))
+	// Description New description
+	Description *string `json:"description,omitempty"`
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// EndDate New last day (YYYY-MM-DD), empty to derive it from the photos
+	EndDate *string `json:"end_date,omitempty"`
 
-	handler.ServeHTTP(w, r)
-}
+	// Name New name, must not be empty or taken by another event
+	Name *string `json:"name,omitempty"`
 
-// PostLogin operation middleware
-func (siw *ServerInterfaceWrapper) PostLogin(w http.ResponseWriter, r *http.Request) {
+	// Password New event password (at least 8 characters), empty to remove it
+	Password *string `json:"password,omitempty"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostLogin(w, r)
-	}))
+	// StartDate New first day (YYYY-MM-DD), empty to derive it from the photos
+	StartDate *string `json:"start_date,omitempty"`
+}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+// NewAPIToken defines model for NewAPIToken.
+type NewAPIToken struct {
+	// Name What the token is for
+	Name string `json:"name"`
 
-	handler.ServeHTTP(w, r)
+	// Scope What an API token may do: browse and download (read), also upload
+	// photos (upload), or everything (admin)
+	Scope TokenScope `json:"scope"`
 }
 
-// ServeStatic operation middleware
-func (siw *ServerInterfaceWrapper) ServeStatic(w http.ResponseWriter, r *http.Request) {
+// NewShare Exactly one of photo, event_id and album_id must be set, or none for
+// an upload link
+type NewShare struct {
+	// AlbumId ID of an album to share
+	AlbumId *int64 `json:"album_id,omitempty"`
 
-	var err error
+	// AllowUpload Allow visitors to upload photos to the shared event
+	AllowUpload *bool `json:"allow_upload,omitempty"`
 
-	// ------------- Path parameter "filename" -------------
-	var filename string
+	// EventId ID of an event to share
+	EventId *int64 `json:"event_id,omitempty"`
 
-	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
-		return
-	}
+	// Expires When the link stops working, at most a year ahead (default one week)
+	Expires *time.Time `json:"expires,omitempty"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ServeStatic(w, r, filename)
-	}))
+	// Kind Set to create an upload link that can upload photos but not see any
+	Kind *NewShareKind `json:"kind,omitempty"`
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// Label Who the link is for, e.g. "Aunt May"
+	Label *string `json:"label,omitempty"`
 
-	handler.ServeHTTP(w, r)
+	// Photo Filename of a photo to share
+	Photo *string `json:"photo,omitempty"`
 }
 
-// ServeThumbnail operation middleware
-func (siw *ServerInterfaceWrapper) ServeThumbnail(w http.ResponseWriter, r *http.Request) {
+// NewShareKind Set to create an upload link that can upload photos but not see any
+type NewShareKind string
 
-	var err error
+// NewUser defines model for NewUser.
+type NewUser struct {
+	// Password Password, at least 8 characters
+	Password string `json:"password"`
 
-	// ------------- Path parameter "filename" -------------
-	var filename string
+	// Role Permission level: viewers browse and download, contributors also upload
+	// and edit their own photos, admins manage everything
+	Role Role `json:"role"`
 
-	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
-		return
-	}
+	// Username Login name
+	Username string `json:"username"`
+}
 
-	ctx := r.Context()
+// PhotoInfo defines model for PhotoInfo.
+type PhotoInfo struct {
+	// Caption Free-text caption of the photo
+	Caption *string `json:"caption,omitempty"`
 
-	ctx = context.WithValue(ctx, SessionAuthScopes, []string{})
+	// Contributors Further uploaders who uploaded an identical copy
+	Contributors *[]string `json:"contributors,omitempty"`
 
-	r = r.WithContext(ctx)
+	// Date Upload timestamp
+	Date time.Time `json:"date"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ServeThumbnail(w, r, filename)
-	}))
+	// DeletedAt Time the photo was moved to the trash, only set for deleted photos
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// Event Event name associated with the photo
+	Event *string `json:"event,omitempty"`
 
-	handler.ServeHTTP(w, r)
-}
+	// Id Database ID of the photo
+	Id int64 `json:"id"`
 
-// UploadPhotos operation middleware
-func (siw *ServerInterfaceWrapper) UploadPhotos(w http.ResponseWriter, r *http.Request) {
+	// Name Filename of the photo
+	Name string `json:"name"`
 
-	ctx := r.Context()
+	// Path URL path to the photo
+	Path string `json:"path"`
 
-	ctx = context.WithValue(ctx, SessionAuthScopes, []string{})
+	// PhotoTime Time the photo was taken, if known
+	PhotoTime *time.Time `json:"photo_time,omitempty"`
 
-	r = r.WithContext(ctx)
+	// Tags Tags of the photo, in lower case
+	Tags *[]string `json:"tags,omitempty"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UploadPhotos(w, r)
-	}))
+	// Uploader Name of the person who uploaded the photo
+	Uploader string `json:"uploader"`
+}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+// PhotoList defines model for PhotoList.
+type PhotoList struct {
+	Photos []PhotoInfo `json:"photos"`
+}
 
-	handler.ServeHTTP(w, r)
+// PhotoNames defines model for PhotoNames.
+type PhotoNames struct {
+	// Names Photo filenames
+	Names []string `json:"names"`
 }
 
-// ServePhoto operation middleware
-func (siw *ServerInterfaceWrapper) ServePhoto(w http.ResponseWriter, r *http.Request) {
+// PhotoUpdate defines model for PhotoUpdate.
+type PhotoUpdate struct {
+	// Caption New caption, empty to clear it
+	Caption *string `json:"caption,omitempty"`
 
-	var err error
+	// Event New event name, empty to clear it
+	Event *string `json:"event,omitempty"`
 
-	// ------------- Path parameter "filename" -------------
-	var filename string
+	// Tags Replaces all tags, an empty list removes them
+	Tags *[]string `json:"tags,omitempty"`
 
-	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
-		return
-	}
+	// Uploader New uploader name, must not be empty
+	Uploader *string `json:"uploader,omitempty"`
+}
 
-	ctx := r.Context()
+// Role Permission level: viewers browse and download, contributors also upload
+// and edit their own photos, admins manage everything
+type Role string
 
-	ctx = context.WithValue(ctx, SessionAuthScopes, []string{})
+// Session defines model for Session.
+type Session struct {
+	// Created Login time
+	Created time.Time `json:"created"`
 
-	r = r.WithContext(ctx)
+	// Current Whether this is the session of the request
+	Current bool `json:"current"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ServePhoto(w, r, filename)
-	}))
+	// Expires Time the session ends unless revoked before
+	Expires time.Time `json:"expires"`
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// Id Database ID of the session
+	Id int64 `json:"id"`
 
-	handler.ServeHTTP(w, r)
-}
+	// Kind How the session logged in: with a user account, the shared gallery
+	// password, an event password or a share link
+	Kind SessionKind `json:"kind"`
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+	// LastIp Client address of the last request
+	LastIp string `json:"last_ip"`
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	// LastSeen Time of the last request, updated at most once a minute
+	LastSeen time.Time `json:"last_seen"`
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	// Name Username or share link label, empty for guests
+	Name string `json:"name"`
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	// ShareId ID of the share link (kind share)
+	ShareId *int64 `json:"share_id,omitempty"`
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	// UserAgent Browser of the last request
+	UserAgent string `json:"user_agent"`
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
+	// UserId ID of the user account (kind user)
+	UserId *int64 `json:"user_id,omitempty"`
 }
 
-type RequiredParamError struct {
-	ParamName string
-}
+// SessionKind How the session logged in: with a user account, the shared gallery
+// password, an event password or a share link
+type SessionKind string
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+// SessionList defines model for SessionList.
+type SessionList struct {
+	Sessions []Session `json:"sessions"`
 }
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+// Share defines model for Share.
+type Share struct {
+	// AlbumId ID of the shared album (kind album)
+	AlbumId *int64 `json:"album_id,omitempty"`
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	// AllowUpload Whether visitors may upload photos to the shared event
+	AllowUpload bool `json:"allow_upload"`
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
-}
+	// Created Creation time of the link
+	Created time.Time `json:"created"`
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
-}
+	// EventId ID of the shared event (kind event)
+	EventId *int64 `json:"event_id,omitempty"`
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
-}
+	// Expires Time the link stops working
+	Expires time.Time `json:"expires"`
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
+	// Id Database ID of the share link
+	Id int64 `json:"id"`
+
+	// Kind What a link shares: a photo, an event or an album to view, or only
+	// uploading photos without seeing any (upload)
+	Kind ShareKind `json:"kind"`
+
+	// Label Who the link was given to, recorded as uploader of their photos
+	Label string `json:"label"`
+
+	// Photo Filename of the shared photo (kind photo)
+	Photo *string `json:"photo,omitempty"`
+
+	// Url The link to hand out
+	Url string `json:"url"`
 }
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
+// ShareKind What a link shares: a photo, an event or an album to view, or only
+// uploading photos without seeing any (upload)
+type ShareKind string
+
+// ShareList defines model for ShareList.
+type ShareList struct {
+	Shares []Share `json:"shares"`
 }
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+// TagCount defines model for TagCount.
+type TagCount struct {
+	// Count Number of photos with the tag
+	Count int `json:"count"`
+
+	// Name The tag
+	Name string `json:"name"`
 }
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{})
+// TagList defines model for TagList.
+type TagList struct {
+	Tags []TagCount `json:"tags"`
 }
 
-type ChiServerOptions struct {
-	BaseURL          string
-	BaseRouter       chi.Router
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+// TokenScope What an API token may do: browse and download (read), also upload
+// photos (upload), or everything (admin)
+type TokenScope string
+
+// TrashList defines model for TrashList.
+type TrashList struct {
+	Photos []PhotoInfo `json:"photos"`
+
+	// RetentionDays Days a photo stays in the trash before it is purged, 0 if never
+	RetentionDays int `json:"retention_days"`
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseRouter: r,
-	})
+// UploadResult defines model for UploadResult.
+type UploadResult struct {
+	// Deduplicated Files identical to an existing photo of the same event, attributed to it instead of being stored again
+	Deduplicated []UploadedFile `json:"deduplicated"`
+
+	// Failed Filenames that could not be saved
+	Failed []string `json:"failed"`
+
+	// Uploaded Files stored as new photos
+	Uploaded []UploadedFile `json:"uploaded"`
 }
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: r,
-	})
+// UploadedFile defines model for UploadedFile.
+type UploadedFile struct {
+	// Filename Filename as sent by the client
+	Filename string `json:"filename"`
+
+	// Name Name of the stored photo, for duplicates the existing photo
+	Name string `json:"name"`
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
-	r := options.BaseRouter
+// User defines model for User.
+type User struct {
+	// Created Creation time of the account
+	Created time.Time `json:"created"`
 
-	if r == nil {
-		r = chi.NewRouter()
-	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
-	}
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
-	}
+	// Id Database ID of the user
+	Id int64 `json:"id"`
 
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/", wrapper.GetGallery)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/download-all", wrapper.DownloadAllPhotos)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/login", wrapper.GetLogin)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/login", wrapper.PostLogin)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/static/{filename}", wrapper.ServeStatic)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/thumbnails/{filename}", wrapper.ServeThumbnail)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/upload", wrapper.UploadPhotos)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/uploads/{filename}", wrapper.ServePhoto)
-	})
+	// Role Permission level: viewers browse and download, contributors also upload
+	// and edit their own photos, admins manage everything
+	Role Role `json:"role"`
 
-	return r
+	// Username Login name, also recorded as uploader of the user's photos
+	Username string `json:"username"`
 }
 
-type GetGalleryRequestObject struct {
-	Params GetGalleryParams
+// UserList defines model for UserList.
+type UserList struct {
+	Users []User `json:"users"`
 }
 
-type GetGalleryResponseObject interface {
-	VisitGetGalleryResponse(w http.ResponseWriter) error
-}
+// UserUpdate defines model for UserUpdate.
+type UserUpdate struct {
+	// Password New password, at least 8 characters
+	Password *string `json:"password,omitempty"`
 
-type GetGallery200TexthtmlResponse struct {
-	Body          io.Reader
-	ContentLength int64
+	// Role Permission level: viewers browse and download, contributors also upload
+	// and edit their own photos, admins manage everything
+	Role *Role `json:"role,omitempty"`
 }
 
-func (response GetGallery200TexthtmlResponse) VisitGetGalleryResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "text/html")
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
-	}
-	w.WriteHeader(200)
+// GetGalleryParams defines parameters for GetGallery.
+type GetGalleryParams struct {
+	// Event Filter photos by event name
+	Event *string `form:"event,omitempty" json:"event,omitempty"`
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
-	}
-	_, err := io.Copy(w, response.Body)
-	return err
-}
+	// Uploader Filter photos by uploader name
+	Uploader *string `form:"uploader,omitempty" json:"uploader,omitempty"`
 
-type GetGallery302Response struct {
-}
+	// Album Only photos of this album, in album order
+	Album *int64 `form:"album,omitempty" json:"album,omitempty"`
 
-func (response GetGallery302Response) VisitGetGalleryResponse(w http.ResponseWriter) error {
-	w.WriteHeader(302)
-	return nil
-}
+	// Tag Filter photos by tag, may be repeated
+	Tag *[]string `form:"tag,omitempty" json:"tag,omitempty"`
 
-type GetGallery500Response struct {
+	// TagMode Whether photos need any (default) or all of the given tags
+	TagMode *GetGalleryParamsTagMode `form:"tag_mode,omitempty" json:"tag_mode,omitempty"`
 }
 
-func (response GetGallery500Response) VisitGetGalleryResponse(w http.ResponseWriter) error {
-	w.WriteHeader(500)
-	return nil
-}
+// GetGalleryParamsTagMode defines parameters for GetGallery.
+type GetGalleryParamsTagMode string
 
-type DownloadAllPhotosRequestObject struct {
-	Params DownloadAllPhotosParams
-}
+// DownloadAllPhotosParams defines parameters for DownloadAllPhotos.
+type DownloadAllPhotosParams struct {
+	// Event Filter photos by event name
+	Event *string `form:"event,omitempty" json:"event,omitempty"`
 
-type DownloadAllPhotosResponseObject interface {
-	VisitDownloadAllPhotosResponse(w http.ResponseWriter) error
-}
+	// Uploader Filter photos by uploader name
+	Uploader *string `form:"uploader,omitempty" json:"uploader,omitempty"`
 
-type DownloadAllPhotos200ApplicationzipResponse struct {
-	Body          io.Reader
-	ContentLength int64
-}
+	// Album Only photos of this album, in album order
+	Album *int64 `form:"album,omitempty" json:"album,omitempty"`
 
-func (response DownloadAllPhotos200ApplicationzipResponse) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/zip")
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
-	}
-	w.WriteHeader(200)
+	// Tag Filter photos by tag, may be repeated
+	Tag *[]string `form:"tag,omitempty" json:"tag,omitempty"`
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
-	}
-	_, err := io.Copy(w, response.Body)
-	return err
-}
+	// TagMode Whether photos need any (default) or all of the given tags
+	TagMode *DownloadAllPhotosParamsTagMode `form:"tag_mode,omitempty" json:"tag_mode,omitempty"`
 
-type DownloadAllPhotos401Response struct {
+	// Format Version of photos converted on upload, e.g. HEIC, to put in the archive
+	Format *DownloadAllPhotosParamsFormat `form:"format,omitempty" json:"format,omitempty"`
 }
 
-func (response DownloadAllPhotos401Response) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
-	w.WriteHeader(401)
-	return nil
-}
+// DownloadAllPhotosParamsTagMode defines parameters for DownloadAllPhotos.
+type DownloadAllPhotosParamsTagMode string
 
-type DownloadAllPhotos404Response struct {
-}
+// DownloadAllPhotosParamsFormat defines parameters for DownloadAllPhotos.
+type DownloadAllPhotosParamsFormat string
 
-func (response DownloadAllPhotos404Response) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
-	w.WriteHeader(404)
-	return nil
-}
+// PostLoginFormdataBody defines parameters for PostLogin.
+type PostLoginFormdataBody struct {
+	// CsrfToken CSRF token embedded in the form, matching the gallery-csrf
+	// cookie; may be sent as X-CSRF-Token header instead. Not
+	// needed with an API token
+	CsrfToken *string `form:"csrf_token,omitempty" json:"csrf_token,omitempty"`
 
-type DownloadAllPhotos405Response struct {
-}
+	// Password Account password, shared gallery password or the password
+	// of an event; an event password only shows that event
+	Password string `form:"password" json:"password"`
 
-func (response DownloadAllPhotos405Response) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
-	w.WriteHeader(405)
-	return nil
+	// Username Account username; leave empty to log in as guest with the shared gallery password
+	Username *string `form:"username,omitempty" json:"username,omitempty"`
 }
 
-type DownloadAllPhotos500Response struct {
+// PostLogoutFormdataBody defines parameters for PostLogout.
+type PostLogoutFormdataBody struct {
+	// CsrfToken CSRF token embedded in the form, matching the gallery-csrf
+	// cookie; may be sent as X-CSRF-Token header instead
+	CsrfToken *string `form:"csrf_token,omitempty" json:"csrf_token,omitempty"`
 }
 
-func (response DownloadAllPhotos500Response) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
-	w.WriteHeader(500)
-	return nil
+// OpenShareParams defines parameters for OpenShare.
+type OpenShareParams struct {
+	// Event Event name to filter by, or to pre-fill on the upload page
+	Event *string `form:"event,omitempty" json:"event,omitempty"`
 }
 
-type GetLoginRequestObject struct {
-}
+// GetShareQRCodeParams defines parameters for GetShareQRCode.
+type GetShareQRCodeParams struct {
+	// Format Image format (default png)
+	Format *GetShareQRCodeParamsFormat `form:"format,omitempty" json:"format,omitempty"`
 
-type GetLoginResponseObject interface {
-	VisitGetLoginResponse(w http.ResponseWriter) error
-}
+	// Size Width and height of PNG images in pixels (default 512)
+	Size *int `form:"size,omitempty" json:"size,omitempty"`
 
-type GetLogin200TexthtmlResponse struct {
-	Body          io.Reader
-	ContentLength int64
+	// Event Event name added to the link, pre-filled on the upload page
+	Event *string `form:"event,omitempty" json:"event,omitempty"`
 }
 
-func (response GetLogin200TexthtmlResponse) VisitGetLoginResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "text/html")
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
-	}
-	w.WriteHeader(200)
+// GetShareQRCodeParamsFormat defines parameters for GetShareQRCode.
+type GetShareQRCodeParamsFormat string
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
-	}
-	_, err := io.Copy(w, response.Body)
-	return err
-}
+// SuggestTagsParams defines parameters for SuggestTags.
+type SuggestTagsParams struct {
+	// Q Beginning of the tag, empty for the most used tags
+	Q *string `form:"q,omitempty" json:"q,omitempty"`
 
-type PostLoginRequestObject struct {
-	Body *PostLoginFormdataRequestBody
+	// Limit Maximum number of suggestions (default 10)
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
-type PostLoginResponseObject interface {
-	VisitPostLoginResponse(w http.ResponseWriter) error
-}
+// UnlockEventsFormdataBody defines parameters for UnlockEvents.
+type UnlockEventsFormdataBody struct {
+	// CsrfToken CSRF token embedded in the form, matching the gallery-csrf
+	// cookie; may be sent as X-CSRF-Token header instead. Not
+	// needed with an API token
+	CsrfToken *string `form:"csrf_token,omitempty" json:"csrf_token,omitempty"`
 
-type PostLogin200TexthtmlResponse struct {
-	Body          io.Reader
-	ContentLength int64
+	// Password Password of one or more events
+	Password string `form:"password" json:"password"`
 }
 
-func (response PostLogin200TexthtmlResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "text/html")
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
-	}
-	w.WriteHeader(200)
+// UploadPhotosMultipartBody defines parameters for UploadPhotos.
+type UploadPhotosMultipartBody struct {
+	// CsrfToken CSRF token embedded in the form, matching the gallery-csrf
+	// cookie; may be sent as X-CSRF-Token header instead. Not
+	// needed with an API token
+	CsrfToken *string `json:"csrf_token,omitempty"`
+
+	// EventName Event name for organizing photos. Events with a password
+	// only take uploads from sessions that unlocked them; share
+	// links of an event always upload to their event
+	EventName *string `json:"event_name,omitempty"`
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
-	}
-	_, err := io.Copy(w, response.Body)
-	return err
+	// Photos Photo files to upload
+	Photos []openapi_types.File `json:"photos"`
 }
 
-type PostLogin302Response struct {
+// ServePhotoParams defines parameters for ServePhoto.
+type ServePhotoParams struct {
+	// Format Serve the JPEG version of a photo converted on upload, e.g. HEIC, or its copy rotated for the EXIF orientation (ROTATED_DISPLAY_COPIES) instead of the original if it has one
+	Format *ServePhotoParamsFormat `form:"format,omitempty" json:"format,omitempty"`
 }
 
-func (response PostLogin302Response) VisitPostLoginResponse(w http.ResponseWriter) error {
-	w.WriteHeader(302)
-	return nil
+// ServePhotoParamsFormat defines parameters for ServePhoto.
+type ServePhotoParamsFormat string
+
+// CreateAlbumJSONRequestBody defines body for CreateAlbum for application/json ContentType.
+type CreateAlbumJSONRequestBody = AlbumInput
+
+// UpdateAlbumJSONRequestBody defines body for UpdateAlbum for application/json ContentType.
+type UpdateAlbumJSONRequestBody = AlbumInput
+
+// AddAlbumPhotosJSONRequestBody defines body for AddAlbumPhotos for application/json ContentType.
+type AddAlbumPhotosJSONRequestBody = PhotoNames
+
+// ReorderAlbumPhotosJSONRequestBody defines body for ReorderAlbumPhotos for application/json ContentType.
+type ReorderAlbumPhotosJSONRequestBody = PhotoNames
+
+// ResolveDuplicatesJSONRequestBody defines body for ResolveDuplicates for application/json ContentType.
+type ResolveDuplicatesJSONRequestBody = DuplicateResolution
+
+// UpdateEventJSONRequestBody defines body for UpdateEvent for application/json ContentType.
+type UpdateEventJSONRequestBody = EventUpdate
+
+// MergeEventsJSONRequestBody defines body for MergeEvents for application/json ContentType.
+type MergeEventsJSONRequestBody = EventMerge
+
+// PostLoginFormdataRequestBody defines body for PostLogin for application/x-www-form-urlencoded ContentType.
+type PostLoginFormdataRequestBody PostLoginFormdataBody
+
+// PostLogoutFormdataRequestBody defines body for PostLogout for application/x-www-form-urlencoded ContentType.
+type PostLogoutFormdataRequestBody PostLogoutFormdataBody
+
+// UpdatePhotosJSONRequestBody defines body for UpdatePhotos for application/json ContentType.
+type UpdatePhotosJSONRequestBody = BulkPhotoUpdate
+
+// UpdatePhotoJSONRequestBody defines body for UpdatePhoto for application/json ContentType.
+type UpdatePhotoJSONRequestBody = PhotoUpdate
+
+// CreateShareJSONRequestBody defines body for CreateShare for application/json ContentType.
+type CreateShareJSONRequestBody = NewShare
+
+// CreateAPITokenJSONRequestBody defines body for CreateAPIToken for application/json ContentType.
+type CreateAPITokenJSONRequestBody = NewAPIToken
+
+// UnlockEventsFormdataRequestBody defines body for UnlockEvents for application/x-www-form-urlencoded ContentType.
+type UnlockEventsFormdataRequestBody UnlockEventsFormdataBody
+
+// UploadPhotosMultipartRequestBody defines body for UploadPhotos for multipart/form-data ContentType.
+type UploadPhotosMultipartRequestBody UploadPhotosMultipartBody
+
+// CreateUserJSONRequestBody defines body for CreateUser for application/json ContentType.
+type CreateUserJSONRequestBody = NewUser
+
+// UpdateUserJSONRequestBody defines body for UpdateUser for application/json ContentType.
+type UpdateUserJSONRequestBody = UserUpdate
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Gallery page
+	// (GET /)
+	GetGallery(w http.ResponseWriter, r *http.Request, params GetGalleryParams)
+	// List albums
+	// (GET /albums)
+	ListAlbums(w http.ResponseWriter, r *http.Request)
+	// Create an album
+	// (POST /albums)
+	CreateAlbum(w http.ResponseWriter, r *http.Request)
+	// Delete an album
+	// (DELETE /albums/{id})
+	DeleteAlbum(w http.ResponseWriter, r *http.Request, id int64)
+	// Get an album
+	// (GET /albums/{id})
+	GetAlbum(w http.ResponseWriter, r *http.Request, id int64)
+	// Rename an album
+	// (PATCH /albums/{id})
+	UpdateAlbum(w http.ResponseWriter, r *http.Request, id int64)
+	// Add photos to an album
+	// (POST /albums/{id}/photos)
+	AddAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64)
+	// Reorder the photos of an album
+	// (PUT /albums/{id}/photos)
+	ReorderAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64)
+	// Remove a photo from an album
+	// (DELETE /albums/{id}/photos/{filename})
+	RemoveAlbumPhoto(w http.ResponseWriter, r *http.Request, id int64, filename string)
+	// Download all photos as ZIP
+	// (GET /download-all)
+	DownloadAllPhotos(w http.ResponseWriter, r *http.Request, params DownloadAllPhotosParams)
+	// List near-duplicate photos
+	// (GET /duplicates)
+	ListDuplicates(w http.ResponseWriter, r *http.Request)
+	// Resolve a near-duplicate cluster
+	// (POST /duplicates/resolve)
+	ResolveDuplicates(w http.ResponseWriter, r *http.Request)
+	// Event overview
+	// (GET /events)
+	ListEvents(w http.ResponseWriter, r *http.Request)
+	// Edit an event
+	// (PATCH /events/{id})
+	UpdateEvent(w http.ResponseWriter, r *http.Request, id int64)
+	// Merge events
+	// (POST /events/{id}/merge)
+	MergeEvents(w http.ResponseWriter, r *http.Request, id int64)
+	// Login page
+	// (GET /login)
+	GetLogin(w http.ResponseWriter, r *http.Request)
+	// Authenticate user
+	// (POST /login)
+	PostLogin(w http.ResponseWriter, r *http.Request)
+	// Log out
+	// (POST /logout)
+	PostLogout(w http.ResponseWriter, r *http.Request)
+	// Edit metadata of several photos
+	// (PATCH /photos)
+	UpdatePhotos(w http.ResponseWriter, r *http.Request)
+	// Delete a photo
+	// (DELETE /photos/{filename})
+	DeletePhoto(w http.ResponseWriter, r *http.Request, filename string)
+	// Edit photo metadata
+	// (PATCH /photos/{filename})
+	UpdatePhoto(w http.ResponseWriter, r *http.Request, filename string)
+	// Serve photo rendition
+	// (GET /renditions/{size}/{filename})
+	ServeRendition(w http.ResponseWriter, r *http.Request, size int, filename string)
+	// List sessions
+	// (GET /sessions)
+	ListSessions(w http.ResponseWriter, r *http.Request)
+	// Revoke a session
+	// (DELETE /sessions/{id})
+	RevokeSession(w http.ResponseWriter, r *http.Request, id int64)
+	// Open a share link
+	// (GET /share/{token})
+	OpenShare(w http.ResponseWriter, r *http.Request, token string, params OpenShareParams)
+	// List share links
+	// (GET /shares)
+	ListShares(w http.ResponseWriter, r *http.Request)
+	// Create a share link
+	// (POST /shares)
+	CreateShare(w http.ResponseWriter, r *http.Request)
+	// Revoke a share link
+	// (DELETE /shares/{id})
+	DeleteShare(w http.ResponseWriter, r *http.Request, id int64)
+	// Share link QR code
+	// (GET /shares/{id}/qr)
+	GetShareQRCode(w http.ResponseWriter, r *http.Request, id int64, params GetShareQRCodeParams)
+	// Serve static assets
+	// (GET /static/{filename})
+	ServeStatic(w http.ResponseWriter, r *http.Request, filename string)
+	// Suggest tags
+	// (GET /tags)
+	SuggestTags(w http.ResponseWriter, r *http.Request, params SuggestTagsParams)
+	// Serve photo thumbnail
+	// (GET /thumbnails/{filename})
+	ServeThumbnail(w http.ResponseWriter, r *http.Request, filename string)
+	// List API tokens
+	// (GET /tokens)
+	ListAPITokens(w http.ResponseWriter, r *http.Request)
+	// Create an API token
+	// (POST /tokens)
+	CreateAPIToken(w http.ResponseWriter, r *http.Request)
+	// Revoke an API token
+	// (DELETE /tokens/{id})
+	RevokeAPIToken(w http.ResponseWriter, r *http.Request, id int64)
+	// List deleted photos
+	// (GET /trash)
+	ListTrash(w http.ResponseWriter, r *http.Request)
+	// Purge a deleted photo
+	// (DELETE /trash/{filename})
+	PurgePhoto(w http.ResponseWriter, r *http.Request, filename string)
+	// Restore a deleted photo
+	// (POST /trash/{filename}/restore)
+	RestorePhoto(w http.ResponseWriter, r *http.Request, filename string)
+	// Unlock events
+	// (POST /unlock)
+	UnlockEvents(w http.ResponseWriter, r *http.Request)
+	// Upload photos
+	// (POST /upload)
+	UploadPhotos(w http.ResponseWriter, r *http.Request)
+	// Serve uploaded photo
+	// (GET /uploads/{filename})
+	ServePhoto(w http.ResponseWriter, r *http.Request, filename string, params ServePhotoParams)
+	// List users
+	// (GET /users)
+	ListUsers(w http.ResponseWriter, r *http.Request)
+	// Create a user
+	// (POST /users)
+	CreateUser(w http.ResponseWriter, r *http.Request)
+	// Delete a user
+	// (DELETE /users/{id})
+	DeleteUser(w http.ResponseWriter, r *http.Request, id int64)
+	// Update a user
+	// (PATCH /users/{id})
+	UpdateUser(w http.ResponseWriter, r *http.Request, id int64)
 }
 
-type ServeStaticRequestObject struct {
-	Filename string `json:"filename"`
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// Gallery page
+// (GET /)
+func (_ Unimplemented) GetGallery(w http.ResponseWriter, r *http.Request, params GetGalleryParams) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServeStaticResponseObject interface {
-	VisitServeStaticResponse(w http.ResponseWriter) error
+// List albums
+// (GET /albums)
+func (_ Unimplemented) ListAlbums(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServeStatic200AsteriskResponse struct {
-	Body          io.Reader
-	ContentType   string
-	ContentLength int64
+// Create an album
+// (POST /albums)
+func (_ Unimplemented) CreateAlbum(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response ServeStatic200AsteriskResponse) VisitServeStaticResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", response.ContentType)
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
-	}
-	w.WriteHeader(200)
+// Delete an album
+// (DELETE /albums/{id})
+func (_ Unimplemented) DeleteAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
-	}
-	_, err := io.Copy(w, response.Body)
-	return err
+// Get an album
+// (GET /albums/{id})
+func (_ Unimplemented) GetAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServeStatic404Response struct {
+// Rename an album
+// (PATCH /albums/{id})
+func (_ Unimplemented) UpdateAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response ServeStatic404Response) VisitServeStaticResponse(w http.ResponseWriter) error {
-	w.WriteHeader(404)
-	return nil
+// Add photos to an album
+// (POST /albums/{id}/photos)
+func (_ Unimplemented) AddAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServeThumbnailRequestObject struct {
-	Filename string `json:"filename"`
+// Reorder the photos of an album
+// (PUT /albums/{id}/photos)
+func (_ Unimplemented) ReorderAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServeThumbnailResponseObject interface {
-	VisitServeThumbnailResponse(w http.ResponseWriter) error
+// Remove a photo from an album
+// (DELETE /albums/{id}/photos/{filename})
+func (_ Unimplemented) RemoveAlbumPhoto(w http.ResponseWriter, r *http.Request, id int64, filename string) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServeThumbnail200ImageResponse struct {
-	Body          io.Reader
-	ContentType   string
-	ContentLength int64
+// Download all photos as ZIP
+// (GET /download-all)
+func (_ Unimplemented) DownloadAllPhotos(w http.ResponseWriter, r *http.Request, params DownloadAllPhotosParams) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response ServeThumbnail200ImageResponse) VisitServeThumbnailResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", response.ContentType)
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
-	}
-	w.WriteHeader(200)
+// List near-duplicate photos
+// (GET /duplicates)
+func (_ Unimplemented) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
-	}
-	_, err := io.Copy(w, response.Body)
-	return err
+// Resolve a near-duplicate cluster
+// (POST /duplicates/resolve)
+func (_ Unimplemented) ResolveDuplicates(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServeThumbnail401Response struct {
+// Event overview
+// (GET /events)
+func (_ Unimplemented) ListEvents(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response ServeThumbnail401Response) VisitServeThumbnailResponse(w http.ResponseWriter) error {
-	w.WriteHeader(401)
-	return nil
+// Edit an event
+// (PATCH /events/{id})
+func (_ Unimplemented) UpdateEvent(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServeThumbnail404Response struct {
+// Merge events
+// (POST /events/{id}/merge)
+func (_ Unimplemented) MergeEvents(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response ServeThumbnail404Response) VisitServeThumbnailResponse(w http.ResponseWriter) error {
-	w.WriteHeader(404)
-	return nil
+// Login page
+// (GET /login)
+func (_ Unimplemented) GetLogin(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type UploadPhotosRequestObject struct {
-	Body *multipart.Reader
+// Authenticate user
+// (POST /login)
+func (_ Unimplemented) PostLogin(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type UploadPhotosResponseObject interface {
-	VisitUploadPhotosResponse(w http.ResponseWriter) error
+// Log out
+// (POST /logout)
+func (_ Unimplemented) PostLogout(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type UploadPhotos302Response struct {
+// Edit metadata of several photos
+// (PATCH /photos)
+func (_ Unimplemented) UpdatePhotos(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response UploadPhotos302Response) VisitUploadPhotosResponse(w http.ResponseWriter) error {
-	w.WriteHeader(302)
-	return nil
+// Delete a photo
+// (DELETE /photos/{filename})
+func (_ Unimplemented) DeletePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type UploadPhotos400Response struct {
+// Edit photo metadata
+// (PATCH /photos/{filename})
+func (_ Unimplemented) UpdatePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response UploadPhotos400Response) VisitUploadPhotosResponse(w http.ResponseWriter) error {
-	w.WriteHeader(400)
-	return nil
+// Serve photo rendition
+// (GET /renditions/{size}/{filename})
+func (_ Unimplemented) ServeRendition(w http.ResponseWriter, r *http.Request, size int, filename string) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type UploadPhotos401Response struct {
+// List sessions
+// (GET /sessions)
+func (_ Unimplemented) ListSessions(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response UploadPhotos401Response) VisitUploadPhotosResponse(w http.ResponseWriter) error {
-	w.WriteHeader(401)
-	return nil
+// Revoke a session
+// (DELETE /sessions/{id})
+func (_ Unimplemented) RevokeSession(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type UploadPhotos405Response struct {
+// Open a share link
+// (GET /share/{token})
+func (_ Unimplemented) OpenShare(w http.ResponseWriter, r *http.Request, token string, params OpenShareParams) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response UploadPhotos405Response) VisitUploadPhotosResponse(w http.ResponseWriter) error {
-	w.WriteHeader(405)
-	return nil
+// List share links
+// (GET /shares)
+func (_ Unimplemented) ListShares(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServePhotoRequestObject struct {
-	Filename string `json:"filename"`
+// Create a share link
+// (POST /shares)
+func (_ Unimplemented) CreateShare(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServePhotoResponseObject interface {
-	VisitServePhotoResponse(w http.ResponseWriter) error
+// Revoke a share link
+// (DELETE /shares/{id})
+func (_ Unimplemented) DeleteShare(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-type ServePhoto200ImageResponse struct {
-	Body          io.Reader
-	ContentType   string
-	ContentLength int64
+// Share link QR code
+// (GET /shares/{id}/qr)
+func (_ Unimplemented) GetShareQRCode(w http.ResponseWriter, r *http.Request, id int64, params GetShareQRCodeParams) {
+	w.WriteHeader(http.StatusNotImplemented)
 }
 
-func (response ServePhoto200ImageResponse) VisitServePhotoResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", response.ContentType)
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
-	}
-	w.WriteHeader(200)
+// Serve static assets
+// (GET /static/{filename})
+func (_ Unimplemented) ServeStatic(w http.ResponseWriter, r *http.Request, filename string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Suggest tags
+// (GET /tags)
+func (_ Unimplemented) SuggestTags(w http.ResponseWriter, r *http.Request, params SuggestTagsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Serve photo thumbnail
+// (GET /thumbnails/{filename})
+func (_ Unimplemented) ServeThumbnail(w http.ResponseWriter, r *http.Request, filename string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List API tokens
+// (GET /tokens)
+func (_ Unimplemented) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Create an API token
+// (POST /tokens)
+func (_ Unimplemented) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Revoke an API token
+// (DELETE /tokens/{id})
+func (_ Unimplemented) RevokeAPIToken(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List deleted photos
+// (GET /trash)
+func (_ Unimplemented) ListTrash(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Purge a deleted photo
+// (DELETE /trash/{filename})
+func (_ Unimplemented) PurgePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Restore a deleted photo
+// (POST /trash/{filename}/restore)
+func (_ Unimplemented) RestorePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Unlock events
+// (POST /unlock)
+func (_ Unimplemented) UnlockEvents(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Upload photos
+// (POST /upload)
+func (_ Unimplemented) UploadPhotos(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Serve uploaded photo
+// (GET /uploads/{filename})
+func (_ Unimplemented) ServePhoto(w http.ResponseWriter, r *http.Request, filename string, params ServePhotoParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List users
+// (GET /users)
+func (_ Unimplemented) ListUsers(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Create a user
+// (POST /users)
+func (_ Unimplemented) CreateUser(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Delete a user
+// (DELETE /users/{id})
+func (_ Unimplemented) DeleteUser(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Update a user
+// (PATCH /users/{id})
+func (_ Unimplemented) UpdateUser(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetGallery operation middleware
+func (siw *ServerInterfaceWrapper) GetGallery(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGalleryParams
+
+	// ------------- Optional query parameter "event" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "event", r.URL.Query(), &params.Event)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "event", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "uploader" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "uploader", r.URL.Query(), &params.Uploader)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "uploader", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "album" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "album", r.URL.Query(), &params.Album)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "album", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag_mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag_mode", r.URL.Query(), &params.TagMode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_mode", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGallery(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListAlbums operation middleware
+func (siw *ServerInterfaceWrapper) ListAlbums(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{"browse"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAlbums(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateAlbum operation middleware
+func (siw *ServerInterfaceWrapper) CreateAlbum(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateAlbum(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAlbum operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAlbum(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAlbum operation middleware
+func (siw *ServerInterfaceWrapper) GetAlbum(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{"browse"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAlbum(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateAlbum operation middleware
+func (siw *ServerInterfaceWrapper) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateAlbum(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AddAlbumPhotos operation middleware
+func (siw *ServerInterfaceWrapper) AddAlbumPhotos(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AddAlbumPhotos(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ReorderAlbumPhotos operation middleware
+func (siw *ServerInterfaceWrapper) ReorderAlbumPhotos(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReorderAlbumPhotos(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RemoveAlbumPhoto operation middleware
+func (siw *ServerInterfaceWrapper) RemoveAlbumPhoto(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RemoveAlbumPhoto(w, r, id, filename)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DownloadAllPhotos operation middleware
+func (siw *ServerInterfaceWrapper) DownloadAllPhotos(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{"browse"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DownloadAllPhotosParams
+
+	// ------------- Optional query parameter "event" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "event", r.URL.Query(), &params.Event)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "event", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "uploader" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "uploader", r.URL.Query(), &params.Uploader)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "uploader", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "album" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "album", r.URL.Query(), &params.Album)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "album", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag_mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag_mode", r.URL.Query(), &params.TagMode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_mode", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DownloadAllPhotos(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListDuplicates operation middleware
+func (siw *ServerInterfaceWrapper) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListDuplicates(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ResolveDuplicates operation middleware
+func (siw *ServerInterfaceWrapper) ResolveDuplicates(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResolveDuplicates(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListEvents operation middleware
+func (siw *ServerInterfaceWrapper) ListEvents(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{"browse"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListEvents(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateEvent operation middleware
+func (siw *ServerInterfaceWrapper) UpdateEvent(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateEvent(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// MergeEvents operation middleware
+func (siw *ServerInterfaceWrapper) MergeEvents(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.MergeEvents(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetLogin operation middleware
+func (siw *ServerInterfaceWrapper) GetLogin(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetLogin(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostLogin operation middleware
+func (siw *ServerInterfaceWrapper) PostLogin(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostLogin(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostLogout operation middleware
+func (siw *ServerInterfaceWrapper) PostLogout(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostLogout(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdatePhotos operation middleware
+func (siw *ServerInterfaceWrapper) UpdatePhotos(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"contributor"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"contributor"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdatePhotos(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeletePhoto operation middleware
+func (siw *ServerInterfaceWrapper) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"contributor"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"contributor"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeletePhoto(w, r, filename)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdatePhoto operation middleware
+func (siw *ServerInterfaceWrapper) UpdatePhoto(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"contributor"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"contributor"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdatePhoto(w, r, filename)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ServeRendition operation middleware
+func (siw *ServerInterfaceWrapper) ServeRendition(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "size" -------------
+	var size int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "size", chi.URLParam(r, "size"), &size, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "size", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{"browse"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ServeRendition(w, r, size, filename)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListSessions operation middleware
+func (siw *ServerInterfaceWrapper) ListSessions(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListSessions(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RevokeSession operation middleware
+func (siw *ServerInterfaceWrapper) RevokeSession(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeSession(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// OpenShare operation middleware
+func (siw *ServerInterfaceWrapper) OpenShare(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token", chi.URLParam(r, "token"), &token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params OpenShareParams
+
+	// ------------- Optional query parameter "event" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "event", r.URL.Query(), &params.Event)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "event", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.OpenShare(w, r, token, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListShares operation middleware
+func (siw *ServerInterfaceWrapper) ListShares(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListShares(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateShare operation middleware
+func (siw *ServerInterfaceWrapper) CreateShare(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateShare(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteShare operation middleware
+func (siw *ServerInterfaceWrapper) DeleteShare(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteShare(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetShareQRCode operation middleware
+func (siw *ServerInterfaceWrapper) GetShareQRCode(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetShareQRCodeParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "size", r.URL.Query(), &params.Size)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "size", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "event" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "event", r.URL.Query(), &params.Event)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "event", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetShareQRCode(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ServeStatic operation middleware
+func (siw *ServerInterfaceWrapper) ServeStatic(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ServeStatic(w, r, filename)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SuggestTags operation middleware
+func (siw *ServerInterfaceWrapper) SuggestTags(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{"browse"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SuggestTagsParams
+
+	// ------------- Optional query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SuggestTags(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ServeThumbnail operation middleware
+func (siw *ServerInterfaceWrapper) ServeThumbnail(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{"browse"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ServeThumbnail(w, r, filename)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListAPITokens operation middleware
+func (siw *ServerInterfaceWrapper) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAPITokens(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateAPIToken operation middleware
+func (siw *ServerInterfaceWrapper) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateAPIToken(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RevokeAPIToken operation middleware
+func (siw *ServerInterfaceWrapper) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeAPIToken(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListTrash operation middleware
+func (siw *ServerInterfaceWrapper) ListTrash(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListTrash(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PurgePhoto operation middleware
+func (siw *ServerInterfaceWrapper) PurgePhoto(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PurgePhoto(w, r, filename)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RestorePhoto operation middleware
+func (siw *ServerInterfaceWrapper) RestorePhoto(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RestorePhoto(w, r, filename)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UnlockEvents operation middleware
+func (siw *ServerInterfaceWrapper) UnlockEvents(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnlockEvents(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UploadPhotos operation middleware
+func (siw *ServerInterfaceWrapper) UploadPhotos(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"contributor"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"contributor"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{"upload"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UploadPhotos(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ServePhoto operation middleware
+func (siw *ServerInterfaceWrapper) ServePhoto(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "filename" -------------
+	var filename string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "filename", chi.URLParam(r, "filename"), &filename, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filename", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"viewer"})
+
+	ctx = context.WithValue(ctx, ShareAuthScopes, []string{"browse"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ServePhotoParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ServePhoto(w, r, filename, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListUsers operation middleware
+func (siw *ServerInterfaceWrapper) ListUsers(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListUsers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateUser operation middleware
+func (siw *ServerInterfaceWrapper) CreateUser(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateUser(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteUser operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteUser(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateUser operation middleware
+func (siw *ServerInterfaceWrapper) UpdateUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SessionAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateUser(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/", wrapper.GetGallery)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/albums", wrapper.ListAlbums)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/albums", wrapper.CreateAlbum)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/albums/{id}", wrapper.DeleteAlbum)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/albums/{id}", wrapper.GetAlbum)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/albums/{id}", wrapper.UpdateAlbum)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/albums/{id}/photos", wrapper.AddAlbumPhotos)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/albums/{id}/photos", wrapper.ReorderAlbumPhotos)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/albums/{id}/photos/{filename}", wrapper.RemoveAlbumPhoto)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/download-all", wrapper.DownloadAllPhotos)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/duplicates", wrapper.ListDuplicates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/duplicates/resolve", wrapper.ResolveDuplicates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/events", wrapper.ListEvents)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/events/{id}", wrapper.UpdateEvent)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/events/{id}/merge", wrapper.MergeEvents)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/login", wrapper.GetLogin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/login", wrapper.PostLogin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/logout", wrapper.PostLogout)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/photos", wrapper.UpdatePhotos)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/photos/{filename}", wrapper.DeletePhoto)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/photos/{filename}", wrapper.UpdatePhoto)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/renditions/{size}/{filename}", wrapper.ServeRendition)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sessions", wrapper.ListSessions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/sessions/{id}", wrapper.RevokeSession)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/share/{token}", wrapper.OpenShare)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/shares", wrapper.ListShares)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/shares", wrapper.CreateShare)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/shares/{id}", wrapper.DeleteShare)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/shares/{id}/qr", wrapper.GetShareQRCode)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/static/{filename}", wrapper.ServeStatic)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/tags", wrapper.SuggestTags)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/thumbnails/{filename}", wrapper.ServeThumbnail)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/tokens", wrapper.ListAPITokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/tokens", wrapper.CreateAPIToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/tokens/{id}", wrapper.RevokeAPIToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/trash", wrapper.ListTrash)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/trash/{filename}", wrapper.PurgePhoto)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/trash/{filename}/restore", wrapper.RestorePhoto)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/unlock", wrapper.UnlockEvents)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/upload", wrapper.UploadPhotos)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/uploads/{filename}", wrapper.ServePhoto)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users", wrapper.ListUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users", wrapper.CreateUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/users/{id}", wrapper.DeleteUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/users/{id}", wrapper.UpdateUser)
+	})
+
+	return r
+}
+
+type GetGalleryRequestObject struct {
+	Params GetGalleryParams
+}
+
+type GetGalleryResponseObject interface {
+	VisitGetGalleryResponse(w http.ResponseWriter) error
+}
+
+type GetGallery200TexthtmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetGallery200TexthtmlResponse) VisitGetGalleryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetGallery302Response struct {
+}
+
+func (response GetGallery302Response) VisitGetGalleryResponse(w http.ResponseWriter) error {
+	w.WriteHeader(302)
+	return nil
+}
+
+type GetGallery500Response struct {
+}
+
+func (response GetGallery500Response) VisitGetGalleryResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type ListAlbumsRequestObject struct {
+}
+
+type ListAlbumsResponseObject interface {
+	VisitListAlbumsResponse(w http.ResponseWriter) error
+}
+
+type ListAlbums200JSONResponse AlbumList
+
+func (response ListAlbums200JSONResponse) VisitListAlbumsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAlbums401Response struct {
+}
+
+func (response ListAlbums401Response) VisitListAlbumsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ListAlbums500Response struct {
+}
+
+func (response ListAlbums500Response) VisitListAlbumsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type CreateAlbumRequestObject struct {
+	Body *CreateAlbumJSONRequestBody
+}
+
+type CreateAlbumResponseObject interface {
+	VisitCreateAlbumResponse(w http.ResponseWriter) error
+}
+
+type CreateAlbum201JSONResponse Album
+
+func (response CreateAlbum201JSONResponse) VisitCreateAlbumResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAlbum400Response struct {
+}
+
+func (response CreateAlbum400Response) VisitCreateAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type CreateAlbum401Response struct {
+}
+
+func (response CreateAlbum401Response) VisitCreateAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type CreateAlbum403Response struct {
+}
+
+func (response CreateAlbum403Response) VisitCreateAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type CreateAlbum500Response struct {
+}
+
+func (response CreateAlbum500Response) VisitCreateAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type DeleteAlbumRequestObject struct {
+	Id int64 `json:"id"`
+}
+
+type DeleteAlbumResponseObject interface {
+	VisitDeleteAlbumResponse(w http.ResponseWriter) error
+}
+
+type DeleteAlbum204Response struct {
+}
+
+func (response DeleteAlbum204Response) VisitDeleteAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteAlbum401Response struct {
+}
+
+func (response DeleteAlbum401Response) VisitDeleteAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteAlbum403Response struct {
+}
+
+func (response DeleteAlbum403Response) VisitDeleteAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteAlbum404Response struct {
+}
+
+func (response DeleteAlbum404Response) VisitDeleteAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteAlbum500Response struct {
+}
+
+func (response DeleteAlbum500Response) VisitDeleteAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type GetAlbumRequestObject struct {
+	Id int64 `json:"id"`
+}
+
+type GetAlbumResponseObject interface {
+	VisitGetAlbumResponse(w http.ResponseWriter) error
+}
+
+type GetAlbum200JSONResponse AlbumDetail
+
+func (response GetAlbum200JSONResponse) VisitGetAlbumResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAlbum401Response struct {
+}
+
+func (response GetAlbum401Response) VisitGetAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetAlbum404Response struct {
+}
+
+func (response GetAlbum404Response) VisitGetAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetAlbum500Response struct {
+}
+
+func (response GetAlbum500Response) VisitGetAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type UpdateAlbumRequestObject struct {
+	Id   int64 `json:"id"`
+	Body *UpdateAlbumJSONRequestBody
+}
+
+type UpdateAlbumResponseObject interface {
+	VisitUpdateAlbumResponse(w http.ResponseWriter) error
+}
+
+type UpdateAlbum200JSONResponse Album
+
+func (response UpdateAlbum200JSONResponse) VisitUpdateAlbumResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateAlbum400Response struct {
+}
+
+func (response UpdateAlbum400Response) VisitUpdateAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type UpdateAlbum401Response struct {
+}
+
+func (response UpdateAlbum401Response) VisitUpdateAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type UpdateAlbum403Response struct {
+}
+
+func (response UpdateAlbum403Response) VisitUpdateAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type UpdateAlbum404Response struct {
+}
+
+func (response UpdateAlbum404Response) VisitUpdateAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type UpdateAlbum500Response struct {
+}
+
+func (response UpdateAlbum500Response) VisitUpdateAlbumResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type AddAlbumPhotosRequestObject struct {
+	Id   int64 `json:"id"`
+	Body *AddAlbumPhotosJSONRequestBody
+}
+
+type AddAlbumPhotosResponseObject interface {
+	VisitAddAlbumPhotosResponse(w http.ResponseWriter) error
+}
+
+type AddAlbumPhotos200JSONResponse Album
+
+func (response AddAlbumPhotos200JSONResponse) VisitAddAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AddAlbumPhotos400Response struct {
+}
+
+func (response AddAlbumPhotos400Response) VisitAddAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type AddAlbumPhotos401Response struct {
+}
+
+func (response AddAlbumPhotos401Response) VisitAddAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type AddAlbumPhotos403Response struct {
+}
+
+func (response AddAlbumPhotos403Response) VisitAddAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type AddAlbumPhotos404Response struct {
+}
+
+func (response AddAlbumPhotos404Response) VisitAddAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type AddAlbumPhotos500Response struct {
+}
+
+func (response AddAlbumPhotos500Response) VisitAddAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type ReorderAlbumPhotosRequestObject struct {
+	Id   int64 `json:"id"`
+	Body *ReorderAlbumPhotosJSONRequestBody
+}
+
+type ReorderAlbumPhotosResponseObject interface {
+	VisitReorderAlbumPhotosResponse(w http.ResponseWriter) error
+}
+
+type ReorderAlbumPhotos204Response struct {
+}
+
+func (response ReorderAlbumPhotos204Response) VisitReorderAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type ReorderAlbumPhotos400Response struct {
+}
+
+func (response ReorderAlbumPhotos400Response) VisitReorderAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type ReorderAlbumPhotos401Response struct {
+}
+
+func (response ReorderAlbumPhotos401Response) VisitReorderAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ReorderAlbumPhotos403Response struct {
+}
+
+func (response ReorderAlbumPhotos403Response) VisitReorderAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type ReorderAlbumPhotos404Response struct {
+}
+
+func (response ReorderAlbumPhotos404Response) VisitReorderAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type ReorderAlbumPhotos500Response struct {
+}
+
+func (response ReorderAlbumPhotos500Response) VisitReorderAlbumPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type RemoveAlbumPhotoRequestObject struct {
+	Id       int64  `json:"id"`
+	Filename string `json:"filename"`
+}
+
+type RemoveAlbumPhotoResponseObject interface {
+	VisitRemoveAlbumPhotoResponse(w http.ResponseWriter) error
+}
+
+type RemoveAlbumPhoto204Response struct {
+}
+
+func (response RemoveAlbumPhoto204Response) VisitRemoveAlbumPhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RemoveAlbumPhoto401Response struct {
+}
+
+func (response RemoveAlbumPhoto401Response) VisitRemoveAlbumPhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type RemoveAlbumPhoto403Response struct {
+}
+
+func (response RemoveAlbumPhoto403Response) VisitRemoveAlbumPhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type RemoveAlbumPhoto404Response struct {
+}
+
+func (response RemoveAlbumPhoto404Response) VisitRemoveAlbumPhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type RemoveAlbumPhoto500Response struct {
+}
+
+func (response RemoveAlbumPhoto500Response) VisitRemoveAlbumPhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type DownloadAllPhotosRequestObject struct {
+	Params DownloadAllPhotosParams
+}
+
+type DownloadAllPhotosResponseObject interface {
+	VisitDownloadAllPhotosResponse(w http.ResponseWriter) error
+}
+
+type DownloadAllPhotos200ApplicationzipResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response DownloadAllPhotos200ApplicationzipResponse) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/zip")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type DownloadAllPhotos401Response struct {
+}
+
+func (response DownloadAllPhotos401Response) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DownloadAllPhotos404Response struct {
+}
+
+func (response DownloadAllPhotos404Response) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DownloadAllPhotos405Response struct {
+}
+
+func (response DownloadAllPhotos405Response) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(405)
+	return nil
+}
+
+type DownloadAllPhotos500Response struct {
+}
+
+func (response DownloadAllPhotos500Response) VisitDownloadAllPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type ListDuplicatesRequestObject struct {
+}
+
+type ListDuplicatesResponseObject interface {
+	VisitListDuplicatesResponse(w http.ResponseWriter) error
+}
+
+type ListDuplicates200JSONResponse DuplicateClusters
+
+func (response ListDuplicates200JSONResponse) VisitListDuplicatesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListDuplicates401Response struct {
+}
+
+func (response ListDuplicates401Response) VisitListDuplicatesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ListDuplicates403Response struct {
+}
+
+func (response ListDuplicates403Response) VisitListDuplicatesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type ListDuplicates500Response struct {
+}
+
+func (response ListDuplicates500Response) VisitListDuplicatesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type ResolveDuplicatesRequestObject struct {
+	Body *ResolveDuplicatesJSONRequestBody
+}
+
+type ResolveDuplicatesResponseObject interface {
+	VisitResolveDuplicatesResponse(w http.ResponseWriter) error
+}
+
+type ResolveDuplicates204Response struct {
+}
+
+func (response ResolveDuplicates204Response) VisitResolveDuplicatesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type ResolveDuplicates400Response struct {
+}
+
+func (response ResolveDuplicates400Response) VisitResolveDuplicatesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type ResolveDuplicates401Response struct {
+}
+
+func (response ResolveDuplicates401Response) VisitResolveDuplicatesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ResolveDuplicates403Response struct {
+}
+
+func (response ResolveDuplicates403Response) VisitResolveDuplicatesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type ResolveDuplicates404Response struct {
+}
+
+func (response ResolveDuplicates404Response) VisitResolveDuplicatesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type ResolveDuplicates500Response struct {
+}
+
+func (response ResolveDuplicates500Response) VisitResolveDuplicatesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type ListEventsRequestObject struct {
+}
+
+type ListEventsResponseObject interface {
+	VisitListEventsResponse(w http.ResponseWriter) error
+}
+
+type ListEvents200JSONResponse EventList
+
+func (response ListEvents200JSONResponse) VisitListEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListEvents200TexthtmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response ListEvents200TexthtmlResponse) VisitListEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ListEvents302Response struct {
+}
+
+func (response ListEvents302Response) VisitListEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(302)
+	return nil
+}
+
+type ListEvents401Response struct {
+}
+
+func (response ListEvents401Response) VisitListEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ListEvents500Response struct {
+}
+
+func (response ListEvents500Response) VisitListEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type UpdateEventRequestObject struct {
+	Id   int64 `json:"id"`
+	Body *UpdateEventJSONRequestBody
+}
+
+type UpdateEventResponseObject interface {
+	VisitUpdateEventResponse(w http.ResponseWriter) error
+}
+
+type UpdateEvent200JSONResponse Event
+
+func (response UpdateEvent200JSONResponse) VisitUpdateEventResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateEvent400Response struct {
+}
+
+func (response UpdateEvent400Response) VisitUpdateEventResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type UpdateEvent401Response struct {
+}
+
+func (response UpdateEvent401Response) VisitUpdateEventResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type UpdateEvent403Response struct {
+}
+
+func (response UpdateEvent403Response) VisitUpdateEventResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type UpdateEvent404Response struct {
+}
+
+func (response UpdateEvent404Response) VisitUpdateEventResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type UpdateEvent409Response struct {
+}
+
+func (response UpdateEvent409Response) VisitUpdateEventResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type UpdateEvent500Response struct {
+}
+
+func (response UpdateEvent500Response) VisitUpdateEventResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type MergeEventsRequestObject struct {
+	Id   int64 `json:"id"`
+	Body *MergeEventsJSONRequestBody
+}
+
+type MergeEventsResponseObject interface {
+	VisitMergeEventsResponse(w http.ResponseWriter) error
+}
+
+type MergeEvents200JSONResponse Event
+
+func (response MergeEvents200JSONResponse) VisitMergeEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type MergeEvents400Response struct {
+}
+
+func (response MergeEvents400Response) VisitMergeEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type MergeEvents401Response struct {
+}
+
+func (response MergeEvents401Response) VisitMergeEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type MergeEvents403Response struct {
+}
+
+func (response MergeEvents403Response) VisitMergeEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type MergeEvents404Response struct {
+}
+
+func (response MergeEvents404Response) VisitMergeEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type MergeEvents500Response struct {
+}
+
+func (response MergeEvents500Response) VisitMergeEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type GetLoginRequestObject struct {
+}
+
+type GetLoginResponseObject interface {
+	VisitGetLoginResponse(w http.ResponseWriter) error
+}
+
+type GetLogin200TexthtmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetLogin200TexthtmlResponse) VisitGetLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type PostLoginRequestObject struct {
+	Body *PostLoginFormdataRequestBody
+}
+
+type PostLoginResponseObject interface {
+	VisitPostLoginResponse(w http.ResponseWriter) error
+}
+
+type PostLogin200TexthtmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response PostLogin200TexthtmlResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type PostLogin302Response struct {
+}
+
+func (response PostLogin302Response) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.WriteHeader(302)
+	return nil
+}
+
+type PostLogin403TexthtmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response PostLogin403TexthtmlResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(403)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type PostLogin429ResponseHeaders struct {
+	RetryAfter int
+}
+
+type PostLogin429TexthtmlResponse struct {
+	Body          io.Reader
+	Headers       PostLogin429ResponseHeaders
+	ContentLength int64
+}
+
+func (response PostLogin429TexthtmlResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type PostLogoutRequestObject struct {
+	Body *PostLogoutFormdataRequestBody
+}
+
+type PostLogoutResponseObject interface {
+	VisitPostLogoutResponse(w http.ResponseWriter) error
+}
+
+type PostLogout303Response struct {
+}
+
+func (response PostLogout303Response) VisitPostLogoutResponse(w http.ResponseWriter) error {
+	w.WriteHeader(303)
+	return nil
+}
+
+type PostLogout403TexthtmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response PostLogout403TexthtmlResponse) VisitPostLogoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(403)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type UpdatePhotosRequestObject struct {
+	Body *UpdatePhotosJSONRequestBody
+}
+
+type UpdatePhotosResponseObject interface {
+	VisitUpdatePhotosResponse(w http.ResponseWriter) error
+}
+
+type UpdatePhotos200JSONResponse PhotoList
+
+func (response UpdatePhotos200JSONResponse) VisitUpdatePhotosResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdatePhotos400Response struct {
+}
+
+func (response UpdatePhotos400Response) VisitUpdatePhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type UpdatePhotos401Response struct {
+}
+
+func (response UpdatePhotos401Response) VisitUpdatePhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type UpdatePhotos403Response struct {
+}
+
+func (response UpdatePhotos403Response) VisitUpdatePhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type UpdatePhotos404Response struct {
+}
+
+func (response UpdatePhotos404Response) VisitUpdatePhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type UpdatePhotos500Response struct {
+}
+
+func (response UpdatePhotos500Response) VisitUpdatePhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type DeletePhotoRequestObject struct {
+	Filename string `json:"filename"`
+}
+
+type DeletePhotoResponseObject interface {
+	VisitDeletePhotoResponse(w http.ResponseWriter) error
+}
+
+type DeletePhoto204Response struct {
+}
+
+func (response DeletePhoto204Response) VisitDeletePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeletePhoto401Response struct {
+}
+
+func (response DeletePhoto401Response) VisitDeletePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeletePhoto403Response struct {
+}
+
+func (response DeletePhoto403Response) VisitDeletePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeletePhoto404Response struct {
+}
+
+func (response DeletePhoto404Response) VisitDeletePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeletePhoto500Response struct {
+}
+
+func (response DeletePhoto500Response) VisitDeletePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type UpdatePhotoRequestObject struct {
+	Filename string `json:"filename"`
+	Body     *UpdatePhotoJSONRequestBody
+}
+
+type UpdatePhotoResponseObject interface {
+	VisitUpdatePhotoResponse(w http.ResponseWriter) error
+}
+
+type UpdatePhoto200JSONResponse PhotoInfo
+
+func (response UpdatePhoto200JSONResponse) VisitUpdatePhotoResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdatePhoto400Response struct {
+}
+
+func (response UpdatePhoto400Response) VisitUpdatePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type UpdatePhoto401Response struct {
+}
+
+func (response UpdatePhoto401Response) VisitUpdatePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type UpdatePhoto403Response struct {
+}
+
+func (response UpdatePhoto403Response) VisitUpdatePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type UpdatePhoto404Response struct {
+}
+
+func (response UpdatePhoto404Response) VisitUpdatePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type UpdatePhoto500Response struct {
+}
+
+func (response UpdatePhoto500Response) VisitUpdatePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type ServeRenditionRequestObject struct {
+	Size     int    `json:"size"`
+	Filename string `json:"filename"`
+}
+
+type ServeRenditionResponseObject interface {
+	VisitServeRenditionResponse(w http.ResponseWriter) error
+}
+
+type ServeRendition200ImageResponse struct {
+	Body          io.Reader
+	ContentType   string
+	ContentLength int64
+}
+
+func (response ServeRendition200ImageResponse) VisitServeRenditionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", response.ContentType)
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ServeRendition401Response struct {
+}
+
+func (response ServeRendition401Response) VisitServeRenditionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ServeRendition404Response struct {
+}
+
+func (response ServeRendition404Response) VisitServeRenditionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type ListSessionsRequestObject struct {
+}
+
+type ListSessionsResponseObject interface {
+	VisitListSessionsResponse(w http.ResponseWriter) error
+}
+
+type ListSessions200JSONResponse SessionList
+
+func (response ListSessions200JSONResponse) VisitListSessionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListSessions401Response struct {
+}
+
+func (response ListSessions401Response) VisitListSessionsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ListSessions403Response struct {
+}
+
+func (response ListSessions403Response) VisitListSessionsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type ListSessions500Response struct {
+}
+
+func (response ListSessions500Response) VisitListSessionsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type RevokeSessionRequestObject struct {
+	Id int64 `json:"id"`
+}
+
+type RevokeSessionResponseObject interface {
+	VisitRevokeSessionResponse(w http.ResponseWriter) error
+}
+
+type RevokeSession204Response struct {
+}
+
+func (response RevokeSession204Response) VisitRevokeSessionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RevokeSession401Response struct {
+}
+
+func (response RevokeSession401Response) VisitRevokeSessionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type RevokeSession403Response struct {
+}
+
+func (response RevokeSession403Response) VisitRevokeSessionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type RevokeSession404Response struct {
+}
+
+func (response RevokeSession404Response) VisitRevokeSessionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type RevokeSession500Response struct {
+}
+
+func (response RevokeSession500Response) VisitRevokeSessionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type OpenShareRequestObject struct {
+	Token  string `json:"token"`
+	Params OpenShareParams
+}
+
+type OpenShareResponseObject interface {
+	VisitOpenShareResponse(w http.ResponseWriter) error
+}
+
+type OpenShare303Response struct {
+}
+
+func (response OpenShare303Response) VisitOpenShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(303)
+	return nil
+}
+
+type OpenShare403TexthtmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response OpenShare403TexthtmlResponse) VisitOpenShareResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(403)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ListSharesRequestObject struct {
+}
+
+type ListSharesResponseObject interface {
+	VisitListSharesResponse(w http.ResponseWriter) error
+}
+
+type ListShares200JSONResponse ShareList
+
+func (response ListShares200JSONResponse) VisitListSharesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListShares401Response struct {
+}
+
+func (response ListShares401Response) VisitListSharesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ListShares403Response struct {
+}
+
+func (response ListShares403Response) VisitListSharesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type ListShares500Response struct {
+}
+
+func (response ListShares500Response) VisitListSharesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type CreateShareRequestObject struct {
+	Body *CreateShareJSONRequestBody
+}
+
+type CreateShareResponseObject interface {
+	VisitCreateShareResponse(w http.ResponseWriter) error
+}
+
+type CreateShare201JSONResponse Share
+
+func (response CreateShare201JSONResponse) VisitCreateShareResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateShare400Response struct {
+}
+
+func (response CreateShare400Response) VisitCreateShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type CreateShare401Response struct {
+}
+
+func (response CreateShare401Response) VisitCreateShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type CreateShare403Response struct {
+}
+
+func (response CreateShare403Response) VisitCreateShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type CreateShare404Response struct {
+}
+
+func (response CreateShare404Response) VisitCreateShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type CreateShare500Response struct {
+}
+
+func (response CreateShare500Response) VisitCreateShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type DeleteShareRequestObject struct {
+	Id int64 `json:"id"`
+}
+
+type DeleteShareResponseObject interface {
+	VisitDeleteShareResponse(w http.ResponseWriter) error
+}
+
+type DeleteShare204Response struct {
+}
+
+func (response DeleteShare204Response) VisitDeleteShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteShare401Response struct {
+}
+
+func (response DeleteShare401Response) VisitDeleteShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteShare403Response struct {
+}
+
+func (response DeleteShare403Response) VisitDeleteShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteShare404Response struct {
+}
+
+func (response DeleteShare404Response) VisitDeleteShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteShare500Response struct {
+}
+
+func (response DeleteShare500Response) VisitDeleteShareResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type GetShareQRCodeRequestObject struct {
+	Id     int64 `json:"id"`
+	Params GetShareQRCodeParams
+}
+
+type GetShareQRCodeResponseObject interface {
+	VisitGetShareQRCodeResponse(w http.ResponseWriter) error
+}
+
+type GetShareQRCode200ImagepngResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetShareQRCode200ImagepngResponse) VisitGetShareQRCodeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "image/png")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetShareQRCode200ImagesvgXmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetShareQRCode200ImagesvgXmlResponse) VisitGetShareQRCodeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "image/svg+xml")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetShareQRCode400Response struct {
+}
+
+func (response GetShareQRCode400Response) VisitGetShareQRCodeResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type GetShareQRCode401Response struct {
+}
+
+func (response GetShareQRCode401Response) VisitGetShareQRCodeResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type GetShareQRCode403Response struct {
+}
+
+func (response GetShareQRCode403Response) VisitGetShareQRCodeResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type GetShareQRCode404Response struct {
+}
+
+func (response GetShareQRCode404Response) VisitGetShareQRCodeResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetShareQRCode500Response struct {
+}
+
+func (response GetShareQRCode500Response) VisitGetShareQRCodeResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type ServeStaticRequestObject struct {
+	Filename string `json:"filename"`
+}
+
+type ServeStaticResponseObject interface {
+	VisitServeStaticResponse(w http.ResponseWriter) error
+}
+
+type ServeStatic200AsteriskResponse struct {
+	Body          io.Reader
+	ContentType   string
+	ContentLength int64
+}
+
+func (response ServeStatic200AsteriskResponse) VisitServeStaticResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", response.ContentType)
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ServeStatic404Response struct {
+}
+
+func (response ServeStatic404Response) VisitServeStaticResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type SuggestTagsRequestObject struct {
+	Params SuggestTagsParams
+}
+
+type SuggestTagsResponseObject interface {
+	VisitSuggestTagsResponse(w http.ResponseWriter) error
+}
+
+type SuggestTags200JSONResponse TagList
+
+func (response SuggestTags200JSONResponse) VisitSuggestTagsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SuggestTags401Response struct {
+}
+
+func (response SuggestTags401Response) VisitSuggestTagsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ServeThumbnailRequestObject struct {
+	Filename string `json:"filename"`
+}
+
+type ServeThumbnailResponseObject interface {
+	VisitServeThumbnailResponse(w http.ResponseWriter) error
+}
+
+type ServeThumbnail200ImageResponse struct {
+	Body          io.Reader
+	ContentType   string
+	ContentLength int64
+}
+
+func (response ServeThumbnail200ImageResponse) VisitServeThumbnailResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", response.ContentType)
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ServeThumbnail401Response struct {
+}
+
+func (response ServeThumbnail401Response) VisitServeThumbnailResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ServeThumbnail404Response struct {
+}
+
+func (response ServeThumbnail404Response) VisitServeThumbnailResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type ListAPITokensRequestObject struct {
+}
+
+type ListAPITokensResponseObject interface {
+	VisitListAPITokensResponse(w http.ResponseWriter) error
+}
+
+type ListAPITokens200JSONResponse APITokenList
+
+func (response ListAPITokens200JSONResponse) VisitListAPITokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAPITokens401Response struct {
+}
+
+func (response ListAPITokens401Response) VisitListAPITokensResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ListAPITokens403Response struct {
+}
+
+func (response ListAPITokens403Response) VisitListAPITokensResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type ListAPITokens500Response struct {
+}
+
+func (response ListAPITokens500Response) VisitListAPITokensResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type CreateAPITokenRequestObject struct {
+	Body *CreateAPITokenJSONRequestBody
+}
+
+type CreateAPITokenResponseObject interface {
+	VisitCreateAPITokenResponse(w http.ResponseWriter) error
+}
+
+type CreateAPIToken201JSONResponse CreatedAPIToken
+
+func (response CreateAPIToken201JSONResponse) VisitCreateAPITokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAPIToken400Response struct {
+}
+
+func (response CreateAPIToken400Response) VisitCreateAPITokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type CreateAPIToken401Response struct {
+}
+
+func (response CreateAPIToken401Response) VisitCreateAPITokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type CreateAPIToken403Response struct {
+}
+
+func (response CreateAPIToken403Response) VisitCreateAPITokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type CreateAPIToken500Response struct {
+}
+
+func (response CreateAPIToken500Response) VisitCreateAPITokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type RevokeAPITokenRequestObject struct {
+	Id int64 `json:"id"`
+}
+
+type RevokeAPITokenResponseObject interface {
+	VisitRevokeAPITokenResponse(w http.ResponseWriter) error
+}
+
+type RevokeAPIToken204Response struct {
+}
+
+func (response RevokeAPIToken204Response) VisitRevokeAPITokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RevokeAPIToken401Response struct {
+}
+
+func (response RevokeAPIToken401Response) VisitRevokeAPITokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type RevokeAPIToken403Response struct {
+}
+
+func (response RevokeAPIToken403Response) VisitRevokeAPITokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type RevokeAPIToken404Response struct {
+}
+
+func (response RevokeAPIToken404Response) VisitRevokeAPITokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type RevokeAPIToken500Response struct {
+}
+
+func (response RevokeAPIToken500Response) VisitRevokeAPITokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type ListTrashRequestObject struct {
+}
+
+type ListTrashResponseObject interface {
+	VisitListTrashResponse(w http.ResponseWriter) error
+}
+
+type ListTrash200JSONResponse TrashList
+
+func (response ListTrash200JSONResponse) VisitListTrashResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListTrash401Response struct {
+}
+
+func (response ListTrash401Response) VisitListTrashResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ListTrash403Response struct {
+}
+
+func (response ListTrash403Response) VisitListTrashResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type ListTrash500Response struct {
+}
+
+func (response ListTrash500Response) VisitListTrashResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type PurgePhotoRequestObject struct {
+	Filename string `json:"filename"`
+}
+
+type PurgePhotoResponseObject interface {
+	VisitPurgePhotoResponse(w http.ResponseWriter) error
+}
+
+type PurgePhoto204Response struct {
+}
+
+func (response PurgePhoto204Response) VisitPurgePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type PurgePhoto401Response struct {
+}
+
+func (response PurgePhoto401Response) VisitPurgePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type PurgePhoto403Response struct {
+}
+
+func (response PurgePhoto403Response) VisitPurgePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type PurgePhoto404Response struct {
+}
+
+func (response PurgePhoto404Response) VisitPurgePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PurgePhoto500Response struct {
+}
+
+func (response PurgePhoto500Response) VisitPurgePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type RestorePhotoRequestObject struct {
+	Filename string `json:"filename"`
+}
+
+type RestorePhotoResponseObject interface {
+	VisitRestorePhotoResponse(w http.ResponseWriter) error
+}
+
+type RestorePhoto204Response struct {
+}
+
+func (response RestorePhoto204Response) VisitRestorePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RestorePhoto401Response struct {
+}
+
+func (response RestorePhoto401Response) VisitRestorePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type RestorePhoto403Response struct {
+}
+
+func (response RestorePhoto403Response) VisitRestorePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type RestorePhoto404Response struct {
+}
+
+func (response RestorePhoto404Response) VisitRestorePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type RestorePhoto409Response struct {
+}
+
+func (response RestorePhoto409Response) VisitRestorePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type RestorePhoto500Response struct {
+}
+
+func (response RestorePhoto500Response) VisitRestorePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type UnlockEventsRequestObject struct {
+	Body *UnlockEventsFormdataRequestBody
+}
+
+type UnlockEventsResponseObject interface {
+	VisitUnlockEventsResponse(w http.ResponseWriter) error
+}
+
+type UnlockEvents204Response struct {
+}
+
+func (response UnlockEvents204Response) VisitUnlockEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type UnlockEvents303Response struct {
+}
+
+func (response UnlockEvents303Response) VisitUnlockEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(303)
+	return nil
+}
+
+type UnlockEvents401Response struct {
+}
+
+func (response UnlockEvents401Response) VisitUnlockEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type UnlockEvents403Response struct {
+}
+
+func (response UnlockEvents403Response) VisitUnlockEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type UnlockEvents429ResponseHeaders struct {
+	RetryAfter int
+}
+
+type UnlockEvents429Response struct {
+	Headers UnlockEvents429ResponseHeaders
+}
+
+func (response UnlockEvents429Response) VisitUnlockEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+	return nil
+}
+
+type UploadPhotosRequestObject struct {
+	Body *multipart.Reader
+}
+
+type UploadPhotosResponseObject interface {
+	VisitUploadPhotosResponse(w http.ResponseWriter) error
+}
+
+type UploadPhotos200JSONResponse UploadResult
+
+func (response UploadPhotos200JSONResponse) VisitUploadPhotosResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UploadPhotos302Response struct {
+}
+
+func (response UploadPhotos302Response) VisitUploadPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(302)
+	return nil
+}
+
+type UploadPhotos400Response struct {
+}
+
+func (response UploadPhotos400Response) VisitUploadPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type UploadPhotos401Response struct {
+}
+
+func (response UploadPhotos401Response) VisitUploadPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type UploadPhotos403Response struct {
+}
+
+func (response UploadPhotos403Response) VisitUploadPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type UploadPhotos405Response struct {
+}
+
+func (response UploadPhotos405Response) VisitUploadPhotosResponse(w http.ResponseWriter) error {
+	w.WriteHeader(405)
+	return nil
+}
+
+type ServePhotoRequestObject struct {
+	Filename string `json:"filename"`
+	Params   ServePhotoParams
+}
+
+type ServePhotoResponseObject interface {
+	VisitServePhotoResponse(w http.ResponseWriter) error
+}
+
+type ServePhoto200ImageResponse struct {
+	Body          io.Reader
+	ContentType   string
+	ContentLength int64
+}
+
+func (response ServePhoto200ImageResponse) VisitServePhotoResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", response.ContentType)
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ServePhoto401Response struct {
+}
+
+func (response ServePhoto401Response) VisitServePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ServePhoto404Response struct {
+}
+
+func (response ServePhoto404Response) VisitServePhotoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type ListUsersRequestObject struct {
+}
+
+type ListUsersResponseObject interface {
+	VisitListUsersResponse(w http.ResponseWriter) error
+}
+
+type ListUsers200JSONResponse UserList
+
+func (response ListUsers200JSONResponse) VisitListUsersResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListUsers401Response struct {
+}
+
+func (response ListUsers401Response) VisitListUsersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type ListUsers403Response struct {
+}
+
+func (response ListUsers403Response) VisitListUsersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type ListUsers500Response struct {
+}
+
+func (response ListUsers500Response) VisitListUsersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type CreateUserRequestObject struct {
+	Body *CreateUserJSONRequestBody
+}
+
+type CreateUserResponseObject interface {
+	VisitCreateUserResponse(w http.ResponseWriter) error
+}
+
+type CreateUser201JSONResponse User
+
+func (response CreateUser201JSONResponse) VisitCreateUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateUser400Response struct {
+}
+
+func (response CreateUser400Response) VisitCreateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type CreateUser401Response struct {
+}
+
+func (response CreateUser401Response) VisitCreateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type CreateUser403Response struct {
+}
+
+func (response CreateUser403Response) VisitCreateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type CreateUser409Response struct {
+}
+
+func (response CreateUser409Response) VisitCreateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
+type CreateUser500Response struct {
+}
+
+func (response CreateUser500Response) VisitCreateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type DeleteUserRequestObject struct {
+	Id int64 `json:"id"`
+}
+
+type DeleteUserResponseObject interface {
+	VisitDeleteUserResponse(w http.ResponseWriter) error
+}
+
+type DeleteUser204Response struct {
+}
+
+func (response DeleteUser204Response) VisitDeleteUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteUser400Response struct {
+}
+
+func (response DeleteUser400Response) VisitDeleteUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type DeleteUser401Response struct {
+}
+
+func (response DeleteUser401Response) VisitDeleteUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type DeleteUser403Response struct {
+}
+
+func (response DeleteUser403Response) VisitDeleteUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type DeleteUser404Response struct {
+}
+
+func (response DeleteUser404Response) VisitDeleteUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type DeleteUser500Response struct {
+}
+
+func (response DeleteUser500Response) VisitDeleteUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type UpdateUserRequestObject struct {
+	Id   int64 `json:"id"`
+	Body *UpdateUserJSONRequestBody
+}
+
+type UpdateUserResponseObject interface {
+	VisitUpdateUserResponse(w http.ResponseWriter) error
+}
+
+type UpdateUser200JSONResponse User
+
+func (response UpdateUser200JSONResponse) VisitUpdateUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateUser400Response struct {
+}
+
+func (response UpdateUser400Response) VisitUpdateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type UpdateUser401Response struct {
+}
+
+func (response UpdateUser401Response) VisitUpdateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(401)
+	return nil
+}
+
+type UpdateUser403Response struct {
+}
+
+func (response UpdateUser403Response) VisitUpdateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(403)
+	return nil
+}
+
+type UpdateUser404Response struct {
+}
+
+func (response UpdateUser404Response) VisitUpdateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type UpdateUser500Response struct {
+}
+
+func (response UpdateUser500Response) VisitUpdateUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Gallery page
+	// (GET /)
+	GetGallery(ctx context.Context, request GetGalleryRequestObject) (GetGalleryResponseObject, error)
+	// List albums
+	// (GET /albums)
+	ListAlbums(ctx context.Context, request ListAlbumsRequestObject) (ListAlbumsResponseObject, error)
+	// Create an album
+	// (POST /albums)
+	CreateAlbum(ctx context.Context, request CreateAlbumRequestObject) (CreateAlbumResponseObject, error)
+	// Delete an album
+	// (DELETE /albums/{id})
+	DeleteAlbum(ctx context.Context, request DeleteAlbumRequestObject) (DeleteAlbumResponseObject, error)
+	// Get an album
+	// (GET /albums/{id})
+	GetAlbum(ctx context.Context, request GetAlbumRequestObject) (GetAlbumResponseObject, error)
+	// Rename an album
+	// (PATCH /albums/{id})
+	UpdateAlbum(ctx context.Context, request UpdateAlbumRequestObject) (UpdateAlbumResponseObject, error)
+	// Add photos to an album
+	// (POST /albums/{id}/photos)
+	AddAlbumPhotos(ctx context.Context, request AddAlbumPhotosRequestObject) (AddAlbumPhotosResponseObject, error)
+	// Reorder the photos of an album
+	// (PUT /albums/{id}/photos)
+	ReorderAlbumPhotos(ctx context.Context, request ReorderAlbumPhotosRequestObject) (ReorderAlbumPhotosResponseObject, error)
+	// Remove a photo from an album
+	// (DELETE /albums/{id}/photos/{filename})
+	RemoveAlbumPhoto(ctx context.Context, request RemoveAlbumPhotoRequestObject) (RemoveAlbumPhotoResponseObject, error)
+	// Download all photos as ZIP
+	// (GET /download-all)
+	DownloadAllPhotos(ctx context.Context, request DownloadAllPhotosRequestObject) (DownloadAllPhotosResponseObject, error)
+	// List near-duplicate photos
+	// (GET /duplicates)
+	ListDuplicates(ctx context.Context, request ListDuplicatesRequestObject) (ListDuplicatesResponseObject, error)
+	// Resolve a near-duplicate cluster
+	// (POST /duplicates/resolve)
+	ResolveDuplicates(ctx context.Context, request ResolveDuplicatesRequestObject) (ResolveDuplicatesResponseObject, error)
+	// Event overview
+	// (GET /events)
+	ListEvents(ctx context.Context, request ListEventsRequestObject) (ListEventsResponseObject, error)
+	// Edit an event
+	// (PATCH /events/{id})
+	UpdateEvent(ctx context.Context, request UpdateEventRequestObject) (UpdateEventResponseObject, error)
+	// Merge events
+	// (POST /events/{id}/merge)
+	MergeEvents(ctx context.Context, request MergeEventsRequestObject) (MergeEventsResponseObject, error)
+	// Login page
+	// (GET /login)
+	GetLogin(ctx context.Context, request GetLoginRequestObject) (GetLoginResponseObject, error)
+	// Authenticate user
+	// (POST /login)
+	PostLogin(ctx context.Context, request PostLoginRequestObject) (PostLoginResponseObject, error)
+	// Log out
+	// (POST /logout)
+	PostLogout(ctx context.Context, request PostLogoutRequestObject) (PostLogoutResponseObject, error)
+	// Edit metadata of several photos
+	// (PATCH /photos)
+	UpdatePhotos(ctx context.Context, request UpdatePhotosRequestObject) (UpdatePhotosResponseObject, error)
+	// Delete a photo
+	// (DELETE /photos/{filename})
+	DeletePhoto(ctx context.Context, request DeletePhotoRequestObject) (DeletePhotoResponseObject, error)
+	// Edit photo metadata
+	// (PATCH /photos/{filename})
+	UpdatePhoto(ctx context.Context, request UpdatePhotoRequestObject) (UpdatePhotoResponseObject, error)
+	// Serve photo rendition
+	// (GET /renditions/{size}/{filename})
+	ServeRendition(ctx context.Context, request ServeRenditionRequestObject) (ServeRenditionResponseObject, error)
+	// List sessions
+	// (GET /sessions)
+	ListSessions(ctx context.Context, request ListSessionsRequestObject) (ListSessionsResponseObject, error)
+	// Revoke a session
+	// (DELETE /sessions/{id})
+	RevokeSession(ctx context.Context, request RevokeSessionRequestObject) (RevokeSessionResponseObject, error)
+	// Open a share link
+	// (GET /share/{token})
+	OpenShare(ctx context.Context, request OpenShareRequestObject) (OpenShareResponseObject, error)
+	// List share links
+	// (GET /shares)
+	ListShares(ctx context.Context, request ListSharesRequestObject) (ListSharesResponseObject, error)
+	// Create a share link
+	// (POST /shares)
+	CreateShare(ctx context.Context, request CreateShareRequestObject) (CreateShareResponseObject, error)
+	// Revoke a share link
+	// (DELETE /shares/{id})
+	DeleteShare(ctx context.Context, request DeleteShareRequestObject) (DeleteShareResponseObject, error)
+	// Share link QR code
+	// (GET /shares/{id}/qr)
+	GetShareQRCode(ctx context.Context, request GetShareQRCodeRequestObject) (GetShareQRCodeResponseObject, error)
+	// Serve static assets
+	// (GET /static/{filename})
+	ServeStatic(ctx context.Context, request ServeStaticRequestObject) (ServeStaticResponseObject, error)
+	// Suggest tags
+	// (GET /tags)
+	SuggestTags(ctx context.Context, request SuggestTagsRequestObject) (SuggestTagsResponseObject, error)
+	// Serve photo thumbnail
+	// (GET /thumbnails/{filename})
+	ServeThumbnail(ctx context.Context, request ServeThumbnailRequestObject) (ServeThumbnailResponseObject, error)
+	// List API tokens
+	// (GET /tokens)
+	ListAPITokens(ctx context.Context, request ListAPITokensRequestObject) (ListAPITokensResponseObject, error)
+	// Create an API token
+	// (POST /tokens)
+	CreateAPIToken(ctx context.Context, request CreateAPITokenRequestObject) (CreateAPITokenResponseObject, error)
+	// Revoke an API token
+	// (DELETE /tokens/{id})
+	RevokeAPIToken(ctx context.Context, request RevokeAPITokenRequestObject) (RevokeAPITokenResponseObject, error)
+	// List deleted photos
+	// (GET /trash)
+	ListTrash(ctx context.Context, request ListTrashRequestObject) (ListTrashResponseObject, error)
+	// Purge a deleted photo
+	// (DELETE /trash/{filename})
+	PurgePhoto(ctx context.Context, request PurgePhotoRequestObject) (PurgePhotoResponseObject, error)
+	// Restore a deleted photo
+	// (POST /trash/{filename}/restore)
+	RestorePhoto(ctx context.Context, request RestorePhotoRequestObject) (RestorePhotoResponseObject, error)
+	// Unlock events
+	// (POST /unlock)
+	UnlockEvents(ctx context.Context, request UnlockEventsRequestObject) (UnlockEventsResponseObject, error)
+	// Upload photos
+	// (POST /upload)
+	UploadPhotos(ctx context.Context, request UploadPhotosRequestObject) (UploadPhotosResponseObject, error)
+	// Serve uploaded photo
+	// (GET /uploads/{filename})
+	ServePhoto(ctx context.Context, request ServePhotoRequestObject) (ServePhotoResponseObject, error)
+	// List users
+	// (GET /users)
+	ListUsers(ctx context.Context, request ListUsersRequestObject) (ListUsersResponseObject, error)
+	// Create a user
+	// (POST /users)
+	CreateUser(ctx context.Context, request CreateUserRequestObject) (CreateUserResponseObject, error)
+	// Delete a user
+	// (DELETE /users/{id})
+	DeleteUser(ctx context.Context, request DeleteUserRequestObject) (DeleteUserResponseObject, error)
+	// Update a user
+	// (PATCH /users/{id})
+	UpdateUser(ctx context.Context, request UpdateUserRequestObject) (UpdateUserResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// GetGallery operation middleware
+func (sh *strictHandler) GetGallery(w http.ResponseWriter, r *http.Request, params GetGalleryParams) {
+	var request GetGalleryRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGallery(ctx, request.(GetGalleryRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGallery")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetGalleryResponseObject); ok {
+		if err := validResponse.VisitGetGalleryResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListAlbums operation middleware
+func (sh *strictHandler) ListAlbums(w http.ResponseWriter, r *http.Request) {
+	var request ListAlbumsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListAlbums(ctx, request.(ListAlbumsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListAlbums")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListAlbumsResponseObject); ok {
+		if err := validResponse.VisitListAlbumsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateAlbum operation middleware
+func (sh *strictHandler) CreateAlbum(w http.ResponseWriter, r *http.Request) {
+	var request CreateAlbumRequestObject
+
+	var body CreateAlbumJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateAlbum(ctx, request.(CreateAlbumRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateAlbum")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateAlbumResponseObject); ok {
+		if err := validResponse.VisitCreateAlbumResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteAlbum operation middleware
+func (sh *strictHandler) DeleteAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	var request DeleteAlbumRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteAlbum(ctx, request.(DeleteAlbumRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteAlbum")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteAlbumResponseObject); ok {
+		if err := validResponse.VisitDeleteAlbumResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetAlbum operation middleware
+func (sh *strictHandler) GetAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	var request GetAlbumRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAlbum(ctx, request.(GetAlbumRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAlbum")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAlbumResponseObject); ok {
+		if err := validResponse.VisitGetAlbumResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateAlbum operation middleware
+func (sh *strictHandler) UpdateAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	var request UpdateAlbumRequestObject
+
+	request.Id = id
+
+	var body UpdateAlbumJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateAlbum(ctx, request.(UpdateAlbumRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateAlbum")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateAlbumResponseObject); ok {
+		if err := validResponse.VisitUpdateAlbumResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// AddAlbumPhotos operation middleware
+func (sh *strictHandler) AddAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64) {
+	var request AddAlbumPhotosRequestObject
+
+	request.Id = id
+
+	var body AddAlbumPhotosJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.AddAlbumPhotos(ctx, request.(AddAlbumPhotosRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "AddAlbumPhotos")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AddAlbumPhotosResponseObject); ok {
+		if err := validResponse.VisitAddAlbumPhotosResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ReorderAlbumPhotos operation middleware
+func (sh *strictHandler) ReorderAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64) {
+	var request ReorderAlbumPhotosRequestObject
+
+	request.Id = id
+
+	var body ReorderAlbumPhotosJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ReorderAlbumPhotos(ctx, request.(ReorderAlbumPhotosRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ReorderAlbumPhotos")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReorderAlbumPhotosResponseObject); ok {
+		if err := validResponse.VisitReorderAlbumPhotosResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RemoveAlbumPhoto operation middleware
+func (sh *strictHandler) RemoveAlbumPhoto(w http.ResponseWriter, r *http.Request, id int64, filename string) {
+	var request RemoveAlbumPhotoRequestObject
+
+	request.Id = id
+	request.Filename = filename
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RemoveAlbumPhoto(ctx, request.(RemoveAlbumPhotoRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RemoveAlbumPhoto")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RemoveAlbumPhotoResponseObject); ok {
+		if err := validResponse.VisitRemoveAlbumPhotoResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DownloadAllPhotos operation middleware
+func (sh *strictHandler) DownloadAllPhotos(w http.ResponseWriter, r *http.Request, params DownloadAllPhotosParams) {
+	var request DownloadAllPhotosRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DownloadAllPhotos(ctx, request.(DownloadAllPhotosRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DownloadAllPhotos")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DownloadAllPhotosResponseObject); ok {
+		if err := validResponse.VisitDownloadAllPhotosResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListDuplicates operation middleware
+func (sh *strictHandler) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+	var request ListDuplicatesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListDuplicates(ctx, request.(ListDuplicatesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListDuplicates")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListDuplicatesResponseObject); ok {
+		if err := validResponse.VisitListDuplicatesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ResolveDuplicates operation middleware
+func (sh *strictHandler) ResolveDuplicates(w http.ResponseWriter, r *http.Request) {
+	var request ResolveDuplicatesRequestObject
+
+	var body ResolveDuplicatesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ResolveDuplicates(ctx, request.(ResolveDuplicatesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ResolveDuplicates")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ResolveDuplicatesResponseObject); ok {
+		if err := validResponse.VisitResolveDuplicatesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListEvents operation middleware
+func (sh *strictHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	var request ListEventsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListEvents(ctx, request.(ListEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListEvents")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListEventsResponseObject); ok {
+		if err := validResponse.VisitListEventsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateEvent operation middleware
+func (sh *strictHandler) UpdateEvent(w http.ResponseWriter, r *http.Request, id int64) {
+	var request UpdateEventRequestObject
+
+	request.Id = id
+
+	var body UpdateEventJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateEvent(ctx, request.(UpdateEventRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateEvent")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateEventResponseObject); ok {
+		if err := validResponse.VisitUpdateEventResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// MergeEvents operation middleware
+func (sh *strictHandler) MergeEvents(w http.ResponseWriter, r *http.Request, id int64) {
+	var request MergeEventsRequestObject
+
+	request.Id = id
+
+	var body MergeEventsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.MergeEvents(ctx, request.(MergeEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "MergeEvents")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(MergeEventsResponseObject); ok {
+		if err := validResponse.VisitMergeEventsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetLogin operation middleware
+func (sh *strictHandler) GetLogin(w http.ResponseWriter, r *http.Request) {
+	var request GetLoginRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetLogin(ctx, request.(GetLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetLogin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetLoginResponseObject); ok {
+		if err := validResponse.VisitGetLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostLogin operation middleware
+func (sh *strictHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
+	var request PostLoginRequestObject
+
+	if err := r.ParseForm(); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode formdata: %w", err))
+		return
+	}
+	var body PostLoginFormdataRequestBody
+	if err := runtime.BindForm(&body, r.Form, nil, nil); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't bind formdata: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostLogin(ctx, request.(PostLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostLogin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostLoginResponseObject); ok {
+		if err := validResponse.VisitPostLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostLogout operation middleware
+func (sh *strictHandler) PostLogout(w http.ResponseWriter, r *http.Request) {
+	var request PostLogoutRequestObject
+
+	if err := r.ParseForm(); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode formdata: %w", err))
+		return
+	}
+	var body PostLogoutFormdataRequestBody
+	if err := runtime.BindForm(&body, r.Form, nil, nil); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't bind formdata: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostLogout(ctx, request.(PostLogoutRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostLogout")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostLogoutResponseObject); ok {
+		if err := validResponse.VisitPostLogoutResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdatePhotos operation middleware
+func (sh *strictHandler) UpdatePhotos(w http.ResponseWriter, r *http.Request) {
+	var request UpdatePhotosRequestObject
+
+	var body UpdatePhotosJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdatePhotos(ctx, request.(UpdatePhotosRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdatePhotos")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdatePhotosResponseObject); ok {
+		if err := validResponse.VisitUpdatePhotosResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeletePhoto operation middleware
+func (sh *strictHandler) DeletePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	var request DeletePhotoRequestObject
+
+	request.Filename = filename
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeletePhoto(ctx, request.(DeletePhotoRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeletePhoto")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeletePhotoResponseObject); ok {
+		if err := validResponse.VisitDeletePhotoResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdatePhoto operation middleware
+func (sh *strictHandler) UpdatePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	var request UpdatePhotoRequestObject
+
+	request.Filename = filename
+
+	var body UpdatePhotoJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdatePhoto(ctx, request.(UpdatePhotoRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdatePhoto")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdatePhotoResponseObject); ok {
+		if err := validResponse.VisitUpdatePhotoResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ServeRendition operation middleware
+func (sh *strictHandler) ServeRendition(w http.ResponseWriter, r *http.Request, size int, filename string) {
+	var request ServeRenditionRequestObject
+
+	request.Size = size
+	request.Filename = filename
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ServeRendition(ctx, request.(ServeRenditionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ServeRendition")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ServeRenditionResponseObject); ok {
+		if err := validResponse.VisitServeRenditionResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListSessions operation middleware
+func (sh *strictHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	var request ListSessionsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListSessions(ctx, request.(ListSessionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListSessions")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListSessionsResponseObject); ok {
+		if err := validResponse.VisitListSessionsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RevokeSession operation middleware
+func (sh *strictHandler) RevokeSession(w http.ResponseWriter, r *http.Request, id int64) {
+	var request RevokeSessionRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RevokeSession(ctx, request.(RevokeSessionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RevokeSession")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RevokeSessionResponseObject); ok {
+		if err := validResponse.VisitRevokeSessionResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// OpenShare operation middleware
+func (sh *strictHandler) OpenShare(w http.ResponseWriter, r *http.Request, token string, params OpenShareParams) {
+	var request OpenShareRequestObject
+
+	request.Token = token
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.OpenShare(ctx, request.(OpenShareRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "OpenShare")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(OpenShareResponseObject); ok {
+		if err := validResponse.VisitOpenShareResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListShares operation middleware
+func (sh *strictHandler) ListShares(w http.ResponseWriter, r *http.Request) {
+	var request ListSharesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListShares(ctx, request.(ListSharesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListShares")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListSharesResponseObject); ok {
+		if err := validResponse.VisitListSharesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateShare operation middleware
+func (sh *strictHandler) CreateShare(w http.ResponseWriter, r *http.Request) {
+	var request CreateShareRequestObject
+
+	var body CreateShareJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateShare(ctx, request.(CreateShareRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateShare")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateShareResponseObject); ok {
+		if err := validResponse.VisitCreateShareResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteShare operation middleware
+func (sh *strictHandler) DeleteShare(w http.ResponseWriter, r *http.Request, id int64) {
+	var request DeleteShareRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteShare(ctx, request.(DeleteShareRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteShare")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteShareResponseObject); ok {
+		if err := validResponse.VisitDeleteShareResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetShareQRCode operation middleware
+func (sh *strictHandler) GetShareQRCode(w http.ResponseWriter, r *http.Request, id int64, params GetShareQRCodeParams) {
+	var request GetShareQRCodeRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetShareQRCode(ctx, request.(GetShareQRCodeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetShareQRCode")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetShareQRCodeResponseObject); ok {
+		if err := validResponse.VisitGetShareQRCodeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ServeStatic operation middleware
+func (sh *strictHandler) ServeStatic(w http.ResponseWriter, r *http.Request, filename string) {
+	var request ServeStaticRequestObject
+
+	request.Filename = filename
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ServeStatic(ctx, request.(ServeStaticRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ServeStatic")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ServeStaticResponseObject); ok {
+		if err := validResponse.VisitServeStaticResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SuggestTags operation middleware
+func (sh *strictHandler) SuggestTags(w http.ResponseWriter, r *http.Request, params SuggestTagsParams) {
+	var request SuggestTagsRequestObject
+
+	request.Params = params
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SuggestTags(ctx, request.(SuggestTagsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SuggestTags")
 	}
-	_, err := io.Copy(w, response.Body)
-	return err
-}
 
-type ServePhoto401Response struct {
-}
+	response, err := handler(r.Context(), w, r, request)
 
-func (response ServePhoto401Response) VisitServePhotoResponse(w http.ResponseWriter) error {
-	w.WriteHeader(401)
-	return nil
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SuggestTagsResponseObject); ok {
+		if err := validResponse.VisitSuggestTagsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type ServePhoto404Response struct {
-}
+// ServeThumbnail operation middleware
+func (sh *strictHandler) ServeThumbnail(w http.ResponseWriter, r *http.Request, filename string) {
+	var request ServeThumbnailRequestObject
 
-func (response ServePhoto404Response) VisitServePhotoResponse(w http.ResponseWriter) error {
-	w.WriteHeader(404)
-	return nil
-}
+	request.Filename = filename
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
-	// Gallery page
-	// (GET /)
-	GetGallery(ctx context.Context, request GetGalleryRequestObject) (GetGalleryResponseObject, error)
-	// Download all photos as ZIP
-	// (GET /download-all)
-	DownloadAllPhotos(ctx context.Context, request DownloadAllPhotosRequestObject) (DownloadAllPhotosResponseObject, error)
-	// Login page
-	// (GET /login)
-	GetLogin(ctx context.Context, request GetLoginRequestObject) (GetLoginResponseObject, error)
-	// Authenticate user
-	// (POST /login)
-	PostLogin(ctx context.Context, request PostLoginRequestObject) (PostLoginResponseObject, error)
-	// Serve static assets
-	// (GET /static/{filename})
-	ServeStatic(ctx context.Context, request ServeStaticRequestObject) (ServeStaticResponseObject, error)
-	// Serve photo thumbnail
-	// (GET /thumbnails/{filename})
-	ServeThumbnail(ctx context.Context, request ServeThumbnailRequestObject) (ServeThumbnailResponseObject, error)
-	// Upload photos
-	// (POST /upload)
-	UploadPhotos(ctx context.Context, request UploadPhotosRequestObject) (UploadPhotosResponseObject, error)
-	// Serve uploaded photo
-	// (GET /uploads/{filename})
-	ServePhoto(ctx context.Context, request ServePhotoRequestObject) (ServePhotoResponseObject, error)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ServeThumbnail(ctx, request.(ServeThumbnailRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ServeThumbnail")
+	}
 
-type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
-type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+	response, err := handler(r.Context(), w, r, request)
 
-type StrictHTTPServerOptions struct {
-	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
-	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ServeThumbnailResponseObject); ok {
+		if err := validResponse.VisitServeThumbnailResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
-		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
-		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		},
-	}}
-}
+// ListAPITokens operation middleware
+func (sh *strictHandler) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	var request ListAPITokensRequestObject
 
-func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListAPITokens(ctx, request.(ListAPITokensRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListAPITokens")
+	}
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
-	options     StrictHTTPServerOptions
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListAPITokensResponseObject); ok {
+		if err := validResponse.VisitListAPITokensResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-// GetGallery operation middleware
-func (sh *strictHandler) GetGallery(w http.ResponseWriter, r *http.Request, params GetGalleryParams) {
-	var request GetGalleryRequestObject
+// CreateAPIToken operation middleware
+func (sh *strictHandler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	var request CreateAPITokenRequestObject
 
-	request.Params = params
+	var body CreateAPITokenJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetGallery(ctx, request.(GetGalleryRequestObject))
+		return sh.ssi.CreateAPIToken(ctx, request.(CreateAPITokenRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetGallery")
+		handler = middleware(handler, "CreateAPIToken")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetGalleryResponseObject); ok {
-		if err := validResponse.VisitGetGalleryResponse(w); err != nil {
+	} else if validResponse, ok := response.(CreateAPITokenResponseObject); ok {
+		if err := validResponse.VisitCreateAPITokenResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -926,25 +6206,25 @@ func (sh *strictHandler) GetGallery(w http.ResponseWriter, r *http.Request, para
 	}
 }
 
-// DownloadAllPhotos operation middleware
-func (sh *strictHandler) DownloadAllPhotos(w http.ResponseWriter, r *http.Request, params DownloadAllPhotosParams) {
-	var request DownloadAllPhotosRequestObject
+// RevokeAPIToken operation middleware
+func (sh *strictHandler) RevokeAPIToken(w http.ResponseWriter, r *http.Request, id int64) {
+	var request RevokeAPITokenRequestObject
 
-	request.Params = params
+	request.Id = id
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.DownloadAllPhotos(ctx, request.(DownloadAllPhotosRequestObject))
+		return sh.ssi.RevokeAPIToken(ctx, request.(RevokeAPITokenRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "DownloadAllPhotos")
+		handler = middleware(handler, "RevokeAPIToken")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(DownloadAllPhotosResponseObject); ok {
-		if err := validResponse.VisitDownloadAllPhotosResponse(w); err != nil {
+	} else if validResponse, ok := response.(RevokeAPITokenResponseObject); ok {
+		if err := validResponse.VisitRevokeAPITokenResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -952,23 +6232,23 @@ func (sh *strictHandler) DownloadAllPhotos(w http.ResponseWriter, r *http.Reques
 	}
 }
 
-// GetLogin operation middleware
-func (sh *strictHandler) GetLogin(w http.ResponseWriter, r *http.Request) {
-	var request GetLoginRequestObject
+// ListTrash operation middleware
+func (sh *strictHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	var request ListTrashRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetLogin(ctx, request.(GetLoginRequestObject))
+		return sh.ssi.ListTrash(ctx, request.(ListTrashRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetLogin")
+		handler = middleware(handler, "ListTrash")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetLoginResponseObject); ok {
-		if err := validResponse.VisitGetLoginResponse(w); err != nil {
+	} else if validResponse, ok := response.(ListTrashResponseObject); ok {
+		if err := validResponse.VisitListTrashResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -976,34 +6256,25 @@ func (sh *strictHandler) GetLogin(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// PostLogin operation middleware
-func (sh *strictHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
-	var request PostLoginRequestObject
+// PurgePhoto operation middleware
+func (sh *strictHandler) PurgePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	var request PurgePhotoRequestObject
 
-	if err := r.ParseForm(); err != nil {
-		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode formdata: %w", err))
-		return
-	}
-	var body PostLoginFormdataRequestBody
-	if err := runtime.BindForm(&body, r.Form, nil, nil); err != nil {
-		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't bind formdata: %w", err))
-		return
-	}
-	request.Body = &body
+	request.Filename = filename
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.PostLogin(ctx, request.(PostLoginRequestObject))
+		return sh.ssi.PurgePhoto(ctx, request.(PurgePhotoRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "PostLogin")
+		handler = middleware(handler, "PurgePhoto")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(PostLoginResponseObject); ok {
-		if err := validResponse.VisitPostLoginResponse(w); err != nil {
+	} else if validResponse, ok := response.(PurgePhotoResponseObject); ok {
+		if err := validResponse.VisitPurgePhotoResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1011,25 +6282,25 @@ func (sh *strictHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ServeStatic operation middleware
-func (sh *strictHandler) ServeStatic(w http.ResponseWriter, r *http.Request, filename string) {
-	var request ServeStaticRequestObject
+// RestorePhoto operation middleware
+func (sh *strictHandler) RestorePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	var request RestorePhotoRequestObject
 
 	request.Filename = filename
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.ServeStatic(ctx, request.(ServeStaticRequestObject))
+		return sh.ssi.RestorePhoto(ctx, request.(RestorePhotoRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "ServeStatic")
+		handler = middleware(handler, "RestorePhoto")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(ServeStaticResponseObject); ok {
-		if err := validResponse.VisitServeStaticResponse(w); err != nil {
+	} else if validResponse, ok := response.(RestorePhotoResponseObject); ok {
+		if err := validResponse.VisitRestorePhotoResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1037,25 +6308,34 @@ func (sh *strictHandler) ServeStatic(w http.ResponseWriter, r *http.Request, fil
 	}
 }
 
-// ServeThumbnail operation middleware
-func (sh *strictHandler) ServeThumbnail(w http.ResponseWriter, r *http.Request, filename string) {
-	var request ServeThumbnailRequestObject
+// UnlockEvents operation middleware
+func (sh *strictHandler) UnlockEvents(w http.ResponseWriter, r *http.Request) {
+	var request UnlockEventsRequestObject
 
-	request.Filename = filename
+	if err := r.ParseForm(); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode formdata: %w", err))
+		return
+	}
+	var body UnlockEventsFormdataRequestBody
+	if err := runtime.BindForm(&body, r.Form, nil, nil); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't bind formdata: %w", err))
+		return
+	}
+	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.ServeThumbnail(ctx, request.(ServeThumbnailRequestObject))
+		return sh.ssi.UnlockEvents(ctx, request.(UnlockEventsRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "ServeThumbnail")
+		handler = middleware(handler, "UnlockEvents")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(ServeThumbnailResponseObject); ok {
-		if err := validResponse.VisitServeThumbnailResponse(w); err != nil {
+	} else if validResponse, ok := response.(UnlockEventsResponseObject); ok {
+		if err := validResponse.VisitUnlockEventsResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1095,10 +6375,11 @@ func (sh *strictHandler) UploadPhotos(w http.ResponseWriter, r *http.Request) {
 }
 
 // ServePhoto operation middleware
-func (sh *strictHandler) ServePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+func (sh *strictHandler) ServePhoto(w http.ResponseWriter, r *http.Request, filename string, params ServePhotoParams) {
 	var request ServePhotoRequestObject
 
 	request.Filename = filename
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.ServePhoto(ctx, request.(ServePhotoRequestObject))
@@ -1120,30 +6401,267 @@ func (sh *strictHandler) ServePhoto(w http.ResponseWriter, r *http.Request, file
 	}
 }
 
+// ListUsers operation middleware
+func (sh *strictHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	var request ListUsersRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListUsers(ctx, request.(ListUsersRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListUsers")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListUsersResponseObject); ok {
+		if err := validResponse.VisitListUsersResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateUser operation middleware
+func (sh *strictHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var request CreateUserRequestObject
+
+	var body CreateUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateUser(ctx, request.(CreateUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateUserResponseObject); ok {
+		if err := validResponse.VisitCreateUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteUser operation middleware
+func (sh *strictHandler) DeleteUser(w http.ResponseWriter, r *http.Request, id int64) {
+	var request DeleteUserRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteUser(ctx, request.(DeleteUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteUserResponseObject); ok {
+		if err := validResponse.VisitDeleteUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateUser operation middleware
+func (sh *strictHandler) UpdateUser(w http.ResponseWriter, r *http.Request, id int64) {
+	var request UpdateUserRequestObject
+
+	request.Id = id
+
+	var body UpdateUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateUser(ctx, request.(UpdateUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateUserResponseObject); ok {
+		if err := validResponse.VisitUpdateUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+RX224bNxD9lQGf4mBlKTcg0JvTNIF7CYSqealhFNTuSMuGy2HIWSuyoX8vSO7qtpIs",
-	"10lQIC9BrOVcOOfMmeGdyKmyZNCwF8M74TGvneLFOC+xwuYn7xWZi5rL8GeBPnfKsiIjhmKcPvYm0mMB",
-	"suYSDatchs9Qe2VmkBN9UuhFJlSwSH+KTBhZoRiKmdQa3aLXhBGZ4IUNH6RVv+JCLJfLYDmlEDwnwzLn",
-	"8N/GfFQSE7xPTsQy28nvAryqrEaw8VwTDKS1us1yrrjczVyaAqZKI9RWkyxCUop1Jx5cjC5FJm7Q+RTu",
-	"2fngfBCyIItGWiWG4sX5s/OByISVXMZ69sM/M+RuMd8qb7VcAJcIlVRmla6VM0x5xmuEWpJFF3O9LMRQ",
-	"vEduSxAiOVkho/NieLUb453SjK7xA5MF4A0ahljNBqHPdfLTVDgeEJnwgRIyZN0A5NkpMxPLZXZvkFRG",
-	"dMfitGeOhrrOhENvyfhEzueDQcuLkGWwwC/cL7nSkbsHHXWY8n6z1g5NgQ4L8HWeo/fTWuvIrheD513c",
-	"/sBCOcwZmEDTTBlQUzDEm6zCIpi/Sulum18aRmekBo/uBh2gc+RCWVfdGHHc6sOr61AKX1eVdIud7KNp",
-	"v6C5CQXtSa0PM645BFLrFq4n5AL3Od4//XYG0oOEvy5HIF1eqhvsMLD1dKH1qOXoj03EDY3p3yq7Tccp",
-	"uUqyGIqJMjIGv5egofpRk6IIKhO0tZGDZSZeDp514f1oAgPJqVss4EmHkWfJ8GXX8AO1NWOClknp9Kvu",
-	"6d+RSyoS47Wm+Tfm+j7SSh/YmZgfW/AkkU3NGrDYp6i/RT/fTHCi+6Nys12VrRqsjUOpLfk9Nx05Cs42",
-	"bgm+nlSqnbPbFx6R37jx5xo9v6FicYTUX3rz+bwX/PZqp9HkVGCxXQTrQhBWqXZWej8nV3RTXctXc6Lb",
-	"Dykr5UKIq7Wr69VJmvyDOadKr4+yq3H5PTCM4znSGSr0vkHm3nnRjngyLfhADiYy/7SeJmRgKpWuHR7l",
-	"xMVGb0PtMfVV37NklffvgnYEhVsebI1x6ElI5+Gn8TiDX8ZZ3IWIS3QgvUfuLh/RbByt7hP9D7JCoGls",
-	"viZOSKsV4rAjrXW4TVjs4vk4XX7af/poLR5v5H5IQ98FrQ6aOKXaFAm5FVZbpW7qGtHisq4mRirtT0dM",
-	"wsoKml20LXIzy5pBDk+aQvqdhfdsP6h/tm4fgmuK9N1hVZWc4VeAdnVniB43MP6aAzY9I3bYcfIETLCn",
-	"Qq+gT/xpHixBbfcOhY/xO5DBoDMVuU3AfFKxClkWkmWHFMl4td8dHhNVrVlZ6bgfx0N0dmQwxM3u78SP",
-	"3YR/Xq2FYYQBuZk06nZj+elgmonmy4Gqp5syrR93irHyJ/Fl/Tp1TsYXQbstHkh/qzPQ+fAsjhbHbrA7",
-	"7NKx/zLqTp4/choW5fX+0RYnEnjPIvdGFtDgH4jfFLWVm7NHdsyJS+aDmqZhfrsyr5vlQUrrLeZqqvJd",
-	"ZY2b+QPlNdLxB5LWdfv9PwV1G9J0g/RO2QfMW7xBTbYK8pROiUzUTouhKJntsN/XlEtdkufh68HrgVhe",
-	"L/8NAAD//4gRe7tvEwAA",
+	"H4sIAAAAAAAC/+x9a3MbubHoX0Hx3qpY544oynJuNvInra1slPihI8lns1m6FGimRSIaArMAKJnZ0n8/",
+	"hcZjMBwMObQlmfv6sCVzZoBGo9/daPw8yMWsEhy4VoPDnwcqn8KM4p9HpycX4ga4+buSogKpGeCTXALV",
+	"UJg/C1C5ZJVmgg8OB6/MAyY40WwGRFwTPQWicZBscC3kjOrB4aCgGnbNG4NsoBcVDA4HSkvGJ4P7bMAS",
+	"w76mml5RBeTk9fKg8InOqhIGhy+iCRjX//9FPTjjGiYgzeglVfpyrlKwX0Qgm9eIhJ/moDS5Y3paz5mR",
+	"eWUWUBCqyUwoTQTPgVAyY3yuofc6OZ1BG4h3dAlvGZGQC1mY+RSZV6WgBUjzCtOKVFOhhYrRMHhFZyAp",
+	"KWmlRZWaWOWiwpn/r4TrweHg/+zVFLDntn8PN/4c37y/zwYGFUwarP1oNshB78fKAkF8DPOJq39Drs18",
+	"nozeMKXbpISLxL+YhplaB5cfbHAfZqJS0kULSjduEqDyaj5LELW4BXmJGG3vy19YCTzam2smlbbo9z9R",
+	"HDbeCny8//xg+O9qktqJzdioPf7z0fOD3f3nu6P9i/3R4cHocDT650PzWWvWg158tp6628v51jCbuCZm",
+	"WSmIEZ2XuZhznRh6PruyfGF5gjCenub5iza8Kyjc71Fz+k6yeg2astKAR8vy/fXg8Mc19Izg3WfLxOgY",
+	"u7XMU7u4GIdmpfYPIybkIOvHSTjSCb8Wa1nJwdJe80e/6hNezRO8/fBEsAQZTtC5F2mBg1NuIHDcBq1G",
+	"kRs0Bcq38/IGcf0B1UZ/0og/ahMILYpLTScJErmgE0W0ILQoMkKrqmRGd1xrkAQ/iLD946CgPIfrUghp",
+	"gA8YafFec/WWO1R6bwN1OkbUwmnM5swnb7+7HI32nWy0/3qO/1oJyIzxE/twvw2VhJm4hTV4sS+tR80N",
+	"K9QmSEnQZhfTvLJCJTaweoqLWv0lFWl71eeQS9DklpbzpmHxkjBNcsq50OQKiAQtGdwafEwoa1hWgwkt",
+	"S5CLy4Ofnu/+6e74H0dvT98cr2VOC1AaAa/nVclyquFVOVcaZJtN1whAPaWalELcEFqyG8gIhzsjOVAv",
+	"P6EIbC9FJSyL6EkvwFroWQdfmGElhGegRDm3iFyG8Qag6s3P+HInN38p//YGo4ASEmJl1+iO/eej/d3v",
+	"j0aj0cF6oFbi1i3WwZZC8PEtcP2FBiW+bNeW4Q+OoPEXMuclKEXyqVBNr2etjdmYtQWEBNjV8EmT+Pd4",
+	"+CPO6R8UORjpKaHa+UY3QKZirpJmJfDi0uu55mRvjE9V0IVfMRisZaQAyYzYuZZiFu+vW7IC3WHzLlu6",
+	"X2DkIijxNH/6AiP3NVNVSRfEPM0ILZWo51jht33LpJ4a9JxSqRcPZv+2lvbieWoplRQa8qQz8v0U9BRk",
+	"PdofPPyEAxT176SiSt0JWcTTXdNSQZjxSogSKHpvSlOpO0jlL+hdPRCt7O8ejNbTygoXoMkb8S7EeOsU",
+	"DGkzFFfUXyPgSGtFlRu0E5S3ICewCpbmNpy8Vo0NQKE7M2MQxrUha6aI4E0J/E3251jW9mCjFSphw/XV",
+	"JvZnSmLadOkd2cGs0gu0YxW0ZPPDSeN3cLckhzeQrubj0kvYZz/88MMPu2/f7r5+vROBb9mHML3MQN0S",
+	"tm/YCu6cvJvNlSbOprQTC0k0vQFOrhaEcoGypCWV+og/L12SszclEHlmzEMw+PiG5FMqaa5BqhgZVp8T",
+	"plNzrRJOZrZrJh8O1U5AtQVSi8jfwV13UDa9M99PndpGc5wwRa6FfNqAYSNW+DG9rPMplQnojz/RXJcL",
+	"IjgE1ZbZvb5kBaG8sCEE8w+kvCswWiAzRMfNR9dCjjnlLnBKSsZvxnyQLaHOj5ESgSgWfIRFC6IQ0l72",
+	"AS1LcXdpp24PfWSeklummBbSuckIZG3gmn3D+YrAMW1F6rGxAnh8ZUPg4VPFZMrH/34K1rQwyCRKi0qR",
+	"OyFvGJ9kISZOyQKoJHQKtCDPCrim81LjNt4B3Oz0jlLeMF6knFpcjY3Nkeb2Wtcwp3wJnVdzK5cUmC+M",
+	"hAFuwsA/Dux7g4+J6Ut6BWUKBaLGgGWpjMBwMiTjwdGca/KWLsaDThuunw6KtqufaPigko50p9g8dU8y",
+	"khSWKfClKNdKgjPzzn02mCuQaZn0RkwYJ04s1IKIlixfb5mFYSON4ABLyZbax29bBXStW+ReabieTbVV",
+	"ijvGJ0TMrZTNKS9KSOIuF1xLdjU33J6Ycy5RM/oMjyJ3Uy8RwEg6wgrgmuW0JLmoFoNNonVpRfbB8odh",
+	"PaXprHq43IJ1zItLqjvSbAGZ5I4qYhRx4eWdllRNMyJ4uTCi3PCWc/SLWoX2AwO8X76kVMzPSH6EKiVy",
+	"htm8kOVLbPJa26Snn9ka+sXzaC2bOprLIYTNzdGK6mmCLs7eEPPE70h73D1Ll2pv7QToLOH+9CEEtBMz",
+	"wq7JDRd3vMNWutj/5nD/+eGLP/YnyBUR4Rh5GWGclOIOJMmpgkQ8OBuoCiCfbhYt90y9OhlSgVSCN/k+",
+	"jf+/iSknr0VPNxZ3OXizARYnFjqFZtpvreOyTxdixUHe+YxD2+rtChOTa8ch6kFSD58V7nfQd3qmdIUz",
+	"6B5GbkVeApXWZ+mjg2b00xvgE8Pkz0ejUX8RWXtU1qtbA8Ba+ZjmvzOoSpqDIrQsMQGTobGKc5UMKzCM",
+	"ajDRfpg9BTPCXV1h0eHO9ufDFiGcOeNpiVJBzphSTHBSwi2Uh+SWwR1IRa6kuFOATk4h7riBKyOxFWGD",
+	"ixZi4+UUBAqGNMAkEXfc6cuM0GLGuCIzyukEoxpyoaeMT8Y8MoPxpUHDThlkAwvM4GO87OYbLYSfAy5n",
+	"g7IhaxA64d1PoudzKZOkW4crmSJMWR/KQuQFrSvsSXtTXW5PUFd+MOBFiDxKuBU3UJAruBYSHroGw80Y",
+	"k95+P8Mh7T79Vdw1VlKKyQQKwvihtYKoCXRJQnOMcmaxG+pSgWNe1c4DXw6+CEmo/aB2uIOzpVD1TNwG",
+	"eLcW3+5wwZS+ZInc1KuSmVlpUUizBYnKrSX74WA4Gu7vHwz/NOiaRgHwjp1PDP+4lWAfnJ9jsFnjkqBH",
+	"6gWysY0Rk6qPI+WwvCJUEDbazvXMkI/9Yadf0MDs7iWdJBnzWxRnsmOjWrDiUKtBjanUAWt+6gVrylAy",
+	"I9SGkie8xqrigqCaZGqxUQumlCngJGPasnLc2N+2cqOtNU3CwEmQfOBts4hYJBLwVYd+/HvnIaJjXoyH",
+	"+NiMLj4zQLZZmZ2h/IfzhNcF55YX4PCIf+98YaAuaKx2oC69wG8er4ywliqb1xJ6JbaSF8z4fzcv9oza",
+	"GXdzwm6BEy26a2ytKZWI3Pso32fG+KJtx7fdtuPfOxu58HOZWOmFX6UWZGrsQjFvKsOp1pU63Ntz6nzo",
+	"ngxzMdtDuPaOwn9v8f///Mfb2fuj4U/PPw2Hw35+pxOndjeW+L0hMYNENYvpFFJ/T9oymN+gjsTNa+rQ",
+	"R1Aj00TIRgzfGLWYIzBRpjG3MBkXykkWYwUZb0oBmF8pX5Bn9qWdhjETPHMne3w544qIMi6lQwEg+P3F",
+	"v3l9vfC3g6awekEnr3wxwXK+tF+NQV0ZTyfL1mnfKNZF+3Pv1vUq/8wG3SW5F3SSRrX3R3shOuBpHa5x",
+	"1CQcdXKsg4A5OTo9cTk6o+kKcZjy/8gzCbTYyZqen9sNT6JI2bWTR56hZ9ekXDNMTaiZc/5SBHth4rGP",
+	"HQsyeNTADUouC7pQKZ2yUCE1orT5F+N1wNh5XoRpwhSp5nICRUZGJpTIDSoaWme01hwMEn8JrNTm2jD6",
+	"Gah5mcBRAYUvvyvSOkFFkX0tUGZ9YkoHcRT0hdEdriKBauuA27C5WTRXGmhh3r1CmaW0kFExZ68N+uCi",
+	"jgaq1B5dU1Z2rQLjXy73JuZl4YMmit5CEQPQNz7TiS2/MmXKMGrd/AArXM40eUCy5i4GRHRTg5uhRQ0+",
+	"ILnCOqCKKODaVEtgMA+93IZ0bIYvP+NgkcOgU5KYYPGrs/GSJgH2n3wJgWG1WXelfjppueHJmNyXYz3t",
+	"2RgXy6iPlvQyZx86h+qUwQoTFgH9Q7LqsF/OFc25KPGKS1h96svsa1prmHH6Kw0zznpOVV21z+bzrtj7",
+	"6oKi6jGz4+3wsInOQD6XTC/OzasWxCugEuTRPJWmqw0Gw8H2icqs8KAKKxH0VEj2H2SYQ/ItjkXG89Ho",
+	"IMcP8U8YD4bkYgpjjgU6jTMCpGQzphVh2lZMoZGckdpeFtLGlpnSEqfJxpwNYYhD2NBxI2QdPiAGV7ba",
+	"wW3kDLgeoo2CqEIfHiGuMW28Fgxj2YBGGi0uKrJreLUgdK6nVrmax2SuDNS5EDcMFC6bhFU3IBlzFzo2",
+	"i5vNZwHeEHd6tmp5Oy9tXM5F5ZvhUQe+IlR6JKkhOa/jbuEF654oqpm6Xlj3xizaoolxDMWbpXgBG45q",
+	"7NYhY884Ffs7LEIQMI07tFMtLmqHPbNUUGfofYGxti85PPr6sjFHcUTzHCrta3VpHYKvgMfZdjPDkHzv",
+	"HS7cDMoXY+5ra/xAL8l4YC3i8cDvlPKeH9odairuQmky5cWYj50OHw+w9AjfQjfUi0gVofKnOchFjcml",
+	"2huPP8OozJWTmJ2nua6L71za8Tu7Ca2Cz8ERUcwIXgukD6bbk0iOQm0Avkm1hoaMLiXBXNdMl635jAsx",
+	"yAa3IG36ZbA/HA1HBgqDdFqxweHgYLg/HLmcMEqYPfO/CejuOnbkAsp4ALcySSSEMygUI1YR1pNicDj4",
+	"DrRHgZlJ0hnY4y8/Jqwe7Y89KGPy1EnHjm0JCQOUpwmD8j5bO0kjx9cxT5Qm32Cq96ZqpYrOaBoCNkGB",
+	"LHFKMzGrDyDUU/YIZK9dr6aTDL1KPOdV+WhLCgDridfT98+GdwVw46MCGEhxxYA7KCzL0gtVF4yzx/A6",
+	"QLuciQIa8IXkJVb00bJM+LD3H7OBBFUJrqxWfT4aefZ1mQpT7LU31bOyboGQ2u8WQ38Xs4QEXoCEgqh5",
+	"noNS1/OyRCFwMHqeSn4XTEKOmrVEY874qkLHzA8YzvzjaNT+/IRrkJyWRIG8BUlASiEbZgSyW0NV/hiy",
+	"uWa7Ytui+STSED9+NNhT89mMysXSgnG2vfo8bVKKGBsQt9m+R5SQRitcLTzzNUWHef3Ijrh20yKxufdv",
+	"JXhz79Ye6jVTpfbUTX+fDV6M9hN5OU6dXQUFedbasJ2vvmNOSw6Wds5thF9bJZTucK+grsLw8qi5Sfat",
+	"I/fM5fC+FcXiYTfIniq/bxr7Ws7hvkUa+w87cydZEO/yIHUkN/mWlswnxZDEv4SQXowOEqECIa9YUQAn",
+	"zxxerKlaG9YPTYQuOtimQf+gQWk1EVGHzCAm9n5mxb2FCo9uto0O/D18+zI6LYdRPx/0mwT7okma9ntP",
+	"mittj3aPC9Q6cbXcofV8m9S3mXZu654XqaMAhlosToqvRjAvukEzk12LOS++KmEtEYeBJalzvgMdXrKG",
+	"akRFLTusZbtuNfU8sBJ0/Uo65d0S9r6QNp+cvh5Me8Yk5Sqnc3Sgm9RjQ03bRkBfXz+PHl8/f/C1WH6L",
+	"tl0/b724PXMJiS49vlfn/9LW5FFVAV8u1zG/xIQ/JK6lBy0l0GLRaNyEnSZ8FYZQzAw8bMnso6JAvJ36",
+	"cMSvl/GiUvhfFuO9E54O0NHfRq4TLlixJex3VMSs01A+c91xJnIKsXFjA7NYSY917BivZNzWBiSa1xEI",
+	"x21zaLPZGeCov7NamtU66UpavK3wGsM2FQIUkt/M2BdxtL2Bx21WWU02ioX5V1ZmuAlLKF2n2/Z+9jns",
+	"lV7rme0l4MtTwpH/4MM2K1a6nFc7TM1f28Fe2coza+GcVXr2qASgG4b18doXXSe87AmhYgnlvzPI5zBI",
+	"m4ab7OGL0HZNmL0za+RewpivL0wT0pCIhlB1onYIVYSSf56cEirzKbtth4L9SEdl2U/b/J5M+j2Z9PjJ",
+	"pBZs/2PzrVFZbC74LWCaQ/guFK49xF+PT15lRAtSzWvWD/SfAtRtWQymW/PgcCAkmzBOy6iwM/rp3xVM",
+	"Pi8dFhtN/7FnrxJEdMU4lYkK+LaLYNgcE9jOAq1LrR8+oFQ7GlqEuln79h/bb78FPRW2WBELA2BrI1Ap",
+	"wUqVkaBOOocSvtW5ON8qMqLXpWaaRsUpxLuQWFY2qyQoBQXJRcVA7WRjrqxHgtovp5wUkLMCyN2U5VP3",
+	"mm8UOeYt0W4AeV3D+4hea7s7Z4I83wGVuwF/AUNLTUV/c3kcJBfexI3n2ibF7UlQoryF7lDQ310cB92c",
+	"YAXY2nYb1TcPsTmYGpIPoQmKuA4mnv1kzKkEkksomBOwdTv2G6h0ffSjiP5ta624IKXgE5BjLqGyiWiq",
+	"4uJXcU2A5lMLyJAc1azmukuZ78PBMzqDMedJ4hkmqP7MImmJ8B/ecU31e/1cD9axDXEbvD7xKcOcTuXF",
+	"MsZuwjJRLaF9u4z30y2KCDkCIpSkic6yZd3NsVsJRC0dzQHLqxICUVsAQ4Eek6QwU0jKJ5CNeTRYFjet",
+	"RYazf2Ex9pCcgZ5Lrsjfzt+/I3dT4P60LxRjfssoTneE1X1kCobdM0sAd0xBDSExcxhdiTUnww5tcmwX",
+	"/YiapO7labD8uQVDFk5rv1dTegX23Ic147+sVIg8++vF2zdYM7rzq6teOW6QQkzooaQgpOSWJNjUUC7S",
+	"k62Wb1BwTdtEyAY9R53zhmOOuRBjtkq4kyyck/A9fY1NVmdJh+T9jGksfmVQFrZyYcznPEdYipd1gQ3O",
+	"vzQz9lhRhOkUsdto+7FzXHuGh7yf+wuKvsb9XJ8402GRuyLTYdG5ThPiW6TABLvaLqV27A/FxmTX0HEv",
+	"Rn9uf3YUd28N+bopVTY24TrmYH/gSMG4o2lfVXMeF0wHdm5Jj71Z6IucNF7fYmCstgVDpwAVqMGlN5ny",
+	"yGkYtmPeeHdInBpw3VbCyYD6OgaEqMiIbR7OZDjkiacZyZXxyiDobtNZN8/xGOFL39jWQBgGRvs4JU6w",
+	"I3RQnpuIk+gegF+aWMFFb41UQWj6ChXLXQ4zttFOTFrIjDVBbaPU2Q5TGpHu2NHKAzSseh2GwDeJIeFU",
+	"9RgezBs8WsU5Dr+y3ryJp6ZHHz7uLgE+lcIMFq2SqPmVa1HWWvCpUNGK+/Drp927u7tdM+7uXJbAc+HO",
+	"+tZIWDoLquT1ZcftNq/Oz/7iTh7B7AqKAttY2ZCAkLPM5lGN3Rbl3XbNiGNuj0699LFsf27uH7tm0F17",
+	"FMr6JV6FDck7oY3HD4U/whQf3B/zzdqWH7nORfVJw2aTrUZDrVicj3lknL5MNeDC5ql4HAr9bnycBq/7",
+	"hKkHz7/xkpRAb6HuBViKCToyyp51q890dSxj7THT8GL7JOfnyOoHYDFcEsofMgOlHOOsddL8ygX3vGm2",
+	"8IrmN7ULJzgxx8jnEiJh+wDQe01hQDZMyyek5hKc6vmfH2iqCyHIzKRh7Hl4QrU2xKF8NpYpd3zdAGMs",
+	"TVqWL2sRaprITYAwhaTKA0M18I1Ua9kQt/kMtFzsHl3rVAPHc8iF7cqnWYkTcfikPVyEqRDkTyTiatvj",
+	"fpUMPYoUJfJGUB/C3YGXFKvHvGjEV4yBKCOSqRULiucOOSuwj8+vXtCmRFXiyPT9sgw4GB2sZswlLD8l",
+	"461Wy9ihCQkpKqpMBzSOqqpctDqC1Ef+ZehargV6LY2Qu4vKajHmLt+voIQ8vE9yISXeaBOlz9cFNIbk",
+	"mKFnGOemJIT+iO42hu54xmndb+Xhbf7lGxif2PCvmymvCCnE2dCV5r+mBdX0UQ37KBlqSAJ7yka5dVfA",
+	"8ZUi5nHv2YSx33zcjgF4BJrFKECV1MhpbVT29TYumHnmM+8Z0dP57IpTVqKU91PuNDrcD8mJvaXiCkw2",
+	"yrVksYorbmLkr6fEtrWuFxGpQDJRDMmrerVqzG0e1scc0i2AawZNMaM9YNOr9mybisDa9wc8JX/UKPf9",
+	"+35pnOHPVVn4G8dbOmPpK9ROfYfJBoHw0KfPj1JHwRtkjjxjRNKYLxM5gU+YTLp27pKHrSfxR5poO4j/",
+	"kUqav6IWtM3f1mjB7VWCv2AWR+VnSbVGn1F4EniBx2rU3s+m6Od+Sfclo2HnBmhjOua0hGLXVFmR27oM",
+	"LmI+t6fmUgsFrvqCKFYAdooxE5KKfYJS2T5kSuYKtGvMw/4D1ow049U6FT96djAa7aB6xfB6Lvg1m8yN",
+	"Cj07fvf65OLk/bvL85N/Hp+H40Vqhk6JCYhYvwXHMcPjFhTEOKQmhJZF9Xu+gDUU72Hd6t9Oj79LSRBE",
+	"y5lH6Doh8iZCRmhM774ljDu8pIWKgb2PQNmuKu5lacJmdAJ7//XF5YVnNdrMiHYhD15Y+IHjpTRIN1ni",
+	"sJD1cnRHQcXTpcotc1auPN4TIzJ73GJ8TbeOXLNb7yv7zzLb5V5CDtwcE5orcKmlLAT/xtw35DfMeeV6",
+	"vuspLMgdSNf23X4oxcxUiSSzCF11Hud+BY+oquIu7amD0RY1AZe/zbrAevkxYa3t72DCYLRJVrbHAwbq",
+	"fF4LU8p1gHlCGa8pZcybyEiX2pn7OM5DE7Seic26a9pXawXhYPY3imxXBs8Dty3lcAZFdW85R4rYvvxn",
+	"jLqtsGA0lbr+tKPF3VInvOWg7ZhHwU5UCVpExo9NIRizJrqestEtL6fctcu7stkdW5D6L5zyXwSPApBA",
+	"u/YYqD+4CbyoBMNOhXXv33pBNuia4o33FfBz19xuJV+cswkHd/+HBTjNGP5Rf9MgW3ElnzVEzHKvAk4r",
+	"CbvXzISg+DJ+P/tkz8fPiR3HPfvVw4aPo6wTfKpKd0pC++t7fTtEZp2gjNj++a5G38mKFTFms+sNYo64",
+	"pYc9UH+nMsJ4Xs6x/2cAgsNSuXy3Zk/rdQvHY2r10Hg/gfy6+eZvVp83MbC2PZjZeGwGK4EWu5hw9jdO",
+	"UGKSHyU0rmu2B7Su5jMjNKnGi0jqixYiORrSxUPyxkDje6LjICaFZOSlkZrm9uTWzQ1aYMToQyRwbTZ8",
+	"6X1fT5X5O4HdPQ8bGaQWHV6WPkbAJtyM/cRd0KJJuxildys0TeUEdGZvrjJkgLSz2MIy/2VS3RI7x3Nd",
+	"h/Du3VMt+n5I/sffq2SuG3VNeZkmpVDYT904cGw2g4JRDeUCK6B784WdsJeN0XFD0Nczv2v63k4LvIZv",
+	"24zwbuLc+0l2WhhnWMRWmziRyU0V+e8zkosCQtKaVJJx3bCnieDWDtf0qgRns99BYaT8RtL8O7BWyH+f",
+	"vRLFNhFuy1o+mTnHYkZ1fbl+xSc7/Q8RhyuM8DIwdTvpddb5e1ZgjU5BpsAmUzz7cPruOxt3U3XUsobq",
+	"j/vPu6ByQcwaphn9ZHqtm8tqX3yTDVzr9cHhfh+kxLd6Y3mKrm/7yoIHUR8ZfAwfoivEaZC8YZAzc5+q",
+	"28n/+7SxM+H4xu7LWg3tKElIjG3+LvBWCrwIIIdlJ+801Szvnz2x75NX5+cZ+du5PbBqj1eYOIBW6ezC",
+	"OX61SX7SzfPkwf3/eoDA/nkEexeBmItzYtJY4QM3EO+wjHvnrwNLbtexvwzHvGW+lvivUO3qek3AJ+3i",
+	"43FY3ER/6FwLY2OXoFMl1OfzyQSUvrDNKlbu67cwYRzjAm5zsRlHfRes+akGYUX7i58260Ty1gpmwsMF",
+	"cMoCjbdUBFm/P+oS9RheS8v6/dFojah/zCao/oa4VHUrncTL/AKx+GSZHwut3XhL2D5rqjZJ64av1iR1",
+	"a2nevLBiJy27LvywW1hb9FipybDmR01Nnm5/JjLQlCNMEzPuEXcMhyuWwotZHDpikijIJWhyS8s5qG6z",
+	"P33fwOnJhQXnMdtnukk6s4phob/RAGQTAavjj2jmF8QO3b4Ey9RWKksLhLn4n8RuBK7MA48m+K221AKq",
+	"Po9pB6S5tk3K8LonO2Moxhxz+1KwAvz9UEwre4vRIUZGzQj+oijncVA15lGljqtOw6nMH58RgfS09XhB",
+	"yDDDE8ch7fqK5vQpmihq+ql7liMFrPV+wiX7Ztd+y7c1BAzGInqDGxvC98scxTSeEGoEFPuLaBthimi8",
+	"Z1ymXs7XvNkhUOVWhhNr8LYrmtgmRqzqXtvTpr7jIZSCLxctWTp2DlrSHsAbjx/TFqivVE7Is9P2En6j",
+	"FoHfqfh8BuKj5/GMU5AzyuNdb3fo1W6vl07bmaMXv7hjEFVrvcW29tJapu6vRmi404Q2aS1NanvulM66",
+	"PiGODoxv4qM09oZyc/yXcS3ibHeqSZyZ5BdHfA45vxiKW9ngxtWsh/PsBs9oJuLF2ArLkiiP7m23H5iy",
+	"xSsAXsdJsBwh9ITBu9i/cgM5s0lpep/zUuQ3K27zKIq4rU8lhbbnRK8WURw09CBoBh0yooQ/seOv85pi",
+	"QcZSU/Qs9Gy1FcS2J3vyvA7CGzV++737xCbdJ07dEyNABEcPaCak397H7tXQ1R5GEUuGRkZg40DbQMAe",
+	"N+1Vohcu1UVX3Y7mkPGwcumdiHruYEShbuQhpC/UW9uHYXVLhcBOvrfC1++G8DnxwoYUsozbaL5jBWa3",
+	"7HH1XDGd1grPeZn+XJO79URMJlDsMm7DN+a0UfKGfEy73XGQSx5pfKt3V/TFQtXj7PpsXmpWUan3UPIY",
+	"KH/FMgc39jLdVCbK0puAnZATytl/6gK+rtZoY45RPE1vfBbCdRkJd6VjVWEQHnoKs5e2NMNeJa7iho6E",
+	"lnd04anASQ4mV/XJqdsypCwMS4Za1Dd0h173a5MG7e73TUFrJ36Yljif77NaWj8DNS87GhkgKh2XZ3G8",
+	"NVSn2yNuTlxjR1j7y0/uqnzsBavIMpBjvlHTHSv4655Yfk+64oDf0sLDYOS/20tvwu08nUG7LHJ2stre",
+	"so3dxtzzQ6OXTMFsB3tL/au0jwtbj/lGDfEf8lTqcubIbc6yisBfG56/4/qNjqdWkLNrli/nLc0ImyYv",
+	"t8Mhy9JLNfOZQ6lxvpaGbsyrL6MwxKIVyUW1IFJojKX7WoLjf5z8hQjJgGtEB3l29v7i6OL49eXrk/PT",
+	"N0c/XL56f3pyfL6zzOW+FwZhJiOCxCv4Vl1x8VAJ3lr+/8bSuk2ecjyqnF26OqlrXiPUtpeLb4T3neY2",
+	"zN9+wFkfU/cpkJ0NfOK1/EYDtXbb12dt433fYI/t9wbRj5fg/GAbuT1pcrOeM0FSfU9XeJ7JGt0iDSK/",
+	"YiQuFVqzu+9iaq5bdOghbeNq23HKou7pZ/7a4HhFk7wvGrGu9tX1zk2lRiYaeLB/k+v+bKcqNioFsGA4",
+	"LumZIp0rkGnr5Gmyo0jpjVRFx+WYzocnuoGy7Yo142K266J8R8t9+jnZ+hUZSZHrFkmvawKIw/nQQxgI",
+	"eGFbLwVXPaI+5IIxr/eUxFwwE5tyge0ktF1c8PAKy6zv67Rx6tRZroOTJ7iVOmuLNNV287HFaq2THmJw",
+	"hDbFEK/hFkpRzYBrt6ZBNpjLcnA4mGpdHe7tlSKn5VQoffjN6JvR4P7j/f8OAFzxB4w+zAAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
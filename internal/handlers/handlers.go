@@ -2,23 +2,34 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Neokil/Gallery/internal/api"
 	"github.com/Neokil/Gallery/internal/service"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 const (
 	maxUploadSize = 32 << 20 // 32MB max upload size
 )
 
+// Handlers implements the API operations. Logins and roles are checked by
+// middleware.AuthMiddleware from the security requirements of the operations;
+// handlers only check access to individual photos, events and albums.
 type Handlers struct {
 	galleryService *service.GalleryService
 	authService    *service.AuthService
@@ -42,11 +53,7 @@ func NewHandlers(galleryService *service.GalleryService, authService *service.Au
 
 // HandleGallery implements the gallery page handler
 func (h *Handlers) HandleGallery(w http.ResponseWriter, r *http.Request, params api.GetGalleryParams) {
-	// Check authentication
-	if !h.authService.IsAuthenticated(r) {
-		http.Redirect(w, r, "/login", http.StatusSeeOther)
-		return
-	}
+	user, _ := h.authService.CurrentUser(r)
 
 	// Get filter parameters
 	var eventFilter, uploaderFilter string
@@ -57,29 +64,99 @@ func (h *Handlers) HandleGallery(w http.ResponseWriter, r *http.Request, params
 		uploaderFilter = *params.Uploader
 	}
 
-	photos, err := h.galleryService.GetPhotos()
+	// Upload links get the upload page instead of the gallery
+	share, shared := h.authService.CurrentShare(r)
+	if share.UploadOnly() {
+		h.renderUploadPage(w, r, user, eventFilter)
+		return
+	}
+
+	access, err := h.photoAccess(r)
 	if err != nil {
+		log.Printf("Failed to resolve photo access: %v", err)
 		http.Error(w, "Failed to load photos", http.StatusInternalServerError)
 		return
 	}
+	if params.Album != nil && !access.AllowsAlbum(*params.Album) {
+		http.Error(w, "Album not found", http.StatusNotFound)
+		return
+	}
+
+	filter := service.PhotoFilter{Event: eventFilter, Uploader: uploaderFilter, Access: access}
+	filter.Tags = tagFilter(params.Tag)
+	filter.AllTags = params.TagMode != nil && *params.TagMode == api.GetGalleryParamsTagModeAll
 
 	// Apply filters
-	filteredPhotos := h.galleryService.FilterPhotos(photos, eventFilter, uploaderFilter)
+	filteredPhotos, err := h.queryPhotos(params.Album, filter)
+	if errors.Is(err, service.ErrAlbumNotFound) {
+		http.Error(w, "Album not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load photos", http.StatusInternalServerError)
+		return
+	}
+
+	// Totals and filter dropdowns only cover the photos the session may see
+	visiblePhotos, err := h.galleryService.QueryPhotos(service.PhotoFilter{Access: access})
+	if err != nil {
+		http.Error(w, "Failed to load photos", http.StatusInternalServerError)
+		return
+	}
+	totalPhotos := len(visiblePhotos)
 
 	// Get unique events and uploaders for filter dropdowns
-	events := h.galleryService.GetUniqueEvents(photos)
-	uploaders := h.galleryService.GetUniqueUploaders(photos)
+	events := h.galleryService.GetUniqueEvents(visiblePhotos)
+	uploaders := h.galleryService.GetUniqueUploaders(visiblePhotos)
+	albums, err := h.galleryService.ListAlbums()
+	if err != nil {
+		http.Error(w, "Failed to load albums", http.StatusInternalServerError)
+		return
+	}
+	albums = slices.DeleteFunc(albums, func(album service.Album) bool {
+		return !access.AllowsAlbum(album.ID)
+	})
+	var selectedAlbum service.Album
+	if params.Album != nil {
+		if selectedAlbum, err = h.galleryService.GetAlbum(*params.Album); err != nil {
+			http.Error(w, "Failed to load album", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Share links that allow uploads fix the event of the uploaded photos
+	var uploadEvent string
+	if share.AllowUpload {
+		event, err := h.galleryService.GetEvent(share.EventID)
+		if err != nil {
+			http.Error(w, "Failed to load event", http.StatusInternalServerError)
+			return
+		}
+		uploadEvent = event.Name
+	}
 
 	// Render template
 	data := map[string]any{
 		"Title":            h.siteTitle,
+		"User":             user,
+		"Shared":           shared,
+		"CanUpload":        user.HasRole(service.RoleContributor) || share.AllowUpload,
+		"UploadEvent":      uploadEvent,
 		"Photos":           filteredPhotos,
 		"AllEvents":        events,
 		"AllUploaders":     uploaders,
+		"AllAlbums":        albums,
 		"SelectedEvent":    eventFilter,
 		"SelectedUploader": uploaderFilter,
-		"TotalPhotos":      len(photos),
+		"SelectedAlbum":    selectedAlbum,
+		"SelectedTags":     filter.Tags,
+		"MatchAllTags":     filter.AllTags,
+		"TotalPhotos":      totalPhotos,
 		"FilteredPhotos":   len(filteredPhotos),
+		"HasConverted":     slices.ContainsFunc(filteredPhotos, service.PhotoInfo.Converted),
+		"RenditionSizes":   h.galleryService.RenditionSizes(),
+		"RotatedCopies":    h.galleryService.RotatedDisplayCopies(),
+		"CSRFToken":        h.authService.CSRFToken(w, r),
 		"CacheBreaker":     time.Now().Unix(),
 	}
 
@@ -90,10 +167,75 @@ func (h *Handlers) HandleGallery(w http.ResponseWriter, r *http.Request, params
 	}
 }
 
+// queryPhotos returns the photos of an album in album order if album is set,
+// else all photos, narrowed down by filter.
+func (h *Handlers) queryPhotos(album *int64, filter service.PhotoFilter) ([]service.PhotoInfo, error) {
+	if album != nil {
+		return h.galleryService.AlbumPhotos(*album, filter)
+	}
+	return h.galleryService.QueryPhotos(filter)
+}
+
+// tagFilter returns the non-empty tags of a tag query parameter in canonical form.
+func tagFilter(tags *[]string) []string {
+	if tags == nil {
+		return nil
+	}
+	var filter []string
+	for _, tag := range *tags {
+		if tag = service.NormalizeTag(tag); tag != "" && !slices.Contains(filter, tag) {
+			filter = append(filter, tag)
+		}
+	}
+	return filter
+}
+
+// renderUploadPage renders the page of upload links, with the event name pre-filled.
+func (h *Handlers) renderUploadPage(w http.ResponseWriter, r *http.Request, user service.User, eventName string) {
+	data := map[string]any{
+		"Title":        h.siteTitle,
+		"User":         user,
+		"EventName":    eventName,
+		"CSRFToken":    h.authService.CSRFToken(w, r),
+		"CacheBreaker": time.Now().Unix(),
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := h.templates.ExecuteTemplate(w, "upload.html", data); err != nil {
+		log.Printf("Failed to execute upload template: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// photoAccess returns which photos the request's session may see.
+func (h *Handlers) photoAccess(r *http.Request) (*service.PhotoAccess, error) {
+	scope, ok := h.authService.Access(r)
+	if !ok {
+		return nil, errors.New("session is not logged in")
+	}
+	return h.galleryService.PhotoAccess(scope)
+}
+
+// canSeePhoto reports whether the request's session may see a photo. Photos
+// in the trash are only visible to sessions that may see everything.
+func (h *Handlers) canSeePhoto(r *http.Request, filename string) bool {
+	access, err := h.photoAccess(r)
+	if err != nil {
+		log.Printf("Failed to resolve photo access: %v", err)
+		return false
+	}
+	if access == nil {
+		return true
+	}
+	photo, err := h.galleryService.GetPhoto(filename)
+	return err == nil && access.Allows(photo)
+}
+
 // HandleGetLogin implements the login page handler
 func (h *Handlers) HandleGetLogin(w http.ResponseWriter, r *http.Request) {
 	data := map[string]any{
 		"Title":        h.siteTitle,
+		"CSRFToken":    h.authService.CSRFToken(w, r),
 		"CacheBreaker": time.Now().Unix(),
 	}
 
@@ -106,30 +248,103 @@ func (h *Handlers) HandleGetLogin(w http.ResponseWriter, r *http.Request) {
 
 // HandlePostLogin implements the login form submission handler
 func (h *Handlers) HandlePostLogin(w http.ResponseWriter, r *http.Request) {
+	if !h.verifyCSRF(w, r) {
+		return
+	}
+
+	username := strings.TrimSpace(r.FormValue("username"))
 	password := r.FormValue("password")
 
-	if h.authService.Login(w, r, password) {
+	// Throttle password guessing before checking anything
+	if wait := h.authService.LoginWait(r, username); wait > 0 {
+		w.Header().Set("Retry-After", retryAfter(wait))
+		h.renderLoginError(w, r, http.StatusTooManyRequests, tooManyAttempts(wait))
+		return
+	}
+
+	var loggedIn bool
+	if username != "" {
+		loggedIn = h.authService.LoginUser(w, r, username, password)
+	} else {
+		// The guest password opens the gallery, an event password only its event
+		loggedIn = h.authService.Login(w, r, password) || h.authService.LoginEvent(w, r, password)
+	}
+	h.authService.RecordLogin(r, username, loggedIn)
+	if loggedIn {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
 
 	// Login failed - clear any existing session
 	h.authService.Logout(w, r)
+	h.renderLoginError(w, r, http.StatusOK, "Invalid username or password")
+}
+
+// renderLoginError renders the login page with an error message.
+func (h *Handlers) renderLoginError(w http.ResponseWriter, r *http.Request, status int, message string) {
 	data := map[string]any{
 		"Title":        h.siteTitle,
-		"Error":        "Invalid password",
+		"Error":        message,
+		"CSRFToken":    h.authService.CSRFToken(w, r),
 		"CacheBreaker": time.Now().Unix(),
 	}
 
 	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
 	if err := h.templates.ExecuteTemplate(w, "login.html", data); err != nil {
 		log.Printf("Failed to execute login template: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
 
+// verifyCSRF checks the CSRF token of a submission to a public operation and
+// renders the forbidden page if it is missing or does not match. It returns
+// false if the request must not be processed. Operations that require a login
+// are checked by the auth middleware.
+func (h *Handlers) verifyCSRF(w http.ResponseWriter, r *http.Request) bool {
+	if h.authService.VerifyCSRF(r) {
+		return true
+	}
+	h.HandleCSRFFailure(w, r)
+	return false
+}
+
+// HandleCSRFFailure answers a request with a missing or wrong CSRF token:
+// scripts get a 403 status, browsers the forbidden page.
+func (h *Handlers) HandleCSRFFailure(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		http.Error(w, "Invalid or missing CSRF token", http.StatusForbidden)
+		return
+	}
+	data := map[string]any{
+		"Title":        h.siteTitle,
+		"CacheBreaker": time.Now().Unix(),
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusForbidden)
+	if err := h.templates.ExecuteTemplate(w, "forbidden.html", data); err != nil {
+		log.Printf("Failed to execute forbidden template: %v", err)
+	}
+}
+
+// tooManyAttempts is the error message for throttled password attempts.
+func tooManyAttempts(wait time.Duration) string {
+	if wait < time.Minute {
+		return fmt.Sprintf("Too many failed attempts. Please try again in %s seconds.", retryAfter(wait))
+	}
+	return fmt.Sprintf("Too many failed attempts. Please try again in %d minutes.", int(math.Ceil(wait.Minutes())))
+}
+
+// retryAfter formats wait as value of a Retry-After header.
+func retryAfter(wait time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(wait.Seconds())))
+}
+
 // HandlePostLogout implements the logout handler
 func (h *Handlers) HandlePostLogout(w http.ResponseWriter, r *http.Request) {
+	if !h.verifyCSRF(w, r) {
+		return
+	}
+
 	// Clear the session
 	h.authService.Logout(w, r)
 
@@ -137,14 +352,34 @@ func (h *Handlers) HandlePostLogout(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/login", http.StatusSeeOther)
 }
 
-// HandleUpload implements the photo upload handler
-func (h *Handlers) HandleUpload(w http.ResponseWriter, r *http.Request) {
-	// Check authentication
-	if !h.authService.IsAuthenticated(r) {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+// HandleUnlockEvents implements the event password handler for logged-in sessions
+func (h *Handlers) HandleUnlockEvents(w http.ResponseWriter, r *http.Request) {
+	// Event passwords are throttled like logins
+	if wait := h.authService.LoginWait(r, ""); wait > 0 {
+		w.Header().Set("Retry-After", retryAfter(wait))
+		http.Error(w, tooManyAttempts(wait), http.StatusTooManyRequests)
+		return
+	}
+	unlocked := h.authService.UnlockEvents(w, r, r.FormValue("password"))
+	h.authService.RecordLogin(r, "", unlocked)
+	if !unlocked {
+		http.Error(w, "Invalid event password", http.StatusForbidden)
 		return
 	}
 
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// HandleUpload implements the photo upload handler
+func (h *Handlers) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	// Share links that allow uploads upload to their event
+	user, _ := h.authService.CurrentUser(r)
+	share, _ := h.authService.CurrentShare(r)
+
 	if r.Method != "POST" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -156,12 +391,27 @@ func (h *Handlers) HandleUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get uploader name and event name from form
-	userName := strings.TrimSpace(r.FormValue("uploader_name"))
-	if userName == "" {
-		userName = "Anonymous"
-	}
+	// The logged-in user is recorded as uploader
 	eventName := strings.TrimSpace(r.FormValue("event_name"))
+	if share.EventID != 0 {
+		event, err := h.galleryService.GetEvent(share.EventID)
+		if writeEventError(w, err) {
+			return
+		}
+		eventName = event.Name
+	} else if eventName != "" {
+		// Other sessions must not add photos to protected events they cannot see
+		access, err := h.photoAccess(r)
+		if err != nil {
+			log.Printf("Failed to resolve photo access: %v", err)
+			http.Error(w, "Failed to upload photos", http.StatusInternalServerError)
+			return
+		}
+		if !access.AllowsUpload(eventName) {
+			http.Error(w, "Unlock the event before uploading to it", http.StatusForbidden)
+			return
+		}
+	}
 
 	files := r.MultipartForm.File["photos"]
 	if len(files) == 0 {
@@ -169,25 +419,37 @@ func (h *Handlers) HandleUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	result := api.UploadResult{
+		Uploaded:     []api.UploadedFile{},
+		Deduplicated: []api.UploadedFile{},
+		Failed:       []string{},
+	}
 	for _, fileHeader := range files {
-		err := h.galleryService.SavePhoto(fileHeader, userName, eventName)
+		saved, err := h.galleryService.SavePhoto(fileHeader, user, eventName)
 		if err != nil {
 			log.Printf("Failed to save photo %s: %v", fileHeader.Filename, err)
+			result.Failed = append(result.Failed, fileHeader.Filename)
 			continue
 		}
+
+		file := api.UploadedFile{Filename: fileHeader.Filename, Name: saved.Photo.Name}
+		if saved.Deduplicated {
+			result.Deduplicated = append(result.Deduplicated, file)
+		} else {
+			result.Uploaded = append(result.Uploaded, file)
+		}
 	}
 
+	// Scripted uploads get a summary, plain form posts go back to the gallery
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
 // HandleDownloadAll implements the download all photos handler
 func (h *Handlers) HandleDownloadAll(w http.ResponseWriter, r *http.Request, params api.DownloadAllPhotosParams) {
-	// Check authentication
-	if !h.authService.IsAuthenticated(r) {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	if r.Method != "GET" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -202,14 +464,31 @@ func (h *Handlers) HandleDownloadAll(w http.ResponseWriter, r *http.Request, par
 		uploaderFilter = *params.Uploader
 	}
 
-	// Get all photos and apply filters
-	photos, err := h.galleryService.GetPhotos()
+	access, err := h.photoAccess(r)
 	if err != nil {
+		log.Printf("Failed to resolve photo access: %v", err)
 		http.Error(w, "Failed to load photos", http.StatusInternalServerError)
 		return
 	}
+	if params.Album != nil && !access.AllowsAlbum(*params.Album) {
+		http.Error(w, "Album not found", http.StatusNotFound)
+		return
+	}
+
+	filter := service.PhotoFilter{Event: eventFilter, Uploader: uploaderFilter, Access: access}
+	filter.Tags = tagFilter(params.Tag)
+	filter.AllTags = params.TagMode != nil && *params.TagMode == api.DownloadAllPhotosParamsTagModeAll
 
-	filteredPhotos := h.galleryService.FilterPhotos(photos, eventFilter, uploaderFilter)
+	// Get filtered photos
+	filteredPhotos, err := h.queryPhotos(params.Album, filter)
+	if errors.Is(err, service.ErrAlbumNotFound) {
+		http.Error(w, "Album not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load photos", http.StatusInternalServerError)
+		return
+	}
 
 	if len(filteredPhotos) == 0 {
 		http.Error(w, "No photos to download", http.StatusNotFound)
@@ -219,14 +498,20 @@ func (h *Handlers) HandleDownloadAll(w http.ResponseWriter, r *http.Request, par
 	// Generate filename
 	timestamp := time.Now().Format("2006-01-02_15-04-05")
 	var filename string
-	if eventFilter != "" || uploaderFilter != "" {
+	if eventFilter != "" || uploaderFilter != "" || params.Album != nil || len(filter.Tags) > 0 {
 		filterSuffix := ""
+		if params.Album != nil {
+			filterSuffix += fmt.Sprintf("_album%d", *params.Album)
+		}
 		if eventFilter != "" {
 			filterSuffix += "_" + strings.ReplaceAll(eventFilter, " ", "_")
 		}
 		if uploaderFilter != "" {
 			filterSuffix += "_" + strings.ReplaceAll(uploaderFilter, " ", "_")
 		}
+		for _, tag := range filter.Tags {
+			filterSuffix += "_" + strings.ReplaceAll(tag, " ", "_")
+		}
 		filename = fmt.Sprintf("gallery_photos%s_%s.zip", filterSuffix, timestamp)
 	} else {
 		filename = fmt.Sprintf("gallery_photos_%s.zip", timestamp)
@@ -234,50 +519,90 @@ func (h *Handlers) HandleDownloadAll(w http.ResponseWriter, r *http.Request, par
 
 	w.Header().Set("Content-Type", "application/zip")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
-	if err := h.galleryService.CreateZipArchive(filteredPhotos, w); err != nil {
+	format := service.PhotoFormatOriginal
+	if params.Format != nil && *params.Format == api.DownloadAllPhotosParamsFormatJpeg {
+		format = service.PhotoFormatJPEG
+	}
+	if err := h.galleryService.CreateZipArchive(filteredPhotos, format, w); err != nil {
 		log.Printf("Failed to create zip archive: %v", err)
 		http.Error(w, "Failed to create archive", http.StatusInternalServerError)
 	}
 }
 
 // HandleServePhoto implements the photo serving handler
-func (h *Handlers) HandleServePhoto(w http.ResponseWriter, r *http.Request, filename string) {
-	// Check authentication before serving photos
-	if !h.authService.IsAuthenticated(r) {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+func (h *Handlers) HandleServePhoto(w http.ResponseWriter, r *http.Request, filename string, params api.ServePhotoParams) {
+	if !h.canSeePhoto(r, filename) {
+		http.Error(w, "File not found", http.StatusNotFound)
 		return
 	}
 
-	filePath, err := h.galleryService.ServePhoto(filename)
+	serve := h.galleryService.ServePhoto
+	if params.Format != nil && *params.Format == api.ServePhotoParamsFormatJpeg {
+		serve = h.galleryService.ServeDisplayPhoto
+	}
+	photo, info, err := serve(filename)
 	if err != nil {
 		http.Error(w, "File not found", http.StatusNotFound)
 		return
 	}
+	defer photo.Close()
 
-	http.ServeFile(w, r, filePath)
+	http.ServeContent(w, r, info.Name, info.ModTime, photo)
 }
 
 // HandleServeThumbnail implements the thumbnail serving handler
 func (h *Handlers) HandleServeThumbnail(w http.ResponseWriter, r *http.Request, filename string) {
-	// Check authentication before serving thumbnails
-	if !h.authService.IsAuthenticated(r) {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	if !h.canSeePhoto(r, filename) {
+		http.Error(w, "File not found", http.StatusNotFound)
 		return
 	}
 
-	thumbnailPath, err := h.galleryService.ServeThumbnail(filename)
+	thumbnail, info, err := h.galleryService.ServeThumbnail(filename)
 	if err != nil {
 		// If thumbnail doesn't exist, serve the original image
-		filePath, err := h.galleryService.ServePhoto(filename)
+		thumbnail, info, err = h.galleryService.ServePhoto(filename)
+		if err != nil {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		defer thumbnail.Close()
+		http.ServeContent(w, r, info.Name, info.ModTime, thumbnail)
+		return
+	}
+	defer thumbnail.Close()
+
+	// Thumbnails of formats without encoder, e.g. WebP, are JPEG, so the
+	// content type is sniffed instead of taken from the file name
+	http.ServeContent(w, r, "", info.ModTime, thumbnail)
+}
+
+// HandleServeRendition implements the photo rendition serving handler
+func (h *Handlers) HandleServeRendition(w http.ResponseWriter, r *http.Request, size int, filename string) {
+	if !h.canSeePhoto(r, filename) {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	rendition, info, err := h.galleryService.ServeRendition(size, filename)
+	if errors.Is(err, service.ErrBlobNotFound) {
+		// Photos smaller than the size are served in full
+		rendition, info, err = h.galleryService.ServeDisplayPhoto(filename)
 		if err != nil {
 			http.Error(w, "File not found", http.StatusNotFound)
 			return
 		}
-		http.ServeFile(w, r, filePath)
+		defer rendition.Close()
+		http.ServeContent(w, r, info.Name, info.ModTime, rendition)
+		return
+	}
+	if err != nil {
+		http.Error(w, "File not found", http.StatusNotFound)
 		return
 	}
+	defer rendition.Close()
 
-	http.ServeFile(w, r, thumbnailPath)
+	// Like thumbnails, renditions of formats without encoder are JPEG
+	http.ServeContent(w, r, "", info.ModTime, rendition)
 }
 
 // HandleServeStatic implements the static file serving handler
@@ -290,3 +615,985 @@ func (h *Handlers) HandleServeStatic(w http.ResponseWriter, r *http.Request, fil
 
 	http.ServeFile(w, r, filePath)
 }
+
+// HandleListDuplicates implements the near-duplicate listing handler
+func (h *Handlers) HandleListDuplicates(w http.ResponseWriter, r *http.Request) {
+	clusters, err := h.galleryService.FindDuplicates()
+	if err != nil {
+		log.Printf("Failed to find duplicates: %v", err)
+		http.Error(w, "Failed to find duplicates", http.StatusInternalServerError)
+		return
+	}
+
+	response := api.DuplicateClusters{Clusters: make([]api.DuplicateCluster, 0, len(clusters))}
+	for _, cluster := range clusters {
+		photos := make([]api.PhotoInfo, 0, len(cluster.Photos))
+		for _, photo := range cluster.Photos {
+			photos = append(photos, toAPIPhoto(photo))
+		}
+		response.Clusters = append(response.Clusters, api.DuplicateCluster{Photos: photos})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleResolveDuplicates implements the near-duplicate resolution handler
+func (h *Handlers) HandleResolveDuplicates(w http.ResponseWriter, r *http.Request) {
+	var resolution api.ResolveDuplicatesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&resolution); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	err := h.galleryService.ResolveDuplicates(resolution.Keep, resolution.Remove)
+	switch {
+	case errors.Is(err, service.ErrInvalidResolution):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, service.ErrPhotoNotFound):
+		http.Error(w, "Photo not found", http.StatusNotFound)
+		return
+	case err != nil:
+		log.Printf("Failed to resolve duplicates: %v", err)
+		http.Error(w, "Failed to resolve duplicates", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleUpdatePhoto implements the photo metadata edit handler
+func (h *Handlers) HandleUpdatePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	user, _ := h.authService.CurrentUser(r)
+
+	var update api.UpdatePhotoJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	if !h.canEditPhotos(w, user, []string{filename}, update.Uploader != nil) {
+		return
+	}
+
+	photo, err := h.galleryService.UpdatePhotoMetadata(filename, service.PhotoUpdate{
+		Event:    update.Event,
+		Uploader: update.Uploader,
+		Caption:  update.Caption,
+		Tags:     update.Tags,
+	})
+	if writeUpdateError(w, err) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIPhoto(photo))
+}
+
+// HandleUpdatePhotos implements the bulk photo metadata edit handler
+func (h *Handlers) HandleUpdatePhotos(w http.ResponseWriter, r *http.Request) {
+	user, _ := h.authService.CurrentUser(r)
+
+	var update api.UpdatePhotosJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	if !h.canEditPhotos(w, user, update.Names, update.Uploader != nil) {
+		return
+	}
+
+	photoUpdate := service.PhotoUpdate{
+		Event:    update.Event,
+		Uploader: update.Uploader,
+		Caption:  update.Caption,
+		Tags:     update.Tags,
+	}
+	if update.AddTags != nil {
+		photoUpdate.AddTags = *update.AddTags
+	}
+	if update.RemoveTags != nil {
+		photoUpdate.RemoveTags = *update.RemoveTags
+	}
+	photos, err := h.galleryService.UpdatePhotosMetadata(update.Names, photoUpdate)
+	if writeUpdateError(w, err) {
+		return
+	}
+
+	response := api.PhotoList{Photos: make([]api.PhotoInfo, 0, len(photos))}
+	for _, photo := range photos {
+		response.Photos = append(response.Photos, toAPIPhoto(photo))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// canEditPhotos reports whether user may edit all of the named photos and,
+// if changesUploader is set, reassign them to another uploader. Otherwise it
+// writes the error response.
+func (h *Handlers) canEditPhotos(w http.ResponseWriter, user service.User, filenames []string, changesUploader bool) bool {
+	if changesUploader && !user.HasRole(service.RoleAdmin) {
+		http.Error(w, "Only admins can change the uploader", http.StatusForbidden)
+		return false
+	}
+	for _, filename := range filenames {
+		photo, err := h.galleryService.GetPhoto(filename)
+		if errors.Is(err, service.ErrPhotoNotFound) {
+			// Reported by the update itself
+			continue
+		}
+		if err != nil || !user.CanEditPhoto(photo) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
+
+// writeUpdateError writes the error response for a failed metadata update and
+// reports whether there was an error.
+func writeUpdateError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrInvalidMetadata):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrPhotoNotFound):
+		http.Error(w, "Photo not found", http.StatusNotFound)
+	default:
+		log.Printf("Failed to update photo metadata: %v", err)
+		http.Error(w, "Failed to update photo metadata", http.StatusInternalServerError)
+	}
+	return true
+}
+
+// HandleListEvents implements the event overview handler
+func (h *Handlers) HandleListEvents(w http.ResponseWriter, r *http.Request) {
+	wantsJSON := strings.Contains(r.Header.Get("Accept"), "application/json")
+
+	events, err := h.galleryService.ListEvents()
+	if err != nil {
+		log.Printf("Failed to list events: %v", err)
+		http.Error(w, "Failed to load events", http.StatusInternalServerError)
+		return
+	}
+	access, err := h.photoAccess(r)
+	if err != nil {
+		log.Printf("Failed to resolve photo access: %v", err)
+		http.Error(w, "Failed to load events", http.StatusInternalServerError)
+		return
+	}
+	events = slices.DeleteFunc(events, func(event service.Event) bool {
+		return !access.AllowsEvent(event.Name)
+	})
+
+	if wantsJSON {
+		response := api.EventList{Events: make([]api.Event, 0, len(events))}
+		for _, event := range events {
+			response.Events = append(response.Events, toAPIEvent(event))
+		}
+		writeJSON(w, http.StatusOK, response)
+		return
+	}
+
+	data := map[string]any{
+		"Title":        h.siteTitle,
+		"Events":       events,
+		"CSRFToken":    h.authService.CSRFToken(w, r),
+		"CacheBreaker": time.Now().Unix(),
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := h.templates.ExecuteTemplate(w, "events.html", data); err != nil {
+		log.Printf("Failed to execute events template: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// HandleUpdateEvent implements the event edit handler
+func (h *Handlers) HandleUpdateEvent(w http.ResponseWriter, r *http.Request, id int64) {
+	var body api.UpdateEventJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	update := service.EventUpdate{
+		Name:        body.Name,
+		Description: body.Description,
+		CoverPhoto:  body.CoverPhoto,
+		Password:    body.Password,
+	}
+	var err error
+	if update.StartDate, err = parseEventDate(body.StartDate); err != nil {
+		http.Error(w, "Invalid start date", http.StatusBadRequest)
+		return
+	}
+	if update.EndDate, err = parseEventDate(body.EndDate); err != nil {
+		http.Error(w, "Invalid end date", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.galleryService.UpdateEvent(id, update)
+	if writeEventError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIEvent(event))
+}
+
+// HandleMergeEvents implements the event merge handler
+func (h *Handlers) HandleMergeEvents(w http.ResponseWriter, r *http.Request, id int64) {
+	var body api.MergeEventsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.galleryService.MergeEvents(id, body.Events)
+	if writeEventError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIEvent(event))
+}
+
+// writeEventError writes the error response for a failed event change and
+// reports whether there was an error.
+func writeEventError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrInvalidEvent):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrEventExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrEventNotFound):
+		http.Error(w, "Event not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrPhotoNotFound):
+		http.Error(w, "Photo not found", http.StatusNotFound)
+	default:
+		log.Printf("Failed to update event: %v", err)
+		http.Error(w, "Failed to update event", http.StatusInternalServerError)
+	}
+	return true
+}
+
+// parseEventDate parses an optional YYYY-MM-DD date; an empty string yields
+// the zero time, which clears the date.
+func parseEventDate(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var date time.Time
+	if *value != "" {
+		var err error
+		if date, err = time.Parse(time.DateOnly, *value); err != nil {
+			return nil, err
+		}
+	}
+	return &date, nil
+}
+
+// HandleListAlbums implements the album list handler
+func (h *Handlers) HandleListAlbums(w http.ResponseWriter, r *http.Request) {
+	albums, err := h.galleryService.ListAlbums()
+	if err != nil {
+		log.Printf("Failed to list albums: %v", err)
+		http.Error(w, "Failed to load albums", http.StatusInternalServerError)
+		return
+	}
+	access, err := h.photoAccess(r)
+	if err != nil {
+		log.Printf("Failed to resolve photo access: %v", err)
+		http.Error(w, "Failed to load albums", http.StatusInternalServerError)
+		return
+	}
+	albums = slices.DeleteFunc(albums, func(album service.Album) bool {
+		return !access.AllowsAlbum(album.ID)
+	})
+
+	response := api.AlbumList{Albums: make([]api.Album, 0, len(albums))}
+	for _, album := range albums {
+		response.Albums = append(response.Albums, toAPIAlbum(album))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleCreateAlbum implements the album creation handler
+func (h *Handlers) HandleCreateAlbum(w http.ResponseWriter, r *http.Request) {
+	var body api.CreateAlbumJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	album, err := h.galleryService.CreateAlbum(body.Name)
+	if writeAlbumError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusCreated, toAPIAlbum(album))
+}
+
+// HandleGetAlbum implements the album detail handler
+func (h *Handlers) HandleGetAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	album, err := h.galleryService.GetAlbum(id)
+	if writeAlbumError(w, err) {
+		return
+	}
+	access, err := h.photoAccess(r)
+	if writeAlbumError(w, err) {
+		return
+	}
+	if !access.AllowsAlbum(id) {
+		http.Error(w, "Album not found", http.StatusNotFound)
+		return
+	}
+	photos, err := h.galleryService.AlbumPhotos(id, service.PhotoFilter{Access: access})
+	if writeAlbumError(w, err) {
+		return
+	}
+
+	apiAlbum := toAPIAlbum(album)
+	response := api.AlbumDetail{
+		Id:         apiAlbum.Id,
+		Name:       apiAlbum.Name,
+		Created:    apiAlbum.Created,
+		PhotoCount: apiAlbum.PhotoCount,
+		CoverPhoto: apiAlbum.CoverPhoto,
+		Photos:     make([]api.PhotoInfo, 0, len(photos)),
+	}
+	for _, photo := range photos {
+		response.Photos = append(response.Photos, toAPIPhoto(photo))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleUpdateAlbum implements the album rename handler
+func (h *Handlers) HandleUpdateAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	var body api.UpdateAlbumJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	album, err := h.galleryService.RenameAlbum(id, body.Name)
+	if writeAlbumError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIAlbum(album))
+}
+
+// HandleDeleteAlbum implements the album deletion handler
+func (h *Handlers) HandleDeleteAlbum(w http.ResponseWriter, r *http.Request, id int64) {
+	if writeAlbumError(w, h.galleryService.DeleteAlbum(id)) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleAddAlbumPhotos implements the handler adding photos to an album
+func (h *Handlers) HandleAddAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64) {
+	var body api.AddAlbumPhotosJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	album, err := h.galleryService.AddToAlbum(id, body.Names)
+	if writeAlbumError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIAlbum(album))
+}
+
+// HandleReorderAlbumPhotos implements the album reorder handler
+func (h *Handlers) HandleReorderAlbumPhotos(w http.ResponseWriter, r *http.Request, id int64) {
+	var body api.ReorderAlbumPhotosJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	if writeAlbumError(w, h.galleryService.ReorderAlbum(id, body.Names)) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleRemoveAlbumPhoto implements the handler removing a photo from an album
+func (h *Handlers) HandleRemoveAlbumPhoto(w http.ResponseWriter, r *http.Request, id int64, filename string) {
+	if writeAlbumError(w, h.galleryService.RemoveFromAlbum(id, filename)) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeAlbumError writes the error response for a failed album operation and
+// reports whether there was an error.
+func writeAlbumError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrInvalidAlbum):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrAlbumNotFound):
+		http.Error(w, "Album not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrPhotoNotFound):
+		http.Error(w, "Photo not found", http.StatusNotFound)
+	default:
+		log.Printf("Failed to update album: %v", err)
+		http.Error(w, "Failed to update album", http.StatusInternalServerError)
+	}
+	return true
+}
+
+// HandleListUsers implements the user listing handler
+func (h *Handlers) HandleListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.galleryService.ListUsers()
+	if err != nil {
+		log.Printf("Failed to list users: %v", err)
+		http.Error(w, "Failed to list users", http.StatusInternalServerError)
+		return
+	}
+
+	response := api.UserList{Users: make([]api.User, 0, len(users))}
+	for _, user := range users {
+		response.Users = append(response.Users, toAPIUser(user))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleCreateUser implements the user creation handler
+func (h *Handlers) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var input api.CreateUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.galleryService.CreateUser(input.Username, input.Password, service.Role(input.Role))
+	if writeUserError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusCreated, toAPIUser(user))
+}
+
+// HandleUpdateUser implements the user role and password change handler
+func (h *Handlers) HandleUpdateUser(w http.ResponseWriter, r *http.Request, id int64) {
+	var input api.UpdateUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	update := service.UserUpdate{Password: input.Password}
+	if input.Role != nil {
+		role := service.Role(*input.Role)
+		update.Role = &role
+	}
+	user, err := h.galleryService.UpdateUser(id, update)
+	if writeUserError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIUser(user))
+}
+
+// HandleDeleteUser implements the user deletion handler
+func (h *Handlers) HandleDeleteUser(w http.ResponseWriter, r *http.Request, id int64) {
+	if writeUserError(w, h.galleryService.DeleteUser(id)) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeUserError writes the error response for a failed user change and
+// reports whether there was an error.
+func writeUserError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrInvalidUser):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrUserExists):
+		http.Error(w, "A user with this name already exists", http.StatusConflict)
+	case errors.Is(err, service.ErrUserNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+	default:
+		log.Printf("Failed to update user: %v", err)
+		http.Error(w, "Failed to update user", http.StatusInternalServerError)
+	}
+	return true
+}
+
+// HandleListSessions implements the session listing handler
+func (h *Handlers) HandleListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.galleryService.ListSessions()
+	if writeSessionError(w, err) {
+		return
+	}
+
+	current, _ := h.authService.CurrentSessionID(r)
+	response := api.SessionList{Sessions: make([]api.Session, 0, len(sessions))}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, toAPISession(session, session.ID == current))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleRevokeSession implements the session revocation handler
+func (h *Handlers) HandleRevokeSession(w http.ResponseWriter, r *http.Request, id int64) {
+	if writeSessionError(w, h.galleryService.RevokeSession(id)) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeSessionError maps session errors to HTTP responses. It returns false if err is nil.
+func writeSessionError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrSessionNotFound):
+		http.Error(w, "Session not found", http.StatusNotFound)
+	default:
+		log.Printf("Failed to update sessions: %v", err)
+		http.Error(w, "Failed to update sessions", http.StatusInternalServerError)
+	}
+	return true
+}
+
+// HandleListAPITokens implements the API token listing handler
+func (h *Handlers) HandleListAPITokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := h.galleryService.ListAPITokens()
+	if writeAPITokenError(w, err) {
+		return
+	}
+
+	response := api.APITokenList{Tokens: make([]api.APIToken, 0, len(tokens))}
+	for _, token := range tokens {
+		response.Tokens = append(response.Tokens, toAPIToken(token))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleCreateAPIToken implements the API token creation handler
+func (h *Handlers) HandleCreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	var body api.CreateAPITokenJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	token, value, err := h.galleryService.CreateAPIToken(body.Name, service.TokenScope(body.Scope))
+	if writeAPITokenError(w, err) {
+		return
+	}
+	apiToken := toAPIToken(token)
+	writeJSON(w, http.StatusCreated, api.CreatedAPIToken{
+		Id:       apiToken.Id,
+		Name:     apiToken.Name,
+		Scope:    apiToken.Scope,
+		Created:  apiToken.Created,
+		LastUsed: apiToken.LastUsed,
+		Token:    value,
+	})
+}
+
+// HandleRevokeAPIToken implements the API token revocation handler
+func (h *Handlers) HandleRevokeAPIToken(w http.ResponseWriter, r *http.Request, id int64) {
+	if writeAPITokenError(w, h.galleryService.RevokeAPIToken(id)) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeAPITokenError maps API token errors to HTTP responses. It returns false if err is nil.
+func writeAPITokenError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrInvalidAPIToken):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrAPITokenNotFound):
+		http.Error(w, "API token not found", http.StatusNotFound)
+	default:
+		log.Printf("Failed to update API token: %v", err)
+		http.Error(w, "Failed to update API token", http.StatusInternalServerError)
+	}
+	return true
+}
+
+// HandleListShares implements the share link listing handler
+func (h *Handlers) HandleListShares(w http.ResponseWriter, r *http.Request) {
+	shares, err := h.galleryService.ListShares()
+	if writeShareError(w, err) {
+		return
+	}
+
+	response := api.ShareList{Shares: make([]api.Share, 0, len(shares))}
+	for _, share := range shares {
+		response.Shares = append(response.Shares, h.toAPIShare(r, share))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleCreateShare implements the share link creation handler
+func (h *Handlers) HandleCreateShare(w http.ResponseWriter, r *http.Request) {
+	var body api.CreateShareJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	share := service.Share{
+		Kind:        service.ShareKind(valueOrZero(body.Kind)),
+		Photo:       valueOrZero(body.Photo),
+		EventID:     valueOrZero(body.EventId),
+		AlbumID:     valueOrZero(body.AlbumId),
+		Label:       valueOrZero(body.Label),
+		AllowUpload: valueOrZero(body.AllowUpload),
+		Expires:     valueOrZero(body.Expires),
+	}
+	share, err := h.galleryService.CreateShare(share)
+	if writeShareError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusCreated, h.toAPIShare(r, share))
+}
+
+// HandleDeleteShare implements the share link revocation handler
+func (h *Handlers) HandleDeleteShare(w http.ResponseWriter, r *http.Request, id int64) {
+	if writeShareError(w, h.galleryService.DeleteShare(id)) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleOpenShare implements the share link handler
+func (h *Handlers) HandleOpenShare(w http.ResponseWriter, r *http.Request, token string, params api.OpenShareParams) {
+	if h.authService.LoginShare(w, r, token) {
+		target := "/"
+		if params.Event != nil && *params.Event != "" {
+			target += "?" + url.Values{"event": {*params.Event}}.Encode()
+		}
+		http.Redirect(w, r, target, http.StatusSeeOther)
+		return
+	}
+	h.renderLoginError(w, r, http.StatusForbidden, "This link is invalid or has expired")
+}
+
+// HandleGetShareQRCode implements the share link QR code handler
+func (h *Handlers) HandleGetShareQRCode(w http.ResponseWriter, r *http.Request, id int64, params api.GetShareQRCodeParams) {
+	share, err := h.galleryService.GetShare(id)
+	if writeShareError(w, err) {
+		return
+	}
+	link := absoluteURL(r, "/share/"+h.authService.ShareToken(share))
+	if params.Event != nil && *params.Event != "" {
+		link += "?" + url.Values{"event": {*params.Event}}.Encode()
+	}
+
+	format := service.QRCodePNG
+	if params.Format != nil {
+		format = service.QRCodeFormat(*params.Format)
+	}
+	var image bytes.Buffer
+	if err := service.WriteQRCode(&image, link, format, valueOrZero(params.Size)); err != nil {
+		if errors.Is(err, service.ErrInvalidQRCode) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Failed to render QR code for share %d: %v", id, err)
+		http.Error(w, "Failed to render QR code", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := image.WriteTo(w); err != nil {
+		log.Printf("Failed to write QR code: %v", err)
+	}
+}
+
+// writeShareError maps share link errors to HTTP responses. It returns false if err is nil.
+func writeShareError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrInvalidShare):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrShareNotFound):
+		http.Error(w, "Share link not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrPhotoNotFound):
+		http.Error(w, "Photo not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrEventNotFound):
+		http.Error(w, "Event not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrAlbumNotFound):
+		http.Error(w, "Album not found", http.StatusNotFound)
+	default:
+		log.Printf("Failed to update share link: %v", err)
+		http.Error(w, "Failed to update share link", http.StatusInternalServerError)
+	}
+	return true
+}
+
+// HandleSuggestTags implements the tag autocompletion handler
+func (h *Handlers) HandleSuggestTags(w http.ResponseWriter, r *http.Request, params api.SuggestTagsParams) {
+	var prefix string
+	if params.Q != nil {
+		prefix = *params.Q
+	}
+	var limit int
+	if params.Limit != nil {
+		limit = min(*params.Limit, 100)
+	}
+
+	// Tags of photos the session may not see would give them away
+	access, err := h.photoAccess(r)
+	if err != nil {
+		log.Printf("Failed to resolve photo access: %v", err)
+		http.Error(w, "Failed to load tags", http.StatusInternalServerError)
+		return
+	}
+
+	suggestions := h.galleryService.SuggestTags(prefix, limit, access)
+	response := api.TagList{Tags: make([]api.TagCount, 0, len(suggestions))}
+	for _, tag := range suggestions {
+		response.Tags = append(response.Tags, api.TagCount{Name: tag.Name, Count: tag.Count})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleDeletePhoto implements the photo deletion handler
+func (h *Handlers) HandleDeletePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	user, _ := h.authService.CurrentUser(r)
+
+	if !h.canEditPhotos(w, user, []string{filename}, false) {
+		return
+	}
+
+	err := h.galleryService.DeletePhoto(filename)
+	if errors.Is(err, service.ErrPhotoNotFound) {
+		http.Error(w, "Photo not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete photo %s: %v", filename, err)
+		http.Error(w, "Failed to delete photo", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleListTrash implements the trash listing handler
+func (h *Handlers) HandleListTrash(w http.ResponseWriter, r *http.Request) {
+	photos, err := h.galleryService.ListTrash()
+	if err != nil {
+		log.Printf("Failed to list trash: %v", err)
+		http.Error(w, "Failed to list trash", http.StatusInternalServerError)
+		return
+	}
+
+	response := api.TrashList{
+		Photos:        make([]api.PhotoInfo, 0, len(photos)),
+		RetentionDays: int(h.galleryService.TrashRetention() / (24 * time.Hour)),
+	}
+	for _, photo := range photos {
+		response.Photos = append(response.Photos, toAPIPhoto(photo))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleRestorePhoto implements the trash restore handler
+func (h *Handlers) HandleRestorePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	err := h.galleryService.RestorePhoto(filename)
+	switch {
+	case errors.Is(err, service.ErrPhotoNotFound):
+		http.Error(w, "Photo not in trash", http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrPhotoExists):
+		http.Error(w, "A photo with this name already exists", http.StatusConflict)
+		return
+	case errors.Is(err, service.ErrDuplicatePhoto):
+		http.Error(w, "The photo has been uploaded again, delete the new copy to restore it", http.StatusConflict)
+		return
+	case err != nil:
+		log.Printf("Failed to restore photo %s: %v", filename, err)
+		http.Error(w, "Failed to restore photo", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandlePurgePhoto implements the permanent deletion handler
+func (h *Handlers) HandlePurgePhoto(w http.ResponseWriter, r *http.Request, filename string) {
+	err := h.galleryService.PurgePhoto(filename)
+	if errors.Is(err, service.ErrPhotoNotFound) {
+		http.Error(w, "Photo not in trash", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to purge photo %s: %v", filename, err)
+		http.Error(w, "Failed to purge photo", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toAPIPhoto converts photo metadata to its API representation
+func toAPIPhoto(photo service.PhotoInfo) api.PhotoInfo {
+	apiPhoto := api.PhotoInfo{
+		Id:       photo.ID,
+		Path:     photo.Path,
+		Name:     photo.Name,
+		Uploader: photo.Uploader,
+		Date:     photo.Date,
+	}
+	if photo.Event != "" {
+		apiPhoto.Event = &photo.Event
+	}
+	if photo.Caption != "" {
+		apiPhoto.Caption = &photo.Caption
+	}
+	if !photo.PhotoTime.IsZero() {
+		apiPhoto.PhotoTime = &photo.PhotoTime
+	}
+	if len(photo.Contributors) > 0 {
+		apiPhoto.Contributors = &photo.Contributors
+	}
+	if len(photo.Tags) > 0 {
+		apiPhoto.Tags = &photo.Tags
+	}
+	if !photo.DeletedAt.IsZero() {
+		apiPhoto.DeletedAt = &photo.DeletedAt
+	}
+	return apiPhoto
+}
+
+func toAPIAlbum(album service.Album) api.Album {
+	apiAlbum := api.Album{
+		Id:         album.ID,
+		Name:       album.Name,
+		Created:    album.Created,
+		PhotoCount: album.PhotoCount,
+	}
+	if album.CoverPhoto != "" {
+		apiAlbum.CoverPhoto = &album.CoverPhoto
+	}
+	return apiAlbum
+}
+
+func toAPIUser(user service.User) api.User {
+	return api.User{
+		Id:       user.ID,
+		Username: user.Username,
+		Role:     api.Role(user.Role),
+		Created:  user.Created,
+	}
+}
+
+// toAPIShare converts a share link, including its URL on the requested host.
+func (h *Handlers) toAPIShare(r *http.Request, share service.Share) api.Share {
+	apiShare := api.Share{
+		Id:          share.ID,
+		Kind:        api.ShareKind(share.Kind),
+		Label:       share.Label,
+		AllowUpload: share.AllowUpload,
+		Expires:     share.Expires,
+		Created:     share.Created,
+		Url:         absoluteURL(r, "/share/"+h.authService.ShareToken(share)),
+	}
+	if share.Photo != "" {
+		apiShare.Photo = &share.Photo
+	}
+	if share.EventID != 0 {
+		apiShare.EventId = &share.EventID
+	}
+	if share.AlbumID != 0 {
+		apiShare.AlbumId = &share.AlbumID
+	}
+	return apiShare
+}
+
+func toAPIToken(token service.APIToken) api.APIToken {
+	apiToken := api.APIToken{
+		Id:      token.ID,
+		Name:    token.Name,
+		Scope:   api.TokenScope(token.Scope),
+		Created: token.Created,
+	}
+	if !token.LastUsed.IsZero() {
+		apiToken.LastUsed = &token.LastUsed
+	}
+	return apiToken
+}
+
+func toAPISession(session service.Session, current bool) api.Session {
+	apiSession := api.Session{
+		Id:        session.ID,
+		Kind:      api.SessionKind(session.Kind),
+		Name:      session.Name,
+		LastIp:    session.LastIP,
+		UserAgent: session.UserAgent,
+		Created:   session.Created,
+		LastSeen:  session.LastSeen,
+		Expires:   session.Expires,
+		Current:   current,
+	}
+	if session.UserID != 0 {
+		apiSession.UserId = &session.UserID
+	}
+	if session.ShareID != 0 {
+		apiSession.ShareId = &session.ShareID
+	}
+	return apiSession
+}
+
+func toAPIEvent(event service.Event) api.Event {
+	apiEvent := api.Event{
+		Id:          event.ID,
+		Name:        event.Name,
+		Description: event.Description,
+		PhotoCount:  event.PhotoCount,
+		Protected:   event.Protected,
+	}
+	if !event.StartDate.IsZero() {
+		apiEvent.StartDate = &openapi_types.Date{Time: event.StartDate}
+	}
+	if !event.EndDate.IsZero() {
+		apiEvent.EndDate = &openapi_types.Date{Time: event.EndDate}
+	}
+	if event.CoverPhoto != "" {
+		apiEvent.CoverPhoto = &event.CoverPhoto
+	}
+	return apiEvent
+}
+
+// valueOrZero returns the value of an optional request field.
+func valueOrZero[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
+
+// absoluteURL returns the URL of path on the host the request was sent to.
+func absoluteURL(r *http.Request, path string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + path
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write JSON response: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Neokil/Gallery/internal/api"
+	"github.com/Neokil/Gallery/internal/service"
+)
+
+// newTestHandlers creates handlers for a gallery with a photo tagged "party"
+// in a public event and one tagged "secret" in a password protected event.
+// Handlers that render templates cannot be tested with it.
+func newTestHandlers(t *testing.T) (*Handlers, *service.AuthService) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	uploadDir := filepath.Join(tempDir, "uploads")
+	if err := os.MkdirAll(uploadDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"public.png", "secret.png"} {
+		writeTestPNG(t, filepath.Join(uploadDir, name), uint8(i*100)) // #nosec G115 - 0 or 100
+	}
+
+	gallery, err := service.NewGalleryService(uploadDir, filepath.Join(tempDir, "metadata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := gallery.Close(); err != nil {
+			t.Errorf("Failed to close gallery service: %v", err)
+		}
+	})
+
+	photos := []struct{ name, event, tag string }{
+		{"public.png", "Party", "party"},
+		{"secret.png", "Secret", "secret"},
+	}
+	for _, photo := range photos {
+		if _, err := gallery.UpdatePhotoMetadata(photo.name, service.PhotoUpdate{Event: &photo.event, AddTags: []string{photo.tag}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events, err := gallery.ListEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(events, func(e service.Event) bool { return e.Name == "Secret" })
+	password := "secret-password"
+	if _, err := gallery.UpdateEvent(events[i].ID, service.EventUpdate{Password: &password}); err != nil {
+		t.Fatal(err)
+	}
+
+	authService := service.NewAuthService("password", "test-session-key-32-bytes-long!!",
+		service.WithAccounts(gallery), service.WithEventPasswords(gallery))
+	return &Handlers{galleryService: gallery, authService: authService, siteTitle: "Test Gallery"}, authService
+}
+
+// writeTestPNG writes a small PNG filled with a gray level to path.
+func writeTestPNG(t *testing.T, path string, gray uint8) {
+	t.Helper()
+
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	for i := range img.Pix {
+		img.Pix[i] = gray
+	}
+	img.Set(0, 0, color.White)
+	file, err := os.Create(path) // #nosec G304 - test file in a temporary directory
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// sessionRequest returns a request to target with the cookies set by w.
+func sessionRequest(w *httptest.ResponseRecorder, target string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+func TestHandleSuggestTags(t *testing.T) {
+	h, authService := newTestHandlers(t)
+
+	suggest := func(r *http.Request) []string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.HandleSuggestTags(w, r, api.SuggestTagsParams{})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", w.Code)
+		}
+		var response api.TagList
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, tag := range response.Tags {
+			names = append(names, tag.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	// Guests don't learn the tags of protected events
+	login := httptest.NewRecorder()
+	if !authService.Login(login, httptest.NewRequest(http.MethodPost, "/login", http.NoBody), "password") {
+		t.Fatal("Expected guest login to succeed")
+	}
+	if got := suggest(sessionRequest(login, "/tags")); !slices.Equal(got, []string{"party"}) {
+		t.Errorf("Expected only the public tag for guests, got %v", got)
+	}
+
+	// until they unlock the event
+	unlock := httptest.NewRecorder()
+	if !authService.UnlockEvents(unlock, sessionRequest(login, "/unlock"), "secret-password") {
+		t.Fatal("Expected event to unlock")
+	}
+	if got := suggest(sessionRequest(unlock, "/tags")); !slices.Equal(got, []string{"party", "secret"}) {
+		t.Errorf("Expected all tags after unlocking, got %v", got)
+	}
+}
+
+func TestHandleGalleryHiddenAlbum(t *testing.T) {
+	h, authService := newTestHandlers(t)
+	album, err := h.galleryService.CreateAlbum("Family")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Event password logins only see their event, not the album's name
+	login := httptest.NewRecorder()
+	if !authService.LoginEvent(login, httptest.NewRequest(http.MethodPost, "/login", http.NoBody), "secret-password") {
+		t.Fatal("Expected event login to succeed")
+	}
+	w := httptest.NewRecorder()
+	h.HandleGallery(w, sessionRequest(login, "/"), api.GetGalleryParams{Album: &album.ID})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an album outside the event, got %d", w.Code)
+	}
+}
+
+// uploadRequest returns a multipart upload of a new photo to an event with
+// the cookies set by w.
+func uploadRequest(t *testing.T, w *httptest.ResponseRecorder, eventName string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("event_name", eventName); err != nil {
+		t.Fatal(err)
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="photos"; filename="upload.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(part, image.NewGray(image.Rect(0, 0, 12, 8))); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := sessionRequest(w, "/upload")
+	r.Method = http.MethodPost
+	r.Body = io.NopCloser(&body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	r.Header.Set("Accept", "application/json")
+	return r
+}
+
+func TestHandleUploadProtectedEvent(t *testing.T) {
+	h, authService := newTestHandlers(t)
+	if _, err := h.galleryService.CreateUser("carol", "carol-password", service.RoleContributor); err != nil {
+		t.Fatal(err)
+	}
+	login := httptest.NewRecorder()
+	if !authService.LoginUser(login, httptest.NewRequest(http.MethodPost, "/login", http.NoBody), "carol", "carol-password") {
+		t.Fatal("Expected login to succeed")
+	}
+
+	upload := func(session *httptest.ResponseRecorder, eventName string) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.HandleUpload(w, uploadRequest(t, session, eventName))
+		return w.Code
+	}
+
+	// Contributors add photos to public and new events, but not to protected ones
+	if code := upload(login, "Party"); code != http.StatusOK {
+		t.Errorf("Expected upload to a public event, got %d", code)
+	}
+	if code := upload(login, "Secret"); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a protected event, got %d", code)
+	}
+	photos, err := h.galleryService.QueryPhotos(service.PhotoFilter{Event: "Secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 1 {
+		t.Errorf("Expected no photo added to the protected event, got %d photos", len(photos))
+	}
+
+	unlock := httptest.NewRecorder()
+	if !authService.UnlockEvents(unlock, sessionRequest(login, "/unlock"), "secret-password") {
+		t.Fatal("Expected event to unlock")
+	}
+	if code := upload(unlock, "Secret"); code != http.StatusOK {
+		t.Errorf("Expected upload after unlocking, got %d", code)
+	}
+}
@@ -2,27 +2,75 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
 
 	"github.com/Neokil/Gallery/internal/service"
 )
 
-func AuthMiddleware(authService *service.AuthService) func(http.Handler) http.Handler {
+// AuthMiddleware enforces the security requirements of the operations in
+// spec. Operations without their own requirements get the global ones of the
+// spec, so a new endpoint is never public by accident; public operations
+// declare an empty list.
+//
+// Requests other than GET and HEAD to operations with requirements must also
+// carry the CSRF token of their session (see service.AuthService.VerifyCSRF);
+// csrfFailed answers those without. Public operations check it themselves.
+//
+// It must run as handler middleware of the generated API (see
+// api.ChiServerOptions), after chi matched the route of the request.
+// Requests for routes not in spec are rejected.
+func AuthMiddleware(authService *service.AuthService, spec *openapi3.T, csrfFailed http.Handler) func(http.Handler) http.Handler {
+	requirements := make(map[string][]service.SecurityRequirement)
+	for path, item := range spec.Paths.Map() {
+		for method, operation := range item.Operations() {
+			security := spec.Security
+			if operation.Security != nil {
+				security = *operation.Security
+			}
+			operationRequirements := make([]service.SecurityRequirement, 0, len(security))
+			for _, requirement := range security {
+				operationRequirements = append(operationRequirements, service.SecurityRequirement(requirement))
+			}
+			requirements[method+" "+path] = operationRequirements
+		}
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Skip auth for login, logout, and static files
-			if r.URL.Path == "/login" || r.URL.Path == "/logout" ||
-				r.URL.Path == "/static/" || r.URL.Path == "/uploads/" {
-				next.ServeHTTP(w, r)
+			operationRequirements, ok := requirements[r.Method+" "+chi.RouteContext(r.Context()).RoutePattern()]
+			if !ok {
+				http.Error(w, "Forbidden", http.StatusForbidden)
 				return
 			}
 
-			if !authService.IsAuthenticated(r) {
-				http.Redirect(w, r, "/login", http.StatusSeeOther)
-				return
+			err := authService.Authorize(r, operationRequirements)
+			switch {
+			case err == nil:
+				if len(operationRequirements) > 0 && changesState(r) && !authService.VerifyCSRF(r) {
+					csrfFailed.ServeHTTP(w, r)
+					return
+				}
+				next.ServeHTTP(w, r)
+			case errors.Is(err, service.ErrUnauthenticated):
+				// Browsers get the login page, scripts and asset requests a status code
+				if r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html") {
+					http.Redirect(w, r, "/login", http.StatusSeeOther)
+					return
+				}
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			default:
+				http.Error(w, "Forbidden", http.StatusForbidden)
 			}
-
-			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// changesState reports whether the request method may change state.
+func changesState(r *http.Request) bool {
+	return r.Method != http.MethodGet && r.Method != http.MethodHead
+}
@@ -0,0 +1,341 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Neokil/Gallery/internal/api"
+	"github.com/Neokil/Gallery/internal/service"
+)
+
+// identity is a way of logging in, with the cookies or headers it adds to
+// requests and the security scheme and scopes it satisfies.
+type identity struct {
+	name      string
+	cookies   []*http.Cookie
+	header    http.Header
+	query     string
+	scheme    string
+	scopes    []string
+	csrfToken string
+}
+
+// authorize adds the credentials of the identity to r.
+func (id identity) authorize(r *http.Request) {
+	for _, cookie := range id.cookies {
+		r.AddCookie(cookie)
+	}
+	for name, values := range id.header {
+		r.Header[name] = values
+	}
+	if id.query != "" {
+		r.URL.RawQuery = id.query
+	}
+	if id.csrfToken != "" {
+		r.Header.Set("X-CSRF-Token", id.csrfToken)
+	}
+}
+
+// satisfies reports whether the identity meets one of the security
+// requirements of an operation.
+func (id identity) satisfies(security openapi3.SecurityRequirements) bool {
+	if len(security) == 0 {
+		return true
+	}
+	for _, requirement := range security {
+		met := true
+		for scheme, scopes := range requirement {
+			if scheme != id.scheme {
+				met = false
+			}
+			for _, scope := range scopes {
+				if !slices.Contains(id.scopes, scope) {
+					met = false
+				}
+			}
+		}
+		if met {
+			return true
+		}
+	}
+	return false
+}
+
+// operation is an operation of the spec with the security that applies to it.
+type operation struct {
+	method, path string
+	security     openapi3.SecurityRequirements
+}
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// target returns a request path for the operation with example path parameters.
+func (op operation) target() string {
+	return pathParam.ReplaceAllStringFunc(op.path, func(param string) string {
+		switch param {
+		case "{id}":
+			return "1"
+		case "{size}":
+			return "800"
+		case "{token}":
+			return "token"
+		}
+		return "photo.png"
+	})
+}
+
+// newTestRouter returns the API router with the auth middleware in front of
+// handlers that all answer 501, the operations of the spec and the auth
+// service and gallery behind it.
+func newTestRouter(t *testing.T) (http.Handler, []operation, *service.AuthService, *service.GalleryService) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	gallery, err := service.NewGalleryService(filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := gallery.Close(); err != nil {
+			t.Errorf("Failed to close gallery service: %v", err)
+		}
+	})
+	authService := service.NewAuthService("password", "test-session-key-32-bytes-long!!",
+		service.WithAccounts(gallery), service.WithShares(gallery), service.WithAPITokens(gallery), service.WithEventPasswords(gallery))
+
+	spec, err := api.GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var operations []operation
+	for path, item := range spec.Paths.Map() {
+		for method, op := range item.Operations() {
+			security := spec.Security
+			if op.Security != nil {
+				security = *op.Security
+			}
+			operations = append(operations, operation{method, path, security})
+		}
+	}
+
+	csrfFailed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Invalid or missing CSRF token", http.StatusForbidden)
+	})
+	router := api.HandlerWithOptions(api.Unimplemented{}, api.ChiServerOptions{
+		BaseRouter:  chi.NewRouter(),
+		Middlewares: []api.MiddlewareFunc{AuthMiddleware(authService, spec, csrfFailed)},
+	})
+	return router, operations, authService, gallery
+}
+
+// newTestIdentities logs in with every kind of session, share link and API token.
+func newTestIdentities(t *testing.T, authService *service.AuthService, gallery *service.GalleryService) []identity {
+	t.Helper()
+
+	// Every session request carries the CSRF token, checked separately
+	csrf := httptest.NewRecorder()
+	csrfToken := authService.CSRFToken(csrf, httptest.NewRequest(http.MethodGet, "/login", http.NoBody))
+	csrfCookies := csrf.Result().Cookies()
+	session := func(name string, login func(w http.ResponseWriter, r *http.Request) bool, scopes ...string) identity {
+		w := httptest.NewRecorder()
+		if !login(w, httptest.NewRequest(http.MethodPost, "/login", http.NoBody)) {
+			t.Fatalf("Failed to log in as %s", name)
+		}
+		cookies := append(slices.Clone(csrfCookies), w.Result().Cookies()...)
+		return identity{name: name, cookies: cookies, scheme: service.SchemeSession, scopes: scopes, csrfToken: csrfToken}
+	}
+
+	for username, role := range map[string]service.Role{"carol": service.RoleContributor, "root": service.RoleAdmin} {
+		if _, err := gallery.CreateUser(username, username+"-password", role); err != nil {
+			t.Fatal(err)
+		}
+	}
+	identities := []identity{
+		session("guest", func(w http.ResponseWriter, r *http.Request) bool {
+			return authService.Login(w, r, "password")
+		}, "viewer"),
+		session("contributor", func(w http.ResponseWriter, r *http.Request) bool {
+			return authService.LoginUser(w, r, "carol", "carol-password")
+		}, "viewer", "contributor"),
+		session("admin", func(w http.ResponseWriter, r *http.Request) bool {
+			return authService.LoginUser(w, r, "root", "root-password")
+		}, "viewer", "contributor", "admin"),
+	}
+
+	tokens := []struct {
+		scope  service.TokenScope
+		scopes []string
+	}{
+		{service.TokenScopeRead, []string{"viewer"}},
+		{service.TokenScopeUpload, []string{"viewer", "contributor"}},
+		{service.TokenScopeAdmin, []string{"viewer", "contributor", "admin"}},
+	}
+	for _, token := range tokens {
+		_, value, err := gallery.CreateAPIToken(string(token.scope)+" script", token.scope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		identities = append(identities, identity{
+			name:   string(token.scope) + " token",
+			header: http.Header{"Authorization": {"Bearer " + value}},
+			scheme: service.SchemeBearer,
+			scopes: token.scopes,
+		})
+	}
+
+	album, err := gallery.CreateAlbum("Shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := []struct {
+		name   string
+		share  service.Share
+		scopes []string
+	}{
+		{"album link", service.Share{AlbumID: album.ID}, []string{"browse"}},
+		{"upload link", service.Share{Kind: service.ShareKindUpload}, []string{"upload"}},
+	}
+	for _, tt := range shares {
+		share, err := gallery.CreateShare(tt.share)
+		if err != nil {
+			t.Fatal(err)
+		}
+		identities = append(identities, identity{
+			name:      tt.name,
+			cookies:   csrfCookies,
+			query:     "share=" + authService.ShareToken(share),
+			scheme:    service.SchemeShare,
+			scopes:    tt.scopes,
+			csrfToken: csrfToken,
+		})
+	}
+	return identities
+}
+
+func TestAuthMiddlewareUnauthenticated(t *testing.T) {
+	router, operations, _, _ := newTestRouter(t)
+
+	for _, op := range operations {
+		public := len(op.security) == 0
+		for _, accept := range []string{"application/json", "text/html"} {
+			r := httptest.NewRequest(op.method, op.target(), http.NoBody)
+			r.Header.Set("Accept", accept)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			want := http.StatusUnauthorized
+			switch {
+			case public:
+				want = http.StatusNotImplemented // Reaches the handler
+			case op.method == http.MethodGet && accept == "text/html":
+				want = http.StatusSeeOther
+			}
+			if w.Code != want {
+				t.Errorf("%s %s (%s): expected %d, got %d", op.method, op.path, accept, want, w.Code)
+			}
+			if want == http.StatusSeeOther && w.Header().Get("Location") != "/login" {
+				t.Errorf("%s %s: expected redirect to /login, got %q", op.method, op.path, w.Header().Get("Location"))
+			}
+		}
+	}
+}
+
+func TestAuthMiddlewareScopes(t *testing.T) {
+	router, operations, authService, gallery := newTestRouter(t)
+	identities := newTestIdentities(t, authService, gallery)
+
+	request := func(id identity, method, target string) int {
+		r := httptest.NewRequest(method, target, http.NoBody)
+		id.authorize(r)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Every operation admits exactly the roles and links its security names
+	for _, op := range operations {
+		for _, id := range identities {
+			want := http.StatusForbidden
+			if id.satisfies(op.security) {
+				want = http.StatusNotImplemented
+			}
+			if got := request(id, op.method, op.target()); got != want {
+				t.Errorf("%s %s as %s: expected %d, got %d", op.method, op.path, id.name, want, got)
+			}
+		}
+	}
+
+	// and the spec says what it should
+	byName := make(map[string]identity, len(identities))
+	for _, id := range identities {
+		byName[id.name] = id
+	}
+	tests := []struct {
+		identity, method, target string
+		allowed                  bool
+	}{
+		{"guest", http.MethodGet, "/", true},
+		{"guest", http.MethodGet, "/uploads/photo.png", true},
+		{"guest", http.MethodPost, "/upload", false},
+		{"guest", http.MethodDelete, "/photos/photo.png", false},
+		{"contributor", http.MethodPost, "/upload", true},
+		{"contributor", http.MethodGet, "/users", false},
+		{"admin", http.MethodGet, "/users", true},
+		{"admin", http.MethodDelete, "/users/1", true},
+		{"read token", http.MethodGet, "/tags", true},
+		{"read token", http.MethodPost, "/upload", false},
+		{"upload token", http.MethodPost, "/upload", true},
+		{"upload token", http.MethodPost, "/tokens", false},
+		{"admin token", http.MethodPost, "/tokens", true},
+		{"album link", http.MethodGet, "/uploads/photo.png", true},
+		{"album link", http.MethodPost, "/upload", false},
+		{"album link", http.MethodGet, "/users", false},
+		{"upload link", http.MethodPost, "/upload", true},
+		{"upload link", http.MethodGet, "/uploads/photo.png", false},
+	}
+	for _, tt := range tests {
+		want := http.StatusForbidden
+		if tt.allowed {
+			want = http.StatusNotImplemented
+		}
+		if got := request(byName[tt.identity], tt.method, tt.target); got != want {
+			t.Errorf("%s %s as %s: expected %d, got %d", tt.method, tt.target, tt.identity, want, got)
+		}
+	}
+}
+
+func TestAuthMiddlewareCSRF(t *testing.T) {
+	router, _, authService, gallery := newTestRouter(t)
+	identities := newTestIdentities(t, authService, gallery)
+
+	tests := []struct {
+		identity, method, target string
+		want                     int
+	}{
+		{"contributor", http.MethodPost, "/upload", http.StatusForbidden},
+		{"admin", http.MethodDelete, "/photos/photo.png", http.StatusForbidden},
+		{"upload link", http.MethodPost, "/upload", http.StatusForbidden},
+		{"admin", http.MethodGet, "/users", http.StatusNotImplemented},
+		{"upload token", http.MethodPost, "/upload", http.StatusNotImplemented}, // Browsers never send API tokens
+	}
+	for _, tt := range tests {
+		i := slices.IndexFunc(identities, func(id identity) bool { return id.name == tt.identity })
+		id := identities[i]
+		id.csrfToken = ""
+
+		r := httptest.NewRequest(tt.method, tt.target, http.NoBody)
+		id.authorize(r)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s %s as %s without CSRF token: expected %d, got %d", tt.method, tt.target, tt.identity, tt.want, w.Code)
+		}
+	}
+}
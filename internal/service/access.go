@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"slices"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccessScope is the part of the gallery a session may see.
+type AccessScope struct {
+	// All grants access to every photo, e.g. for admins
+	All bool
+	// Public grants access to the photos of events without a password
+	Public bool
+	// Events are the IDs of the events the session unlocked with their password
+	Events []int64
+	// Albums are the IDs of the albums shared with the session
+	Albums []int64
+	// Photos are the names of single photos shared with the session
+	Photos []string
+	// OwnerID grants access to the photos uploaded by this user
+	OwnerID int64
+}
+
+// PhotoAccess decides which photos a session may see. It is derived from an
+// AccessScope by GalleryService.PhotoAccess; a nil PhotoAccess allows everything.
+type PhotoAccess struct {
+	public    bool
+	unlocked  map[string]bool // names of the events unlocked by the session
+	protected map[string]bool // names of all events with a password
+	photos    map[string]bool // names of the photos shared with the session
+	albums    map[int64]bool  // IDs of the albums shared with the session
+	ownerID   int64
+}
+
+// Allows reports whether the photo may be seen.
+func (a *PhotoAccess) Allows(photo PhotoInfo) bool {
+	if a == nil || a.ownerID != 0 && photo.OwnerID == a.ownerID || a.photos[photo.Name] {
+		return true
+	}
+	return a.AllowsEvent(photo.Event)
+}
+
+// AllowsEvent reports whether the photos of an event may be seen. The empty
+// event name stands for photos without an event.
+func (a *PhotoAccess) AllowsEvent(name string) bool {
+	if a == nil || a.unlocked[name] {
+		return true
+	}
+	return a.public && !a.protected[name]
+}
+
+// AllowsUpload reports whether photos may be uploaded to an event. Events
+// with a password only take uploads from sessions that unlocked them.
+func (a *PhotoAccess) AllowsUpload(event string) bool {
+	return a == nil || a.unlocked[event] || !a.protected[event]
+}
+
+// AllowsAlbum reports whether an album may be listed. Sessions that only
+// see unlocked events or shared photos do not see other albums.
+func (a *PhotoAccess) AllowsAlbum(id int64) bool {
+	return a == nil || a.public || a.albums[id]
+}
+
+// PhotoAccess resolves the events of scope. The result is a snapshot; event
+// passwords set or removed later are not reflected, create it per request.
+func (s *GalleryService) PhotoAccess(scope AccessScope) (*PhotoAccess, error) {
+	if scope.All {
+		return nil, nil
+	}
+
+	events, err := s.store.ListEvents()
+	if err != nil {
+		return nil, err
+	}
+	access := &PhotoAccess{
+		public:    scope.Public,
+		unlocked:  make(map[string]bool),
+		protected: make(map[string]bool),
+		photos:    make(map[string]bool),
+		albums:    make(map[int64]bool),
+		ownerID:   scope.OwnerID,
+	}
+	for _, event := range events {
+		if event.Protected {
+			access.protected[event.Name] = true
+		}
+		if slices.Contains(scope.Events, event.ID) {
+			access.unlocked[event.Name] = true
+		}
+	}
+	for _, name := range scope.Photos {
+		access.photos[name] = true
+	}
+	for _, id := range scope.Albums {
+		photos, err := s.store.AlbumPhotos(id)
+		if errors.Is(err, ErrAlbumNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		access.albums[id] = true
+		for _, photo := range photos {
+			access.photos[photo.Name] = true
+		}
+	}
+	return access, nil
+}
+
+// EventPasswordHashes returns the password hashes of the protected events by
+// event ID, which change with every new password.
+func (s *GalleryService) EventPasswordHashes() (map[int64]string, error) {
+	return s.store.EventPasswordHashes()
+}
+
+// UnlockEvents returns the IDs of the events protected by password, or
+// ErrInvalidCredentials if there are none.
+func (s *GalleryService) UnlockEvents(password string) ([]int64, error) {
+	if password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	hashes, err := s.store.EventPasswordHashes()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for id, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
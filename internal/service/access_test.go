@@ -0,0 +1,281 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// newTestServiceWithSecretEvent returns a service with a photo in the public
+// event "Party", a photo in the event "Secret" protected by "secret-password"
+// and a photo of bob in "Secret".
+func newTestServiceWithSecretEvent(t *testing.T) (*GalleryService, Event, User) {
+	t.Helper()
+
+	service := newTestServiceWithPhoto(t, "public.png")
+	bob, err := service.CreateUser("bob", "bob-password", RoleContributor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uploads := []struct {
+		filename string
+		uploader User
+	}{
+		{"secret.png", User{Username: "Alice"}},
+		{"bob.png", bob},
+	}
+	for i, upload := range uploads {
+		data := encodeTestImage(t, createPatternImage(30+i, 30, i%2 == 0), "png")
+		if _, err := service.SavePhoto(newTestFileHeader(t, upload.filename, "image/png", data), upload.uploader, "Secret"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := service.ListEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(events, func(e Event) bool { return e.Name == "Secret" })
+	password := "secret-password"
+	secret, err := service.UpdateEvent(events[i].ID, EventUpdate{Password: &password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, secret, bob
+}
+
+func photoNames(photos []PhotoInfo) []string {
+	names := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		names = append(names, photo.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestEventPasswords(t *testing.T) {
+	service, secret, bob := newTestServiceWithSecretEvent(t)
+	if !secret.Protected {
+		t.Fatalf("Expected event to be protected, got %+v", secret)
+	}
+
+	short := "short"
+	if _, err := service.UpdateEvent(secret.ID, EventUpdate{Password: &short}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("Expected ErrInvalidEvent for a short password, got %v", err)
+	}
+	if _, err := service.UnlockEvents("wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for a wrong password, got %v", err)
+	}
+	ids, err := service.UnlockEvents("secret-password")
+	if err != nil || !slices.Equal(ids, []int64{secret.ID}) {
+		t.Errorf("Expected to unlock event %d, got %v (%v)", secret.ID, ids, err)
+	}
+
+	tests := []struct {
+		name  string
+		scope AccessScope
+		want  []string
+	}{
+		{"admin", AccessScope{All: true}, []string{"bob.png", "public.png", "secret.png"}},
+		{"guest", AccessScope{Public: true}, []string{"public.png"}},
+		{"owner", AccessScope{Public: true, OwnerID: bob.ID}, []string{"bob.png", "public.png"}},
+		{"unlocked", AccessScope{Public: true, Events: ids}, []string{"bob.png", "public.png", "secret.png"}},
+		{"event only", AccessScope{Events: ids}, []string{"bob.png", "secret.png"}},
+		{"nothing", AccessScope{}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access, err := service.PhotoAccess(tt.scope)
+			if err != nil {
+				t.Fatal(err)
+			}
+			photos, err := service.QueryPhotos(PhotoFilter{Access: access})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := photoNames(photos); !slices.Equal(got, tt.want) {
+				t.Errorf("Index returned %v, want %v", got, tt.want)
+			}
+
+			// The store must agree with the in-memory index
+			stored, err := service.store.ListPhotos(PhotoFilter{Access: access})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := photoNames(stored); !slices.Equal(got, tt.want) {
+				t.Errorf("Store returned %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	empty := ""
+	if event, err := service.UpdateEvent(secret.ID, EventUpdate{Password: &empty}); err != nil || event.Protected {
+		t.Errorf("Expected password to be removed, got %+v (%v)", event, err)
+	}
+	if _, err := service.UnlockEvents("secret-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected removed password not to unlock anything, got %v", err)
+	}
+}
+
+func TestEventLogin(t *testing.T) {
+	gallery, secret, _ := newTestServiceWithSecretEvent(t)
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!", WithAccounts(gallery), WithEventPasswords(gallery))
+
+	if service.LoginEvent(httptest.NewRecorder(), httptest.NewRequest("POST", "/login", http.NoBody), "wrong-password") {
+		t.Error("Expected login to fail with a wrong event password")
+	}
+
+	w := httptest.NewRecorder()
+	if !service.LoginEvent(w, httptest.NewRequest("POST", "/login", http.NoBody), "secret-password") {
+		t.Fatal("Expected login to succeed with the event password")
+	}
+	r := httptest.NewRequest("GET", "/", http.NoBody)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	scope, ok := service.Access(r)
+	if !ok || scope.Public || !slices.Equal(scope.Events, []int64{secret.ID}) {
+		t.Errorf("Expected session scoped to event %d, got %+v (%v)", secret.ID, scope, ok)
+	}
+
+	// A guest session unlocks the event in addition to the public ones
+	w = httptest.NewRecorder()
+	if !service.Login(w, httptest.NewRequest("POST", "/login", http.NoBody), "password") {
+		t.Fatal("Login should have succeeded")
+	}
+	r = httptest.NewRequest("GET", "/", http.NoBody)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	if service.UnlockEvents(httptest.NewRecorder(), r, "wrong-password") {
+		t.Error("Expected unlock to fail with a wrong event password")
+	}
+	w = httptest.NewRecorder()
+	if !service.UnlockEvents(w, r, "secret-password") {
+		t.Fatal("Expected unlock to succeed with the event password")
+	}
+	r = httptest.NewRequest("GET", "/", http.NoBody)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	scope, ok = service.Access(r)
+	if !ok || !scope.Public || !slices.Equal(scope.Events, []int64{secret.ID}) {
+		t.Errorf("Expected public session with event %d unlocked, got %+v (%v)", secret.ID, scope, ok)
+	}
+}
+
+func TestEventPasswordChange(t *testing.T) {
+	gallery, secret, _ := newTestServiceWithSecretEvent(t)
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!", WithAccounts(gallery), WithEventPasswords(gallery))
+
+	eventLogin := httptest.NewRecorder()
+	if !service.LoginEvent(eventLogin, httptest.NewRequest("POST", "/login", http.NoBody), "secret-password") {
+		t.Fatal("Expected login to succeed with the event password")
+	}
+	guestLogin := httptest.NewRecorder()
+	if !service.Login(guestLogin, httptest.NewRequest("POST", "/login", http.NoBody), "password") {
+		t.Fatal("Login should have succeeded")
+	}
+	unlocked := httptest.NewRecorder()
+	if !service.UnlockEvents(unlocked, withCookies(guestLogin), "secret-password") {
+		t.Fatal("Expected unlock to succeed with the event password")
+	}
+
+	// A new password ends the event login and the unlock of the guest
+	password := "new-secret-password"
+	if _, err := gallery.UpdateEvent(secret.ID, EventUpdate{Password: &password}); err != nil {
+		t.Fatal(err)
+	}
+	if scope, ok := service.Access(withCookies(eventLogin)); ok {
+		t.Errorf("Expected event login to end, got %+v", scope)
+	}
+	if scope, ok := service.Access(withCookies(unlocked)); !ok || !scope.Public || len(scope.Events) != 0 {
+		t.Errorf("Expected public guest session without unlocked events, got %+v (%v)", scope, ok)
+	}
+	if service.UnlockEvents(httptest.NewRecorder(), withCookies(unlocked), "secret-password") {
+		t.Error("Expected the old password not to unlock the event")
+	}
+
+	// The new password unlocks it again, until the password is removed
+	unlocked = httptest.NewRecorder()
+	if !service.UnlockEvents(unlocked, withCookies(guestLogin), password) {
+		t.Fatal("Expected unlock to succeed with the new password")
+	}
+	if scope, ok := service.Access(withCookies(unlocked)); !ok || !slices.Equal(scope.Events, []int64{secret.ID}) {
+		t.Errorf("Expected event %d unlocked, got %+v (%v)", secret.ID, scope, ok)
+	}
+	empty := ""
+	if _, err := gallery.UpdateEvent(secret.ID, EventUpdate{Password: &empty}); err != nil {
+		t.Fatal(err)
+	}
+	if scope, ok := service.Access(withCookies(unlocked)); !ok || len(scope.Events) != 0 {
+		t.Errorf("Expected no unlocked events after removing the password, got %+v (%v)", scope, ok)
+	}
+}
+
+func TestMergeProtectedEvent(t *testing.T) {
+	service, secret, _ := newTestServiceWithSecretEvent(t)
+	events, err := service.ListEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	party := events[slices.IndexFunc(events, func(e Event) bool { return e.Name == "Party" })]
+
+	// Merging would move the photos out of their protected event
+	if _, err := service.MergeEvents(party.ID, []int64{secret.ID}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("Expected ErrInvalidEvent for a protected source, got %v", err)
+	}
+	access, err := service.PhotoAccess(AccessScope{Public: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	photos, err := service.QueryPhotos(PhotoFilter{Access: access})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := photoNames(photos); !slices.Equal(got, []string{"public.png"}) {
+		t.Errorf("Expected the protected photos to stay hidden, got %v", got)
+	}
+
+	// Unprotected events can be merged into the protected one
+	merged, err := service.MergeEvents(secret.ID, []int64{party.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merged.Protected || merged.PhotoCount != 3 {
+		t.Errorf("Expected protected event with 3 photos, got %+v", merged)
+	}
+}
+
+func TestSavePhotoDuplicateOfProtectedEvent(t *testing.T) {
+	service, _, bob := newTestServiceWithSecretEvent(t)
+
+	// Bob uploads a copy of Alice's protected photo to a public event
+	data := encodeTestImage(t, createPatternImage(30, 30, true), "png")
+	result, err := service.SavePhoto(newTestFileHeader(t, "copy.png", "image/png", data), bob, "Party")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Deduplicated || result.Photo.Name != "copy.png" || result.Photo.Event != "Party" {
+		t.Errorf("Expected a new photo in the public event, got %+v", result)
+	}
+
+	secret, err := service.store.GetPhoto("secret.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret.Contributors) != 0 {
+		t.Errorf("Expected no contributors on the protected photo, got %v", secret.Contributors)
+	}
+
+	// Within the protected event, copies are still stored once
+	result, err = service.SavePhoto(newTestFileHeader(t, "again.png", "image/png", data), bob, "Secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Deduplicated || result.Photo.Name != "secret.png" {
+		t.Errorf("Expected duplicate of secret.png in the same event, got %+v", result)
+	}
+}
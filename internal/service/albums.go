@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAlbum is returned for album changes with invalid values.
+var ErrInvalidAlbum = errors.New("invalid album")
+
+// Album is a curated, ordered selection of photos across events.
+type Album struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Created    time.Time `json:"created"`
+	PhotoCount int       `json:"photo_count"`
+	CoverPhoto string    `json:"cover_photo,omitempty"` // first photo of the album
+}
+
+// CreateAlbum creates an empty album.
+func (s *GalleryService) CreateAlbum(name string) (Album, error) {
+	name, err := normalizeAlbumName(name)
+	if err != nil {
+		return Album{}, err
+	}
+	return s.store.CreateAlbum(name)
+}
+
+// ListAlbums returns all albums sorted by name.
+func (s *GalleryService) ListAlbums() ([]Album, error) {
+	return s.store.ListAlbums()
+}
+
+// GetAlbum returns an album or ErrAlbumNotFound.
+func (s *GalleryService) GetAlbum(id int64) (Album, error) {
+	return s.store.GetAlbum(id)
+}
+
+// RenameAlbum changes the name of an album.
+func (s *GalleryService) RenameAlbum(id int64, name string) (Album, error) {
+	name, err := normalizeAlbumName(name)
+	if err != nil {
+		return Album{}, err
+	}
+	if err := s.store.RenameAlbum(id, name); err != nil {
+		return Album{}, err
+	}
+	return s.store.GetAlbum(id)
+}
+
+// DeleteAlbum deletes an album; its photos stay in the gallery.
+func (s *GalleryService) DeleteAlbum(id int64) error {
+	return s.store.DeleteAlbum(id)
+}
+
+// AlbumPhotos returns the photos of an album matching filter, in album order.
+func (s *GalleryService) AlbumPhotos(id int64, filter PhotoFilter) ([]PhotoInfo, error) {
+	photos, err := s.store.AlbumPhotos(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter = filter.normalized()
+	filtered := photos[:0]
+	for _, photo := range photos {
+		if filter.Matches(photo) {
+			filtered = append(filtered, photo)
+		}
+	}
+	return filtered, nil
+}
+
+// AddToAlbum appends photos to the end of an album. Photos already in the
+// album keep their position.
+func (s *GalleryService) AddToAlbum(id int64, filenames []string) (Album, error) {
+	if len(filenames) == 0 {
+		return Album{}, fmt.Errorf("%w: no photos selected", ErrInvalidAlbum)
+	}
+	if err := s.store.AddAlbumPhotos(id, filenames); err != nil {
+		return Album{}, err
+	}
+	return s.store.GetAlbum(id)
+}
+
+// RemoveFromAlbum removes a photo from an album; the photo stays in the gallery.
+func (s *GalleryService) RemoveFromAlbum(id int64, filename string) error {
+	return s.store.RemoveAlbumPhoto(id, filename)
+}
+
+// ReorderAlbum sets the order of an album's photos. filenames must list every
+// photo of the album exactly once.
+func (s *GalleryService) ReorderAlbum(id int64, filenames []string) error {
+	return s.store.ReorderAlbum(id, filenames)
+}
+
+func normalizeAlbumName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name must not be empty", ErrInvalidAlbum)
+	}
+	return name, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestAlbums(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	uploads := []struct{ filename, uploader, event string }{
+		{"a.png", "Alice", "Wedding"},
+		{"b.png", "Bob", "Party"},
+		{"c.png", "Alice", "Party"},
+	}
+	for i, upload := range uploads {
+		data := encodeTestImage(t, createPatternImage(20+i, 20, i%2 == 0), "png")
+		if _, err := service.SavePhoto(newTestFileHeader(t, upload.filename, "image/png", data), User{Username: upload.uploader}, upload.event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := service.CreateAlbum("  "); !errors.Is(err, ErrInvalidAlbum) {
+		t.Errorf("Expected ErrInvalidAlbum for an empty name, got %v", err)
+	}
+	album, err := service.CreateAlbum("Best of")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if album, err = service.AddToAlbum(album.ID, []string{"b.png", "a.png"}); err != nil {
+		t.Fatal(err)
+	}
+	if album.PhotoCount != 2 || album.CoverPhoto != "b.png" {
+		t.Errorf("Expected 2 photos with cover b.png, got %+v", album)
+	}
+
+	if err := service.ReorderAlbum(album.ID, []string{"a.png", "a.png"}); !errors.Is(err, ErrInvalidAlbum) {
+		t.Errorf("Expected ErrInvalidAlbum for a duplicate entry, got %v", err)
+	}
+	if err := service.ReorderAlbum(album.ID, []string{"a.png", "b.png"}); err != nil {
+		t.Fatal(err)
+	}
+
+	photos, err := service.AlbumPhotos(album.ID, PhotoFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 2 || photos[0].Name != "a.png" || photos[1].Name != "b.png" {
+		t.Errorf("Expected album order a, b, got %+v", photos)
+	}
+	photos, err = service.AlbumPhotos(album.ID, PhotoFilter{Event: "Party"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 1 || photos[0].Name != "b.png" {
+		t.Errorf("Expected filters to apply within the album, got %+v", photos)
+	}
+
+	if err := service.DeletePhoto("a.png"); err != nil {
+		t.Fatal(err)
+	}
+	if album, err = service.GetAlbum(album.ID); err != nil {
+		t.Fatal(err)
+	}
+	if album.PhotoCount != 1 {
+		t.Errorf("Expected trashed photo to be hidden from the album, got %d photos", album.PhotoCount)
+	}
+	if _, err := service.AlbumPhotos(42, PhotoFilter{}); !errors.Is(err, ErrAlbumNotFound) {
+		t.Errorf("Expected ErrAlbumNotFound, got %v", err)
+	}
+}
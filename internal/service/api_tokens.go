@@ -0,0 +1,119 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	apiTokenPrefix        = "gallery_"  // Prefix of API tokens, so they are recognizable in scripts and secret scanners
+	apiTokenLength        = 32          // Length of the random part of API tokens in bytes
+	maxAPITokenNameLength = 100         // Maximum API token name length in bytes
+	apiTokenTouchInterval = time.Minute // How often the last use of a token is updated
+)
+
+// ErrInvalidAPIToken is returned for API tokens with invalid values.
+var ErrInvalidAPIToken = errors.New("invalid API token")
+
+// TokenScope is what an API token may do.
+type TokenScope string
+
+const (
+	// TokenScopeRead browses and downloads photos, like a viewer.
+	TokenScopeRead TokenScope = "read"
+	// TokenScopeUpload also uploads photos, like a contributor.
+	TokenScopeUpload TokenScope = "upload"
+	// TokenScopeAdmin may do everything, like an admin.
+	TokenScopeAdmin TokenScope = "admin"
+)
+
+// Role returns the role requests with a token of the scope have.
+func (s TokenScope) Role() Role {
+	switch s {
+	case TokenScopeUpload:
+		return RoleContributor
+	case TokenScopeAdmin:
+		return RoleAdmin
+	}
+	return RoleViewer
+}
+
+// Valid reports whether s is a known scope.
+func (s TokenScope) Valid() bool {
+	return s == TokenScopeRead || s == TokenScopeUpload || s == TokenScopeAdmin
+}
+
+// APIToken is a bearer token for scripts. The token itself is only returned
+// on creation; the store keeps its hash.
+type APIToken struct {
+	ID       int64      `json:"id"`
+	Name     string     `json:"name"` // also recorded as uploader of the token's photos
+	Scope    TokenScope `json:"scope"`
+	Created  time.Time  `json:"created"`
+	LastUsed time.Time  `json:"last_used"` // zero if never used
+}
+
+// User returns the identity of requests with the token.
+func (t APIToken) User() User {
+	return User{Username: t.Name, Role: t.Scope.Role()}
+}
+
+// CreateAPIToken creates an API token and returns it with its secret value.
+func (s *GalleryService) CreateAPIToken(name string, scope TokenScope) (APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return APIToken{}, "", fmt.Errorf("%w: name must not be empty", ErrInvalidAPIToken)
+	}
+	if len(name) > maxAPITokenNameLength {
+		return APIToken{}, "", fmt.Errorf("%w: name is longer than %d bytes", ErrInvalidAPIToken, maxAPITokenNameLength)
+	}
+	if !scope.Valid() {
+		return APIToken{}, "", fmt.Errorf("%w: unknown scope %q", ErrInvalidAPIToken, scope)
+	}
+
+	secret := make([]byte, apiTokenLength)
+	if _, err := rand.Read(secret); err != nil {
+		return APIToken{}, "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	value := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token, err := s.store.CreateAPIToken(hashToken(value), APIToken{Name: name, Scope: scope})
+	if err != nil {
+		return APIToken{}, "", err
+	}
+	return token, value, nil
+}
+
+// ListAPITokens returns all API tokens, newest first.
+func (s *GalleryService) ListAPITokens() ([]APIToken, error) {
+	return s.store.ListAPITokens()
+}
+
+// RevokeAPIToken deletes an API token; requests with it fail immediately.
+func (s *GalleryService) RevokeAPIToken(id int64) error {
+	return s.store.DeleteAPIToken(id)
+}
+
+// AuthenticateAPIToken returns the API token with the given value or
+// ErrAPITokenNotFound, and records that it was used.
+func (s *GalleryService) AuthenticateAPIToken(value string) (APIToken, error) {
+	if !strings.HasPrefix(value, apiTokenPrefix) {
+		return APIToken{}, ErrAPITokenNotFound
+	}
+	hash := hashToken(value)
+	token, err := s.store.APITokenByHash(hash)
+	if err != nil {
+		return APIToken{}, err
+	}
+	if time.Since(token.LastUsed) >= apiTokenTouchInterval {
+		if err := s.store.TouchAPIToken(hash); err != nil {
+			log.Printf("Failed to update API token %d: %v", token.ID, err)
+		}
+	}
+	return token, nil
+}
@@ -2,9 +2,19 @@
 package service
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/binary"
+	"log"
 	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/sessions"
 )
@@ -13,21 +23,123 @@ const (
 	secretKeyLength = 32 // Length of secret key in bytes
 )
 
+// guestUser is the identity of a session logged in with the shared gallery password.
+var guestUser = User{Username: "Guest", Role: RoleViewer}
+
+// Accounts looks up the user accounts that can log in besides the shared
+// gallery password. It is implemented by GalleryService.
+type Accounts interface {
+	Authenticate(username, password string) (User, error)
+	GetUser(id int64) (User, error)
+}
+
+// EventPasswords unlocks password-protected events. It is implemented by GalleryService.
+type EventPasswords interface {
+	UnlockEvents(password string) ([]int64, error)
+	EventPasswordHashes() (map[int64]string, error)
+}
+
+// Shares looks up the share links behind share tokens. It is implemented by GalleryService.
+type Shares interface {
+	GetShare(id int64) (Share, error)
+}
+
+// APITokens looks up the API tokens of bearer requests. It is implemented by GalleryService.
+type APITokens interface {
+	AuthenticateAPIToken(value string) (APIToken, error)
+}
+
+// Sessions stores sessions server-side, so they can be listed and revoked.
+// It is implemented by GalleryService.
+type Sessions interface {
+	CreateSession(token string, session Session) (Session, error)
+	LookupSession(token string) (Session, error)
+	UpdateSession(token string, data []byte, expires time.Time) error
+	TouchSession(token, lastIP, userAgent string) error
+	EndSession(token string) error
+}
+
+// AuthOption configures an AuthService.
+type AuthOption func(*AuthService)
+
+// WithAccounts enables logging in with a username and password.
+func WithAccounts(accounts Accounts) AuthOption {
+	return func(a *AuthService) {
+		a.accounts = accounts
+	}
+}
+
+// WithEventPasswords enables unlocking events with their password.
+func WithEventPasswords(events EventPasswords) AuthOption {
+	return func(a *AuthService) {
+		a.events = events
+	}
+}
+
+// WithShares enables logging in with share link tokens.
+func WithShares(shares Shares) AuthOption {
+	return func(a *AuthService) {
+		a.shares = shares
+	}
+}
+
+// WithAPITokens enables authenticating requests with an
+// "Authorization: Bearer <token>" header instead of a session.
+func WithAPITokens(tokens APITokens) AuthOption {
+	return func(a *AuthService) {
+		a.tokens = tokens
+	}
+}
+
+// WithSessions stores sessions server-side instead of in the session cookie.
+func WithSessions(sessions Sessions) AuthOption {
+	return func(a *AuthService) {
+		a.sessions = sessions
+	}
+}
+
+// WithPasswordHash sets a bcrypt or argon2id hash of the shared guest
+// password, so the password itself need not be configured. It takes
+// precedence over a plaintext password. See HashPassword and ValidatePasswordHash.
+func WithPasswordHash(hash string) AuthOption {
+	return func(a *AuthService) {
+		a.passwordHash = hash
+	}
+}
+
+// WithTrustedProxies sets the networks of the reverse proxies whose
+// X-Forwarded-For headers identify clients, instead of DefaultTrustedProxies.
+func WithTrustedProxies(proxies []netip.Prefix) AuthOption {
+	return func(a *AuthService) {
+		a.trustedProxies = proxies
+	}
+}
+
 type AuthService struct {
-	store    *sessions.CookieStore
+	store          sessions.Store
+	sessions       Sessions
+	tokens         APITokens
+	accounts       Accounts
+	events         EventPasswords
+	shares         Shares
+	shareKey       []byte // signs share tokens
+	csrfKey        []byte // signs CSRF tokens
+	limiter        *LoginLimiter
+	trustedProxies []netip.Prefix
+	passwordHash   string // hash of the shared password, see WithPasswordHash
+	// Password is the shared password for guest (viewer) access, empty to disable it
 	Password string
 }
 
-func NewAuthService(password, sessionKey string) *AuthService {
+func NewAuthService(password, sessionKey string, opts ...AuthOption) *AuthService {
 	// Use provided session key or generate one if empty
 	key := sessionKey
 	if key == "" {
 		key = generateSecretKey()
 	}
 
-	store := sessions.NewCookieStore([]byte(key))
 	// Configure session options for better compatibility
-	store.Options = &sessions.Options{
+	options := &sessions.Options{
 		Path:     "/",
 		MaxAge:   86400 * 7, // 7 days
 		HttpOnly: true,
@@ -36,30 +148,431 @@ func NewAuthService(password, sessionKey string) *AuthService {
 		Domain:   "", // Empty domain works better with IP addresses
 	}
 
-	return &AuthService{
-		store:    store,
-		Password: password,
+	// Share and CSRF tokens are signed with keys derived from the session
+	// key, so they stay valid across restarts as long as sessions do
+	a := &AuthService{
+		shareKey:       deriveKey(key, "share tokens"),
+		csrfKey:        deriveKey(key, "csrf tokens"),
+		limiter:        NewLoginLimiter(),
+		trustedProxies: DefaultTrustedProxies,
+		Password:       password,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if a.sessions != nil {
+		a.store = &serverSessionStore{
+			sessions: a.sessions,
+			options:  options,
+			clientIP: func(r *http.Request) string { return clientIP(r, a.trustedProxies) },
+		}
+	} else {
+		store := sessions.NewCookieStore([]byte(key))
+		store.Options = options
+		a.store = store
 	}
+	return a
 }
 
+// IsAuthenticated reports whether the request may browse the gallery. Upload-only
+// share links are logged in (see CurrentUser) but may not browse.
 func (a *AuthService) IsAuthenticated(r *http.Request) bool {
+	_, share, ok := a.identify(r)
+	return ok && (share == nil || !share.UploadOnly())
+}
+
+// CurrentUser returns the user the request's session is logged in as. The
+// account is looked up on every request so role changes and deleted users
+// take effect immediately.
+func (a *AuthService) CurrentUser(r *http.Request) (User, bool) {
+	user, _, ok := a.identify(r)
+	return user, ok
+}
+
+// CurrentShare returns the share link the request is authenticated with,
+// either through a session opened with LoginShare or a "share" query parameter.
+// Share links are checked on every request so revoked links stop working immediately.
+func (a *AuthService) CurrentShare(r *http.Request) (Share, bool) {
+	_, share, ok := a.identify(r)
+	if !ok || share == nil {
+		return Share{}, false
+	}
+	return *share, true
+}
+
+// CurrentSessionID returns the ID of the request's server-side session, see WithSessions.
+func (a *AuthService) CurrentSessionID(r *http.Request) (int64, bool) {
+	if a.sessions == nil {
+		return 0, false
+	}
 	session, err := a.store.Get(r, "gallery-session")
+	if err != nil || session.ID == "" {
+		return 0, false
+	}
+	stored, err := a.sessions.LookupSession(session.ID)
 	if err != nil {
-		// Log session retrieval errors for debugging
-		return false
+		return 0, false
+	}
+	return stored.ID, true
+}
+
+// identify returns the user of the request and the share link it uses, if any.
+func (a *AuthService) identify(r *http.Request) (User, *Share, bool) {
+	// Scripts send an API token instead of a session cookie
+	if value, ok := bearerToken(r); ok {
+		if a.tokens == nil {
+			return User{}, nil, false
+		}
+		token, err := a.tokens.AuthenticateAPIToken(value)
+		if err != nil {
+			return User{}, nil, false
+		}
+		return token.User(), nil, true
+	}
+
+	session, err := a.store.Get(r, "gallery-session")
+	if err != nil || !isAuthenticated(session) {
+		// Share tokens also work without a session, e.g. for direct photo links
+		if token := r.URL.Query().Get("share"); token != "" {
+			return a.shareIdentity(token)
+		}
+		return User{}, nil, false
 	}
 
-	if auth, ok := session.Values["authenticated"].(bool); ok && auth {
-		return true
+	if token, ok := session.Values["share"].(string); ok {
+		return a.shareIdentity(token)
+	}
+	userID, _ := session.Values["user_id"].(int64)
+	if userID == 0 {
+		// Guest sessions end when the gallery password changes, event
+		// sessions when the passwords of all their events changed
+		if eventOnly, _ := session.Values["event_only"].(bool); eventOnly {
+			if len(a.unlockedEvents(session)) == 0 {
+				return User{}, nil, false
+			}
+		} else {
+			credential, _ := session.Values["credential"].(string)
+			if !hmac.Equal([]byte(credential), []byte(a.guestCredential())) {
+				return User{}, nil, false
+			}
+		}
+		return guestUser, nil, true
+	}
+	if a.accounts == nil {
+		return User{}, nil, false
+	}
+	user, err := a.accounts.GetUser(userID)
+	if err != nil {
+		return User{}, nil, false
 	}
-	return false
+	return user, nil, true
+}
+
+// guestCredential identifies the configured gallery password without revealing it.
+func (a *AuthService) guestCredential() string {
+	mac := hmac.New(sha256.New, a.shareKey)
+	mac.Write([]byte("guest password\x00" + a.Password + "\x00" + a.passwordHash))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// eventCredential identifies the password hash of an event without revealing
+// it, so unlocks end when the password is changed or removed.
+func (a *AuthService) eventCredential(id int64, hash string) string {
+	mac := hmac.New(sha256.New, a.shareKey)
+	mac.Write([]byte("event password\x00" + strconv.FormatInt(id, 10) + "\x00" + hash))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// eventCredentials checks password against the event passwords and returns
+// the credentials of the events it unlocks for the session.
+func (a *AuthService) eventCredentials(password string) ([]string, bool) {
+	if a.events == nil {
+		return nil, false
+	}
+	// Hashes read before checking the password never match a newer password
+	hashes, err := a.events.EventPasswordHashes()
+	if err != nil {
+		return nil, false
+	}
+	ids, err := a.events.UnlockEvents(password)
+	if err != nil {
+		return nil, false
+	}
+	credentials := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if hash, ok := hashes[id]; ok {
+			credentials = append(credentials, a.eventCredential(id, hash))
+		}
+	}
+	return credentials, len(credentials) > 0
+}
+
+// unlockedEvents returns the IDs of the events unlocked by a session whose
+// password is unchanged since, in ascending order.
+func (a *AuthService) unlockedEvents(session *sessions.Session) []int64 {
+	credentials, _ := session.Values["event_credentials"].([]string)
+	if a.events == nil || len(credentials) == 0 {
+		return nil
+	}
+	hashes, err := a.events.EventPasswordHashes()
+	if err != nil {
+		log.Printf("Failed to load event passwords: %v", err)
+		return nil
+	}
+	var ids []int64
+	for id, hash := range hashes {
+		if slices.Contains(credentials, a.eventCredential(id, hash)) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// bearerToken returns the token of the request's "Authorization: Bearer"
+// header, if it has one. Requests with such a header are never authenticated
+// by session, even if the token is invalid.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// IsBearer reports whether the request authenticates with an API token
+// rather than a session cookie.
+func (a *AuthService) IsBearer(r *http.Request) bool {
+	_, ok := bearerToken(r)
+	return ok
 }
 
+func isAuthenticated(session *sessions.Session) bool {
+	auth, ok := session.Values["authenticated"].(bool)
+	return ok && auth
+}
+
+// shareIdentity verifies a share token. Share link visitors are viewers named
+// after the label of the link.
+func (a *AuthService) shareIdentity(token string) (User, *Share, bool) {
+	share, ok := a.verifyShareToken(token)
+	if !ok {
+		return User{}, nil, false
+	}
+	user := guestUser
+	if share.Label != "" {
+		user.Username = share.Label
+	}
+	return user, &share, true
+}
+
+// ShareToken returns the signed token of a share link. It encodes the share
+// ID and expiry, so tokens of expired links are rejected without a lookup.
+func (a *AuthService) ShareToken(share Share) string {
+	payload := make([]byte, 16)
+	binary.BigEndian.PutUint64(payload, uint64(share.ID))                 // #nosec G115 - IDs are positive
+	binary.BigEndian.PutUint64(payload[8:], uint64(share.Expires.Unix())) // #nosec G115 - expiry is after 1970
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(a.signShare(payload))
+}
+
+func (a *AuthService) signShare(payload []byte) []byte {
+	mac := hmac.New(sha256.New, a.shareKey)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// verifyShareToken returns the share link of a valid, unexpired and unrevoked token.
+func (a *AuthService) verifyShareToken(token string) (Share, bool) {
+	if a.shares == nil {
+		return Share{}, false
+	}
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return Share{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != 16 {
+		return Share{}, false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(signature, a.signShare(payload)) {
+		return Share{}, false
+	}
+
+	id := int64(binary.BigEndian.Uint64(payload))                        // #nosec G115 - signed by ShareToken
+	expires := time.Unix(int64(binary.BigEndian.Uint64(payload[8:])), 0) // #nosec G115 - signed by ShareToken
+	if !time.Now().Before(expires) {
+		return Share{}, false
+	}
+	share, err := a.shares.GetShare(id)
+	if err != nil || share.Expired(time.Now()) {
+		return Share{}, false
+	}
+	return share, true
+}
+
+// Access returns the part of the gallery the request's session may see.
+// Admins see everything; other sessions see the events without a password,
+// the events they unlocked and their own photos. Sessions started with an
+// event password only see the events they unlocked, share links only what
+// they share.
+func (a *AuthService) Access(r *http.Request) (AccessScope, bool) {
+	user, share, ok := a.identify(r)
+	if !ok {
+		return AccessScope{}, false
+	}
+	if share != nil {
+		return share.Scope(), true
+	}
+	if user.HasRole(RoleAdmin) {
+		return AccessScope{All: true}, true
+	}
+	if a.IsBearer(r) {
+		return AccessScope{Public: true}, true
+	}
+
+	session, err := a.store.Get(r, "gallery-session")
+	if err != nil {
+		return AccessScope{}, false
+	}
+	eventOnly, _ := session.Values["event_only"].(bool)
+	return AccessScope{Public: !eventOnly, Events: a.unlockedEvents(session), OwnerID: user.ID}, true
+}
+
+// Login logs the session in as guest with the shared gallery password.
 func (a *AuthService) Login(w http.ResponseWriter, r *http.Request, password string) bool {
-	if password != a.Password {
+	if a.passwordHash != "" {
+		ok, err := CheckPasswordHash(a.passwordHash, password)
+		if err != nil {
+			log.Printf("Failed to check gallery password: %v", err)
+		}
+		if !ok {
+			return false
+		}
+		return a.startSession(w, r, guestUser, sessionScope{})
+	}
+
+	// Compare hashes so neither the content nor the length of the password leaks through timing
+	given, want := sha256.Sum256([]byte(password)), sha256.Sum256([]byte(a.Password))
+	if a.Password == "" || subtle.ConstantTimeCompare(given[:], want[:]) != 1 {
+		return false
+	}
+	return a.startSession(w, r, guestUser, sessionScope{})
+}
+
+// LoginWait returns how long the client of r has to wait before it may try
+// a password for username again after too many failed attempts, or 0 if it
+// may try now. An empty username stands for the guest and event passwords.
+func (a *AuthService) LoginWait(r *http.Request, username string) time.Duration {
+	return a.limiter.Wait(a.limiterKey(r, username))
+}
+
+// RecordLogin records the outcome of a password attempt for username by the
+// client of r for LoginWait. Failures lead to exponentially growing lockouts.
+// Only logging in to the account forgets its failures: the guest and event
+// passwords are shared, so knowing them must not let a client reset its
+// failed guesses.
+func (a *AuthService) RecordLogin(r *http.Request, username string, success bool) {
+	switch {
+	case !success:
+		a.limiter.Failure(a.limiterKey(r, username))
+	case username != "":
+		a.limiter.Success(a.limiterKey(r, username))
+	}
+}
+
+// limiterKey identifies the client of r and the account it tries to log in
+// to for login throttling. IPv6 clients are grouped by /64 network, as they
+// usually control all of its addresses.
+func (a *AuthService) limiterKey(r *http.Request, username string) string {
+	client := clientIP(r, a.trustedProxies)
+	if addr, err := netip.ParseAddr(client); err == nil && addr.Is6() {
+		client = netip.PrefixFrom(addr, 64).Masked().String()
+	}
+	if username == "" {
+		return client
+	}
+	return client + " (" + username + ")"
+}
+
+// LoginEvent logs the session in as guest scoped to the events protected by password.
+func (a *AuthService) LoginEvent(w http.ResponseWriter, r *http.Request, password string) bool {
+	credentials, ok := a.eventCredentials(password)
+	if !ok {
+		return false
+	}
+	return a.startSession(w, r, guestUser, sessionScope{events: credentials})
+}
+
+// LoginShare logs the session in with a share token, scoped to the photos of the share link.
+func (a *AuthService) LoginShare(w http.ResponseWriter, r *http.Request, token string) bool {
+	user, share, ok := a.shareIdentity(token)
+	if !ok {
+		return false
+	}
+	return a.startSession(w, r, user, sessionScope{share: token, shareID: share.ID})
+}
+
+// UnlockEvents adds the events protected by password to the scope of a
+// logged-in session, until their password is changed.
+func (a *AuthService) UnlockEvents(w http.ResponseWriter, r *http.Request, password string) bool {
+	if _, share, ok := a.identify(r); !ok || share != nil {
+		return false
+	}
+	unlocked, ok := a.eventCredentials(password)
+	if !ok {
 		return false
 	}
 
+	session, err := a.store.Get(r, "gallery-session")
+	if err != nil {
+		return false
+	}
+	credentials, _ := session.Values["event_credentials"].([]string)
+	for _, credential := range unlocked {
+		if !slices.Contains(credentials, credential) {
+			credentials = append(credentials, credential)
+		}
+	}
+	session.Values["event_credentials"] = credentials
+	return session.Save(r, w) == nil
+}
+
+// LoginUser logs the session in as the user account with the given credentials.
+func (a *AuthService) LoginUser(w http.ResponseWriter, r *http.Request, username, password string) bool {
+	if a.accounts == nil {
+		return false
+	}
+	user, err := a.accounts.Authenticate(username, password)
+	if err != nil {
+		return false
+	}
+	return a.startSession(w, r, user, sessionScope{})
+}
+
+// sessionScope limits what a new session may see; the zero value does not limit it.
+type sessionScope struct {
+	events  []string // only the events with these credentials, see eventCredential
+	share   string   // only the photos of this share token, see LoginShare
+	shareID int64    // ID of the share link of share
+}
+
+// kind returns how a session with the scope logged in as user.
+func (s sessionScope) kind(user User) SessionKind {
+	switch {
+	case s.share != "":
+		return SessionKindShare
+	case s.events != nil:
+		return SessionKindEvent
+	case user.ID == 0:
+		return SessionKindGuest
+	}
+	return SessionKindUser
+}
+
+// startSession logs the session in as user, limited to scope.
+func (a *AuthService) startSession(w http.ResponseWriter, r *http.Request, user User, scope sessionScope) bool {
 	session, err := a.store.Get(r, "gallery-session")
 	if err != nil {
 		return false
@@ -68,7 +581,29 @@ func (a *AuthService) Login(w http.ResponseWriter, r *http.Request, password str
 	// Set secure cookie if using HTTPS
 	session.Options.Secure = r.Header.Get("X-Forwarded-Proto") == "https" || r.TLS != nil
 
+	// Every login gets a new server-side session
+	session.ID = ""
 	session.Values["authenticated"] = true
+	session.Values["kind"] = string(scope.kind(user))
+	session.Values["user_id"] = user.ID
+	session.Values["event_only"] = scope.events != nil
+	if scope.events != nil {
+		session.Values["event_credentials"] = scope.events
+	} else {
+		delete(session.Values, "event_credentials")
+	}
+	if scope.share != "" {
+		session.Values["share"] = scope.share
+		session.Values["share_id"] = scope.shareID
+	} else {
+		delete(session.Values, "share")
+		delete(session.Values, "share_id")
+	}
+	if scope.kind(user) == SessionKindGuest {
+		session.Values["credential"] = a.guestCredential()
+	} else {
+		delete(session.Values, "credential")
+	}
 	if err := session.Save(r, w); err != nil {
 		return false
 	}
@@ -78,6 +613,12 @@ func (a *AuthService) Login(w http.ResponseWriter, r *http.Request, password str
 func (a *AuthService) Logout(w http.ResponseWriter, r *http.Request) {
 	session, _ := a.store.Get(r, "gallery-session")
 	session.Values["authenticated"] = false
+	delete(session.Values, "user_id")
+	delete(session.Values, "event_credentials")
+	delete(session.Values, "event_only")
+	delete(session.Values, "share")
+	delete(session.Values, "share_id")
+	delete(session.Values, "credential")
 
 	// Set MaxAge to -1 to delete the cookie immediately
 	session.Options.MaxAge = -1
@@ -85,6 +626,13 @@ func (a *AuthService) Logout(w http.ResponseWriter, r *http.Request) {
 	_ = session.Save(r, w) // Ignore error on logout
 }
 
+// deriveKey derives a key for purpose from the session key.
+func deriveKey(sessionKey, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(sessionKey))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
 func generateSecretKey() string {
 	key := make([]byte, secretKeyLength)
 	if _, err := rand.Read(key); err != nil {
@@ -278,3 +278,76 @@ func TestLogoutClearsCookie(t *testing.T) {
 		t.Error("Expected logout response to contain gallery-session cookie with MaxAge=-1")
 	}
 }
+
+func TestLoginUser(t *testing.T) {
+	sessionKey := "test-session-key-32-bytes-long!!"
+	gallery := newTestServiceWithPhoto(t, "photo.png")
+	alice, err := gallery.CreateUser("alice", "alice-password", RoleContributor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewAuthService("", sessionKey, WithAccounts(gallery))
+
+	// Guest login is disabled without a shared password
+	if service.Login(httptest.NewRecorder(), httptest.NewRequest("POST", "/login", http.NoBody), "") {
+		t.Error("Expected guest login to fail without a shared password")
+	}
+	if service.LoginUser(httptest.NewRecorder(), httptest.NewRequest("POST", "/login", http.NoBody), "alice", "wrong-password") {
+		t.Error("Expected login to fail with a wrong password")
+	}
+
+	w := httptest.NewRecorder()
+	if !service.LoginUser(w, httptest.NewRequest("POST", "/login", http.NoBody), "alice", "alice-password") {
+		t.Fatal("Expected login to succeed with correct credentials")
+	}
+	r := httptest.NewRequest("GET", "/", http.NoBody)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+
+	user, ok := service.CurrentUser(r)
+	if !ok || user.ID != alice.ID || user.Role != RoleContributor {
+		t.Errorf("Expected session of alice, got %+v (%v)", user, ok)
+	}
+
+	// Role changes apply to existing sessions
+	viewer := RoleViewer
+	if _, err := gallery.UpdateUser(alice.ID, UserUpdate{Role: &viewer}); err != nil {
+		t.Fatal(err)
+	}
+	if user, _ := service.CurrentUser(r); user.Role != RoleViewer {
+		t.Errorf("Expected updated role in the session, got %s", user.Role)
+	}
+
+	// Deleted users are logged out
+	if _, err := gallery.CreateUser("admin", "admin-password", RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if err := gallery.DeleteUser(alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if service.IsAuthenticated(r) {
+		t.Error("Expected session of a deleted user to be invalid")
+	}
+}
+
+func TestGuestLoginIsViewer(t *testing.T) {
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!")
+
+	w := httptest.NewRecorder()
+	if !service.Login(w, httptest.NewRequest("POST", "/login", http.NoBody), "password") {
+		t.Fatal("Login should have succeeded")
+	}
+	r := httptest.NewRequest("GET", "/", http.NoBody)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+
+	user, ok := service.CurrentUser(r)
+	if !ok || user.ID != 0 || user.Role != RoleViewer {
+		t.Errorf("Expected guest viewer session, got %+v (%v)", user, ok)
+	}
+	if user.HasRole(RoleContributor) {
+		t.Error("Expected guests not to upload")
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrBlobNotFound is returned by a BlobStore when the requested blob does not exist.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobInfo describes a stored blob.
+type BlobInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// BlobStore stores the binary content of photos and their derivatives
+// (thumbnails) under flat names.
+type BlobStore interface {
+	// Put stores the content of r under name, replacing an existing blob.
+	// size is the content length, or -1 if unknown.
+	Put(name string, r io.Reader, size int64) error
+	// Open returns a reader for the blob or ErrBlobNotFound.
+	Open(name string) (io.ReadSeekCloser, BlobInfo, error)
+	// Stat returns information about the blob or ErrBlobNotFound.
+	Stat(name string) (BlobInfo, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(name string) error
+	// List returns all blobs in the store.
+	List() ([]BlobInfo, error)
+}
+
+// localPather is implemented by blob stores that keep blobs as local files,
+// so tools that need a path (exiftool) can skip downloading a copy.
+type localPather interface {
+	LocalPath(name string) string
+}
+
+// FileBlobStore is a BlobStore keeping each blob as a file in a directory.
+type FileBlobStore struct {
+	dir string
+}
+
+// NewFileBlobStore creates a blob store in dir, creating the directory if needed.
+func NewFileBlobStore(dir string) (*FileBlobStore, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory %s: %w", dir, err)
+	}
+	return &FileBlobStore{dir: dir}, nil
+}
+
+func (f *FileBlobStore) LocalPath(name string) string {
+	return filepath.Join(f.dir, filepath.Base(name))
+}
+
+func (f *FileBlobStore) Put(name string, r io.Reader, _ int64) error {
+	// Write to a hidden temporary file first so readers (and the upload
+	// directory watcher) never see a half-written blob
+	tmp, err := os.CreateTemp(f.dir, ".blob-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(tmp.Name()); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove temporary file %s: %v", tmp.Name(), err)
+		}
+	}()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), f.LocalPath(name)); err != nil {
+		return fmt.Errorf("failed to store %s: %w", name, err)
+	}
+	return nil
+}
+
+func (f *FileBlobStore) Open(name string) (io.ReadSeekCloser, BlobInfo, error) {
+	// #nosec G304 - path is constructed from the controlled blob directory and a base name
+	file, err := os.Open(f.LocalPath(name))
+	if os.IsNotExist(err) {
+		return nil, BlobInfo{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, BlobInfo{}, err
+	}
+	if fileInfo.IsDir() {
+		_ = file.Close()
+		return nil, BlobInfo{}, ErrBlobNotFound
+	}
+	return file, fileBlobInfo(fileInfo), nil
+}
+
+func (f *FileBlobStore) Stat(name string) (BlobInfo, error) {
+	fileInfo, err := os.Stat(f.LocalPath(name))
+	if os.IsNotExist(err) || (err == nil && fileInfo.IsDir()) {
+		return BlobInfo{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	return fileBlobInfo(fileInfo), nil
+}
+
+func (f *FileBlobStore) Delete(name string) error {
+	if err := os.Remove(f.LocalPath(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *FileBlobStore) List() ([]BlobInfo, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []BlobInfo
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		fileInfo, err := entry.Info()
+		if err != nil {
+			continue // Removed since ReadDir
+		}
+		blobs = append(blobs, fileBlobInfo(fileInfo))
+	}
+	return blobs, nil
+}
+
+func fileBlobInfo(fileInfo os.FileInfo) BlobInfo {
+	return BlobInfo{
+		Name:    fileInfo.Name(),
+		Size:    fileInfo.Size(),
+		ModTime: fileInfo.ModTime(),
+	}
+}
+
+// withLocalFile calls fn with the path of a local file holding the blob's
+// content, downloading a temporary copy if the store is not file based.
+func withLocalFile(store BlobStore, name string, fn func(path string) error) error {
+	if local, ok := store.(localPather); ok {
+		return fn(local.LocalPath(name))
+	}
+
+	reader, _, err := store.Open(name)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	tmp, err := os.CreateTemp("", "gallery-*"+filepath.Ext(name))
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(tmp.Name()); err != nil {
+			log.Printf("Failed to remove temporary file %s: %v", tmp.Name(), err)
+		}
+	}()
+
+	if _, err := io.Copy(tmp, reader); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return fn(tmp.Name())
+}
+
+// moveBlob moves a blob from one store to another. Blobs between two file
+// stores are renamed, otherwise the content is copied and the source deleted.
+func moveBlob(from, to BlobStore, name string) error {
+	if src, ok := from.(*FileBlobStore); ok {
+		if dst, ok := to.(*FileBlobStore); ok {
+			err := os.Rename(src.LocalPath(name), dst.LocalPath(name))
+			if os.IsNotExist(err) {
+				return ErrBlobNotFound
+			}
+			if err == nil {
+				return nil
+			}
+			// Fall back to copying, e.g. across filesystems
+		}
+	}
+
+	reader, info, err := from.Open(name)
+	if err != nil {
+		return err
+	}
+	err = to.Put(name, reader, info.Size)
+	if closeErr := reader.Close(); closeErr != nil {
+		log.Printf("Failed to close blob %s: %v", name, closeErr)
+	}
+	if err != nil {
+		return err
+	}
+	return from.Delete(name)
+}
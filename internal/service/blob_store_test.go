@@ -0,0 +1,173 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// testBlobStore runs the behaviour every BlobStore implementation must share.
+func testBlobStore(t *testing.T, store BlobStore) {
+	t.Helper()
+
+	content := []byte("photo content")
+	if err := store.Put("a.jpg", bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := store.Put("b.png", bytes.NewReader([]byte("other")), 5); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	info, err := store.Stat("a.jpg")
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Name != "a.jpg" || info.Size != int64(len(content)) {
+		t.Errorf("Unexpected blob info: %+v", info)
+	}
+
+	reader, _, err := store.Open("a.jpg")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	// Readers must support seeking for range requests
+	if _, err := reader.Seek(6, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	rest, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != "content" {
+		t.Errorf("Expected %q after seek, got %q", "content", rest)
+	}
+
+	blobs, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var names []string
+	for _, blob := range blobs {
+		names = append(names, blob.Name)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "a.jpg" || names[1] != "b.png" {
+		t.Errorf("Expected [a.jpg b.png], got %v", names)
+	}
+
+	if err := store.Delete("a.jpg"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := store.Delete("a.jpg"); err != nil {
+		t.Errorf("Deleting a missing blob should not fail, got %v", err)
+	}
+	if _, err := store.Stat("a.jpg"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected ErrBlobNotFound from Stat, got %v", err)
+	}
+	if _, _, err := store.Open("a.jpg"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected ErrBlobNotFound from Open, got %v", err)
+	}
+}
+
+func TestFileBlobStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "blobs")
+	store := newTestFileBlobStore(t, dir)
+
+	testBlobStore(t, store)
+
+	// No temporary files may be left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "b.png" {
+		t.Errorf("Expected only b.png in blob directory, got %v", entries)
+	}
+}
+
+func TestFileBlobStoreRejectsTraversal(t *testing.T) {
+	tempDir := t.TempDir()
+	store := newTestFileBlobStore(t, filepath.Join(tempDir, "blobs"))
+
+	if err := store.Put("../escaped.jpg", bytes.NewReader([]byte("x")), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "escaped.jpg")); err == nil {
+		t.Error("Blob must not be written outside the store directory")
+	}
+}
+
+func TestWithLocalFile(t *testing.T) {
+	fileStore := newTestFileBlobStore(t, t.TempDir())
+	if err := fileStore.Put("a.jpg", bytes.NewReader([]byte("abc")), 3); err != nil {
+		t.Fatal(err)
+	}
+
+	// File stores hand out their own path
+	err := withLocalFile(fileStore, "a.jpg", func(path string) error {
+		if path != fileStore.LocalPath("a.jpg") {
+			t.Errorf("Expected local path %s, got %s", fileStore.LocalPath("a.jpg"), path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other stores get a temporary copy that is removed afterwards
+	s3Store := newTestS3BlobStore(t, "photos")
+	if err := s3Store.Put("b.jpg", bytes.NewReader([]byte("remote")), 6); err != nil {
+		t.Fatal(err)
+	}
+	var tempPath string
+	err = withLocalFile(s3Store, "b.jpg", func(path string) error {
+		tempPath = path
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if string(data) != "remote" {
+			t.Errorf("Expected downloaded content %q, got %q", "remote", data)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(tempPath); !os.IsNotExist(err) {
+		t.Errorf("Expected temporary copy %s to be removed", tempPath)
+	}
+}
+
+func TestMoveBlob(t *testing.T) {
+	tempDir := t.TempDir()
+	live := newTestFileBlobStore(t, filepath.Join(tempDir, "live"))
+	trash := newTestFileBlobStore(t, filepath.Join(tempDir, "live", ".trash"))
+	remote := newTestS3BlobStore(t, "trash")
+
+	if err := live.Put("a.jpg", bytes.NewReader([]byte("abc")), 3); err != nil {
+		t.Fatal(err)
+	}
+
+	// Between file stores, then across backends and back
+	for _, step := range []struct{ from, to BlobStore }{{live, trash}, {trash, remote}, {remote, live}} {
+		if err := moveBlob(step.from, step.to, "a.jpg"); err != nil {
+			t.Fatalf("moveBlob failed: %v", err)
+		}
+		if _, err := step.from.Stat("a.jpg"); !errors.Is(err, ErrBlobNotFound) {
+			t.Errorf("Expected source blob to be gone, got %v", err)
+		}
+		if info, err := step.to.Stat("a.jpg"); err != nil || info.Size != 3 {
+			t.Errorf("Expected moved blob, got %+v, %v", info, err)
+		}
+	}
+
+	if err := moveBlob(trash, live, "missing.jpg"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected ErrBlobNotFound for a missing blob, got %v", err)
+	}
+}
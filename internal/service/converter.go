@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// derivativeQuality is the JPEG quality of converted photos (0-100).
+const derivativeQuality = 90
+
+// PhotoFormat selects the version of photos in downloads.
+type PhotoFormat string
+
+const (
+	// PhotoFormatOriginal is the photo as uploaded.
+	PhotoFormatOriginal PhotoFormat = "original"
+	// PhotoFormatJPEG is the JPEG version of photos in formats that need
+	// conversion, e.g. HEIC, and the original of all others.
+	PhotoFormatJPEG PhotoFormat = "jpeg"
+)
+
+// ImageConverter converts photos in formats that cannot be decoded by the
+// gallery, like HEIC, to JPEG. The JPEG is kept as display derivative next to
+// the original and used for thumbnails, duplicate detection and browsers.
+type ImageConverter interface {
+	// ConvertToJPEG converts the image file at src to a JPEG file at dst.
+	ConvertToJPEG(src, dst string) error
+}
+
+// WithImageConverter converts HEIC and HEIF photos with converter instead of
+// the heif-convert tool found in PATH.
+func WithImageConverter(converter ImageConverter) GalleryOption {
+	return func(s *GalleryService) {
+		s.converter = converter
+	}
+}
+
+// HeifConvert converts HEIC and HEIF photos with the heif-convert tool of libheif.
+type HeifConvert struct {
+	Path string // Path of the heif-convert executable
+}
+
+// FindHeifConvert returns a converter using heif-convert from PATH, or nil if
+// it is not installed.
+func FindHeifConvert() *HeifConvert {
+	path, err := exec.LookPath("heif-convert")
+	if err != nil {
+		return nil
+	}
+	return &HeifConvert{Path: path}
+}
+
+// ConvertToJPEG implements ImageConverter.
+func (c *HeifConvert) ConvertToJPEG(src, dst string) error {
+	cmd := exec.Command(c.Path, "-q", strconv.Itoa(derivativeQuality), src, dst) // #nosec G204 - the tool is configured, not user input
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("heif-convert failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// needsConversion reports whether photos with the name are converted to a
+// JPEG derivative, because they cannot be decoded or shown by browsers.
+func needsConversion(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".heic", ".heif":
+		return true
+	}
+	return false
+}
+
+// Converted reports whether the photo has a JPEG version for browsers and
+// downloads, see ServeDisplayPhoto.
+func (p PhotoInfo) Converted() bool {
+	return needsConversion(p.Name)
+}
+
+// jpegName returns the name of the JPEG derivative of a photo in downloads.
+func jpegName(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + ".jpg"
+}
+
+// createDerivative converts the original of filename at path to JPEG, stores
+// it as the photo's derivative and returns it.
+func (s *GalleryService) createDerivative(path, filename string) ([]byte, error) {
+	if s.converter == nil {
+		return nil, fmt.Errorf("no converter for %s, install heif-convert", filepath.Ext(filename))
+	}
+
+	dir, err := os.MkdirTemp("", "convert-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("Failed to remove temporary directory %s: %v", dir, err)
+		}
+	}()
+
+	dst := filepath.Join(dir, "derivative.jpg")
+	if err := s.converter.ConvertToJPEG(path, dst); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(dst) // #nosec G304 - dst is in our temporary directory
+	if err != nil {
+		return nil, fmt.Errorf("failed to read converted image: %w", err)
+	}
+	if err := s.derivatives.Put(filename, bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, fmt.Errorf("failed to store converted image: %w", err)
+	}
+	return data, nil
+}
+
+// openDecodable opens the version of a photo that can be decoded: the JPEG
+// derivative of converted photos, else the original.
+func (s *GalleryService) openDecodable(filename string) (io.ReadSeekCloser, BlobInfo, error) {
+	if needsConversion(filename) {
+		return s.derivatives.Open(filename)
+	}
+	return s.photos.Open(filename)
+}
+
+// ServeDisplayPhoto opens the version of a photo that browsers can show: the
+// JPEG derivative of photos converted on upload or rotated for their EXIF
+// orientation, named like a JPEG file, and the original of all others and of
+// photos that could not be converted. The caller must close the reader.
+func (s *GalleryService) ServeDisplayPhoto(filename string) (io.ReadSeekCloser, BlobInfo, error) {
+	if needsConversion(filename) || s.rotatedCopies {
+		if derivative, info, err := s.derivatives.Open(filename); err == nil {
+			info.Name = jpegName(filename)
+			return derivative, info, nil
+		}
+	}
+	return s.photos.Open(filename)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"image"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// fakeConverter writes a fixed JPEG instead of converting, or fails with err.
+type fakeConverter struct {
+	jpeg []byte
+	err  error
+}
+
+func (c *fakeConverter) ConvertToJPEG(src, dst string) error {
+	if c.err != nil {
+		return c.err
+	}
+	return os.WriteFile(dst, c.jpeg, 0o600)
+}
+
+func TestSaveConvertedPhoto(t *testing.T) {
+	tempDir := t.TempDir()
+	converter := &fakeConverter{jpeg: encodeTestImage(t, createPatternImage(640, 480, false), "jpeg")}
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"),
+		WithImageConverter(converter))
+
+	original := []byte("not really a HEIC file")
+	saved, err := service.SavePhoto(newTestFileHeader(t, "IMG_0001.heic", "image/heic", original), User{Username: "Alice"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved.Photo.Converted() || saved.Photo.PerceptualHash == 0 {
+		t.Errorf("Expected a converted photo with perceptual hash, got %+v", saved.Photo)
+	}
+
+	// The original is kept as uploaded
+	photo, info, err := service.ServePhoto(saved.Photo.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(photo)
+	photo.Close()
+	if err != nil || !bytes.Equal(data, original) || info.Name != saved.Photo.Name {
+		t.Errorf("Expected original %s, got %q as %s (%v)", saved.Photo.Name, data, info.Name, err)
+	}
+
+	display, info, err := service.ServeDisplayPhoto(saved.Photo.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = io.ReadAll(display)
+	display.Close()
+	if err != nil || !bytes.Equal(data, converter.jpeg) || info.Name != jpegName(saved.Photo.Name) {
+		t.Errorf("Expected JPEG version named %s, got %s (%v)", jpegName(saved.Photo.Name), info.Name, err)
+	}
+
+	thumbnail, _, err := service.ServeThumbnail(saved.Photo.Name)
+	if err != nil {
+		t.Fatalf("Expected a thumbnail, got %v", err)
+	}
+	config, format, err := image.DecodeConfig(thumbnail)
+	thumbnail.Close()
+	if err != nil || format != "jpeg" || config.Width != thumbnailSize {
+		t.Errorf("Expected %dpx JPEG thumbnail, got %dpx %s (%v)", thumbnailSize, config.Width, format, err)
+	}
+
+	// A JPEG with the name of the JPEG version must not be overwritten in archives
+	other, err := service.SavePhoto(newTestFileHeader(t, "IMG_0001.jpg", "image/jpeg",
+		encodeTestImage(t, createPatternImage(64, 64, true), "jpeg")), User{Username: "Alice"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	photos := []PhotoInfo{saved.Photo, other.Photo}
+
+	tests := []struct {
+		format PhotoFormat
+		want   map[string][]byte
+	}{
+		{PhotoFormatOriginal, map[string][]byte{saved.Photo.Name: original}},
+		{PhotoFormatJPEG, map[string][]byte{saved.Photo.Name + ".jpg": converter.jpeg}},
+	}
+	for _, tt := range tests {
+		entries := readTestZip(t, service, photos, tt.format)
+		if len(entries) != 2 {
+			t.Errorf("%s: expected 2 entries, got %v", tt.format, slices.Collect(maps.Keys(entries)))
+		}
+		for name, want := range tt.want {
+			if !bytes.Equal(entries[name], want) {
+				t.Errorf("%s: unexpected content of %s", tt.format, name)
+			}
+		}
+		if _, ok := entries[other.Photo.Name]; !ok {
+			t.Errorf("%s: expected %s in archive", tt.format, other.Photo.Name)
+		}
+	}
+}
+
+func TestSaveConvertedPhotoWithoutConversion(t *testing.T) {
+	tempDir := t.TempDir()
+	converter := &fakeConverter{err: errors.New("unsupported codec")}
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"),
+		WithImageConverter(converter))
+
+	// Photos that cannot be converted are still accepted
+	original := []byte("not really a HEIF file")
+	saved, err := service.SavePhoto(newTestFileHeader(t, "IMG_0002.HEIF", "image/heif", original), User{Username: "Alice"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.ServeThumbnail(saved.Photo.Name); err == nil {
+		t.Error("Expected no thumbnail")
+	}
+	display, info, err := service.ServeDisplayPhoto(saved.Photo.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	display.Close()
+	if info.Name != saved.Photo.Name {
+		t.Errorf("Expected original without JPEG version, got %s", info.Name)
+	}
+
+	// and archived as original
+	entries := readTestZip(t, service, []PhotoInfo{saved.Photo}, PhotoFormatJPEG)
+	if !bytes.Equal(entries[saved.Photo.Name], original) {
+		t.Errorf("Expected original in archive, got %v", slices.Collect(maps.Keys(entries)))
+	}
+}
+
+// readTestZip archives the photos and returns the entries by name.
+func readTestZip(t *testing.T, service *GalleryService, photos []PhotoInfo, format PhotoFormat) map[string][]byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := service.CreateZipArchive(photos, format, &buf); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := make(map[string][]byte)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[file.Name] = data
+	}
+	return entries
+}
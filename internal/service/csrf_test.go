@@ -0,0 +1,69 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCSRF(t *testing.T) {
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!")
+
+	w := httptest.NewRecorder()
+	token := service.CSRFToken(w, httptest.NewRequest("GET", "/login", http.NoBody))
+	cookies := w.Result().Cookies()
+	if token == "" || len(cookies) != 1 || cookies[0].Value != token || !cookies[0].HttpOnly {
+		t.Fatalf("Expected token %q in an HTTP-only cookie, got %v", token, cookies)
+	}
+
+	// The token of a valid cookie is kept
+	r := withCookies(w)
+	again := httptest.NewRecorder()
+	if got := service.CSRFToken(again, r); got != token || len(again.Result().Cookies()) != 0 {
+		t.Errorf("Expected existing token to be reused, got %q", got)
+	}
+
+	post := func(cookie, field, header string) *http.Request {
+		r := httptest.NewRequest("POST", "/login", strings.NewReader(url.Values{"csrf_token": {field}}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if cookie != "" {
+			r.AddCookie(&http.Cookie{Name: csrfCookieName, Value: cookie})
+		}
+		if header != "" {
+			r.Header.Set(csrfHeaderName, header)
+		}
+		return r
+	}
+
+	other := NewAuthService("password", "another-session-key-32-bytes!!!!")
+	foreign := other.CSRFToken(httptest.NewRecorder(), httptest.NewRequest("GET", "/", http.NoBody))
+	unsigned := strings.Split(token, ".")[0] + ".AAAA"
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		want bool
+	}{
+		{"form field", post(token, token, ""), true},
+		{"header", post(token, "", token), true},
+		{"missing token", post(token, "", ""), false},
+		{"missing cookie", post("", token, ""), false},
+		{"mismatch", post(token, foreign, ""), false},
+		{"foreign key", post(foreign, foreign, ""), false},
+		{"unsigned cookie", post(unsigned, unsigned, ""), false},
+	}
+	for _, tt := range tests {
+		if got := service.VerifyCSRF(tt.r); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// API clients do not need a token
+	bearer := post("", "", "")
+	bearer.Header.Set("Authorization", "Bearer gallery_token")
+	if !service.VerifyCSRF(bearer) {
+		t.Error("Expected bearer requests to be exempt")
+	}
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"math/bits"
+	"slices"
+	"time"
+)
+
+const (
+	// similarityThreshold is the largest number of differing dHash bits for
+	// which two photos are considered near-duplicates.
+	similarityThreshold = 8
+
+	// dHashSamples is the number of pixels sampled per axis in each cell of the
+	// 9x8 dHash grid, so hashing cost does not grow with the image size.
+	dHashSamples = 8
+)
+
+// ErrInvalidResolution is returned by ResolveDuplicates for a malformed request.
+var ErrInvalidResolution = errors.New("invalid duplicate resolution")
+
+// DuplicateCluster is a group of photos that look alike.
+type DuplicateCluster struct {
+	Photos []PhotoInfo // sorted like GetPhotos
+}
+
+// dHash computes the 64-bit difference hash of img: the image is reduced to a
+// 9x8 grayscale grid and each bit records whether a cell is brighter than its
+// right neighbour. Resized or recompressed copies yield (nearly) the same hash.
+// A hash of 0 is reserved for "unknown" and is never returned.
+func dHash(img image.Image) uint64 {
+	const cols, rows = 9, 8
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return 1
+	}
+
+	var grid [rows][cols]float64
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			var sum float64
+			for sy := 0; sy < dHashSamples; sy++ {
+				y := bounds.Min.Y + ((row*dHashSamples+sy)*height+height/2)/(rows*dHashSamples)
+				for sx := 0; sx < dHashSamples; sx++ {
+					x := bounds.Min.X + ((col*dHashSamples+sx)*width+width/2)/(cols*dHashSamples)
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+				}
+			}
+			grid[row][col] = sum
+		}
+	}
+
+	var hash uint64
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols-1; col++ {
+			hash <<= 1
+			if grid[row][col] < grid[row][col+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	if hash == 0 {
+		// Flat images: keep them comparable with each other but distinguishable from "unknown"
+		hash = 1
+	}
+	return hash
+}
+
+func hammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// FindDuplicates groups photos whose perceptual hashes are within
+// similarityThreshold of each other. Pairs an admin decided to keep are not
+// linked. Clusters are ordered by their newest photo.
+func (s *GalleryService) FindDuplicates() ([]DuplicateCluster, error) {
+	photos := s.index.list(PhotoFilter{})
+	dismissed, err := s.store.DismissedDuplicates()
+	if err != nil {
+		return nil, err
+	}
+
+	// Union-find over the photo positions
+	parent := make([]int, len(photos))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range photos {
+		if photos[i].PerceptualHash == 0 {
+			continue
+		}
+		for j := i + 1; j < len(photos); j++ {
+			if photos[j].PerceptualHash == 0 {
+				continue
+			}
+			if hammingDistance(photos[i].PerceptualHash, photos[j].PerceptualHash) > similarityThreshold {
+				continue
+			}
+			if dismissed[duplicatePair(photos[i].ID, photos[j].ID)] {
+				continue
+			}
+			parent[find(j)] = find(i)
+		}
+	}
+
+	// photos is sorted newest first, so clusters and their members are too
+	members := make(map[int][]PhotoInfo)
+	var roots []int
+	for i, photo := range photos {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], photo)
+	}
+
+	var clusters []DuplicateCluster
+	for _, root := range roots {
+		if len(members[root]) > 1 {
+			clusters = append(clusters, DuplicateCluster{Photos: members[root]})
+		}
+	}
+	return clusters, nil
+}
+
+// ResolveDuplicates settles a duplicate cluster: the photos in keep stay and
+// are no longer reported as duplicates of each other, the photos in remove are
+// moved to the trash and their uploaders are credited on the first kept photo.
+// All photos must belong to the same cluster of FindDuplicates.
+func (s *GalleryService) ResolveDuplicates(keep, remove []string) error {
+	if len(keep) == 0 {
+		return fmt.Errorf("%w: at least one photo must be kept", ErrInvalidResolution)
+	}
+	for _, name := range remove {
+		if slices.Contains(keep, name) {
+			return fmt.Errorf("%w: %s is both kept and removed", ErrInvalidResolution, name)
+		}
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	load := func(names []string) ([]PhotoInfo, error) {
+		photos := make([]PhotoInfo, 0, len(names))
+		for _, name := range names {
+			photo, err := s.store.GetPhoto(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s: %w", name, err)
+			}
+			photos = append(photos, photo)
+		}
+		return photos, nil
+	}
+	kept, err := load(keep)
+	if err != nil {
+		return err
+	}
+	removed, err := load(remove)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCluster(append(slices.Clone(kept), removed...)); err != nil {
+		return err
+	}
+
+	// Settle the metadata at once before touching any blobs
+	if err := s.store.ResolveDuplicates(kept, removed, time.Now()); err != nil {
+		return err
+	}
+	target := kept[0].Name
+	for _, photo := range removed {
+		if err := s.moveToTrash(photo.Name); err != nil {
+			return err
+		}
+		log.Printf("Removed %s as a duplicate of %s", photo.Name, target)
+	}
+
+	updated, err := s.store.GetPhoto(target)
+	if err != nil {
+		return err
+	}
+	s.index.put(updated)
+	return nil
+}
+
+// checkCluster returns ErrInvalidResolution unless photos are distinct
+// members of a single cluster of FindDuplicates.
+func (s *GalleryService) checkCluster(photos []PhotoInfo) error {
+	clusters, err := s.FindDuplicates()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(clusters, func(c DuplicateCluster) bool {
+		return slices.ContainsFunc(c.Photos, func(p PhotoInfo) bool { return p.ID == photos[0].ID })
+	})
+	if i < 0 {
+		return fmt.Errorf("%w: %s has no near-duplicates", ErrInvalidResolution, photos[0].Name)
+	}
+
+	seen := make(map[int64]bool, len(photos))
+	for _, photo := range photos {
+		if seen[photo.ID] {
+			return fmt.Errorf("%w: %s is listed twice", ErrInvalidResolution, photo.Name)
+		}
+		seen[photo.ID] = true
+		if !slices.ContainsFunc(clusters[i].Photos, func(p PhotoInfo) bool { return p.ID == photo.ID }) {
+			return fmt.Errorf("%w: %s is not a near-duplicate of %s", ErrInvalidResolution, photo.Name, photos[0].Name)
+		}
+	}
+	return nil
+}
+
+// GenerateMissingPerceptualHashes computes the perceptual hash of photos
+// stored before near-duplicate detection existed.
+func (s *GalleryService) GenerateMissingPerceptualHashes() {
+	photos, err := s.store.ListPhotos(PhotoFilter{})
+	if err != nil {
+		log.Printf("Failed to list photo metadata: %v", err)
+		return
+	}
+
+	hashedCount := 0
+	for _, photo := range photos {
+		if photo.PerceptualHash != 0 {
+			continue
+		}
+
+		hash, err := s.perceptualHashFromPhoto(photo.Name)
+		if err != nil {
+			log.Printf("Failed to compute perceptual hash for %s: %v", photo.Name, err)
+			continue
+		}
+		if err := s.store.SetPerceptualHash(photo.Name, hash); err != nil {
+			log.Printf("Failed to save perceptual hash for %s: %v", photo.Name, err)
+			continue
+		}
+		hashedCount++
+	}
+
+	if hashedCount > 0 {
+		log.Printf("Computed perceptual hashes for %d existing photos", hashedCount)
+	}
+}
+
+func (s *GalleryService) perceptualHashFromPhoto(filename string) (uint64, error) {
+	original, _, err := s.openDecodable(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer original.Close()
+
+	orientation := readOrientation(original)
+	if _, err := original.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	img, _, err := image.Decode(original)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return dHash(orientedView(img, orientation)), nil
+}
+
+// duplicatePair is the key of a dismissed pair, independent of argument order.
+func duplicatePair(a, b int64) [2]int64 {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int64{a, b}
+}
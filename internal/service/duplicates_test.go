@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"path/filepath"
+	"testing"
+)
+
+// Helper function to create an image with enough structure for perceptual hashing
+func createPatternImage(width, height int, mirrored bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fx := x * 255 / width
+			if mirrored {
+				fx = 255 - fx
+			}
+			fy := y * 255 / height
+			// Diagonal bands on top of a horizontal gradient
+			band := uint8(0)
+			if ((x*8/width)+(y*8/height))%2 == 0 {
+				band = 60
+			}
+			img.Set(x, y, color.RGBA{uint8(fx), uint8(fy), uint8(fx/2) + band, 255})
+		}
+	}
+	return img
+}
+
+func encodeTestImage(t *testing.T, img image.Image, format string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 40})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDHash(t *testing.T) {
+	original := createPatternImage(400, 300, false)
+
+	// A downscaled, heavily recompressed copy
+	recompressed, err := jpeg.Decode(bytes.NewReader(encodeTestImage(t, createPatternImage(200, 150, false), "jpeg")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distance := hammingDistance(dHash(original), dHash(recompressed)); distance > similarityThreshold {
+		t.Errorf("Expected recompressed copy within threshold, distance %d", distance)
+	}
+
+	different := createPatternImage(400, 300, true)
+	if distance := hammingDistance(dHash(original), dHash(different)); distance <= similarityThreshold {
+		t.Errorf("Expected different image beyond threshold, distance %d", distance)
+	}
+
+	if dHash(image.NewRGBA(image.Rect(0, 0, 10, 10))) == 0 {
+		t.Error("dHash must never return the reserved value 0 for flat images")
+	}
+}
+
+func TestFindAndResolveDuplicates(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	uploads := []struct {
+		filename, contentType, uploader string
+		data                            []byte
+	}{
+		{"original.png", "image/png", "Alice", encodeTestImage(t, createPatternImage(400, 300, false), "png")},
+		{"whatsapp.jpg", "image/jpeg", "Bob", encodeTestImage(t, createPatternImage(200, 150, false), "jpeg")},
+		{"other.png", "image/png", "Carol", encodeTestImage(t, createPatternImage(400, 300, true), "png")},
+	}
+	for _, upload := range uploads {
+		fileHeader := newTestFileHeader(t, upload.filename, upload.contentType, upload.data)
+		if _, err := service.SavePhoto(fileHeader, User{Username: upload.uploader}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clusters, err := service.FindDuplicates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 || len(clusters[0].Photos) != 2 {
+		t.Fatalf("Expected one cluster of two photos, got %+v", clusters)
+	}
+
+	if err := service.ResolveDuplicates([]string{"original.png"}, []string{"original.png"}); !errors.Is(err, ErrInvalidResolution) {
+		t.Errorf("Expected ErrInvalidResolution, got %v", err)
+	}
+	if err := service.ResolveDuplicates([]string{"original.png"}, []string{"whatsapp.jpg"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.photos.Stat("whatsapp.jpg"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected removed copy to be deleted, got %v", err)
+	}
+	if _, ok := service.index.get("whatsapp.jpg"); ok {
+		t.Error("Expected removed copy to leave the index")
+	}
+	kept, ok := service.index.get("original.png")
+	if !ok || len(kept.Contributors) != 1 || kept.Contributors[0] != "Bob" {
+		t.Errorf("Expected Bob to be credited on the kept photo, got %+v", kept)
+	}
+
+	clusters, err = service.FindDuplicates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 0 {
+		t.Errorf("Expected no clusters after resolving, got %d", len(clusters))
+	}
+}
+
+func TestResolveDuplicatesOutsideCluster(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	uploads := []struct {
+		filename string
+		mirrored bool
+	}{
+		{"original.png", false},
+		{"copy.png", false},
+		{"other.png", true},
+	}
+	for i, upload := range uploads {
+		data := encodeTestImage(t, createPatternImage(400-i, 300, upload.mirrored), "png")
+		if _, err := service.SavePhoto(newTestFileHeader(t, upload.filename, "image/png", data), User{Username: "Alice"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		keep, remove []string
+	}{
+		{"photo of another cluster", []string{"original.png"}, []string{"other.png"}},
+		{"photo without duplicates", []string{"other.png"}, []string{"copy.png"}},
+		{"photo listed twice", []string{"original.png"}, []string{"copy.png", "copy.png"}},
+	}
+	for _, tt := range tests {
+		if err := service.ResolveDuplicates(tt.keep, tt.remove); !errors.Is(err, ErrInvalidResolution) {
+			t.Errorf("%s: expected ErrInvalidResolution, got %v", tt.name, err)
+		}
+	}
+	if service.index.count() != 3 {
+		t.Errorf("Expected no photo to be removed, got %d photos", service.index.count())
+	}
+	if err := service.ResolveDuplicates([]string{"original.png"}, []string{"copy.png"}); err != nil {
+		t.Errorf("Expected photos of one cluster to be resolved, got %v", err)
+	}
+}
+
+func TestResolveDuplicatesKeepAll(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	for _, filename := range []string{"a.png", "b.jpg"} {
+		format, contentType := "png", "image/png"
+		if filename == "b.jpg" {
+			format, contentType = "jpeg", "image/jpeg"
+		}
+		data := encodeTestImage(t, createPatternImage(300, 200, false), format)
+		if _, err := service.SavePhoto(newTestFileHeader(t, filename, contentType, data), User{Username: "Alice"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := service.ResolveDuplicates([]string{"a.png", "b.jpg"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	clusters, err := service.FindDuplicates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 0 {
+		t.Errorf("Expected dismissed pair not to be reported, got %+v", clusters)
+	}
+	if service.index.count() != 2 {
+		t.Errorf("Expected both photos to be kept, got %d", service.index.count())
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidEvent is returned for event changes with invalid values.
+var ErrInvalidEvent = errors.New("invalid event")
+
+// Event groups the photos that share an event name.
+type Event struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	StartDate   time.Time `json:"start_date,omitzero"` // set explicitly or derived from the photos
+	EndDate     time.Time `json:"end_date,omitzero"`
+	CoverPhoto  string    `json:"cover_photo,omitempty"` // chosen cover, else the newest photo
+	PhotoCount  int       `json:"photo_count"`
+	// Protected is set for events that have an access password
+	Protected bool `json:"protected"`
+}
+
+// ListEvents returns all events in alphabetical order. Dates and cover photo
+// that were not set explicitly are derived from the event's photos.
+func (s *GalleryService) ListEvents() ([]Event, error) {
+	events, err := s.store.ListEvents()
+	if err != nil {
+		return nil, err
+	}
+	s.completeEvents(events)
+	return events, nil
+}
+
+// GetEvent returns an event like ListEvents or ErrEventNotFound.
+func (s *GalleryService) GetEvent(id int64) (Event, error) {
+	event, err := s.store.GetEvent(id)
+	if err != nil {
+		return Event{}, err
+	}
+	events := []Event{event}
+	s.completeEvents(events)
+	return events[0], nil
+}
+
+// UpdateEvent changes an event's details. Renaming moves all its photos to the
+// new name; renaming to the name of another event fails with ErrEventExists,
+// use MergeEvents for that. Setting a password hides the event's photos from
+// sessions that have not unlocked it, see PhotoAccess.
+func (s *GalleryService) UpdateEvent(id int64, update EventUpdate) (Event, error) {
+	update, err := normalizeEventUpdate(update)
+	if err != nil {
+		return Event{}, err
+	}
+	if update.Password != nil && *update.Password != "" {
+		hash, err := hashPassword(*update.Password, ErrInvalidEvent)
+		if err != nil {
+			return Event{}, err
+		}
+		update.Password = &hash
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	current, err := s.store.GetEvent(id)
+	if err != nil {
+		return Event{}, err
+	}
+
+	startDate, endDate := current.StartDate, current.EndDate
+	if update.StartDate != nil {
+		startDate = *update.StartDate
+	}
+	if update.EndDate != nil {
+		endDate = *update.EndDate
+	}
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		return Event{}, fmt.Errorf("%w: end date is before start date", ErrInvalidEvent)
+	}
+
+	if update.CoverPhoto != nil && *update.CoverPhoto != "" {
+		photo, err := s.store.GetPhoto(*update.CoverPhoto)
+		if err != nil {
+			return Event{}, err
+		}
+		if photo.Event != current.Name {
+			return Event{}, fmt.Errorf("%w: %s is not part of %s", ErrInvalidEvent, photo.Name, current.Name)
+		}
+	}
+
+	if err := s.store.UpdateEvent(id, update); err != nil {
+		return Event{}, err
+	}
+	if update.Name != nil && *update.Name != current.Name {
+		if err := s.reindexEvent(*update.Name); err != nil {
+			return Event{}, err
+		}
+	}
+	return s.GetEvent(id)
+}
+
+// MergeEvents moves all photos of the source events to the target event and
+// deletes the source events. Password protected sources are refused, their
+// photos would lose their password; remove it first to merge them.
+func (s *GalleryService) MergeEvents(target int64, sources []int64) (Event, error) {
+	if len(sources) == 0 {
+		return Event{}, fmt.Errorf("%w: no events to merge", ErrInvalidEvent)
+	}
+	if slices.Contains(sources, target) {
+		return Event{}, fmt.Errorf("%w: cannot merge an event into itself", ErrInvalidEvent)
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	hashes, err := s.store.EventPasswordHashes()
+	if err != nil {
+		return Event{}, err
+	}
+	for _, source := range sources {
+		if hash := hashes[source]; hash != "" && hash != hashes[target] {
+			event, err := s.store.GetEvent(source)
+			if err != nil {
+				return Event{}, err
+			}
+			return Event{}, fmt.Errorf("%w: %s is password protected, remove its password before merging", ErrInvalidEvent, event.Name)
+		}
+	}
+
+	if err := s.store.MergeEvents(target, sources); err != nil {
+		return Event{}, err
+	}
+
+	event, err := s.store.GetEvent(target)
+	if err != nil {
+		return Event{}, err
+	}
+	if err := s.reindexEvent(event.Name); err != nil {
+		return Event{}, err
+	}
+	return s.GetEvent(target)
+}
+
+// reindexEvent reloads the photos of an event into the index after their
+// event name was rewritten in the store.
+func (s *GalleryService) reindexEvent(name string) error {
+	photos, err := s.store.ListPhotos(PhotoFilter{Event: name})
+	if err != nil {
+		return err
+	}
+	for _, photo := range photos {
+		s.index.put(photo)
+	}
+	return nil
+}
+
+// completeEvents fills in the photo count and, unless set explicitly, the
+// date range and cover photo of events from the photos in the index.
+func (s *GalleryService) completeEvents(events []Event) {
+	byName := make(map[string]*Event, len(events))
+	for i := range events {
+		byName[events[i].Name] = &events[i]
+	}
+
+	starts, ends := make(map[string]time.Time), make(map[string]time.Time)
+	// The index is sorted newest first, so the first photo seen is the fallback cover
+	for _, photo := range s.index.list(PhotoFilter{}) {
+		event, ok := byName[photo.Event]
+		if !ok {
+			continue
+		}
+		event.PhotoCount++
+		if event.CoverPhoto == "" {
+			event.CoverPhoto = photo.Name
+		}
+
+		taken := photo.PhotoTime
+		if taken.IsZero() {
+			taken = photo.Date
+		}
+		if start, ok := starts[event.Name]; !ok || taken.Before(start) {
+			starts[event.Name] = taken
+		}
+		if end, ok := ends[event.Name]; !ok || taken.After(end) {
+			ends[event.Name] = taken
+		}
+	}
+
+	for i := range events {
+		if events[i].StartDate.IsZero() {
+			events[i].StartDate = starts[events[i].Name]
+		}
+		if events[i].EndDate.IsZero() {
+			events[i].EndDate = ends[events[i].Name]
+		}
+	}
+}
+
+// normalizeEventUpdate trims the new values and rejects invalid ones.
+func normalizeEventUpdate(update EventUpdate) (EventUpdate, error) {
+	trim := func(value *string) *string {
+		if value == nil {
+			return nil
+		}
+		trimmed := strings.TrimSpace(*value)
+		return &trimmed
+	}
+
+	update.Name = trim(update.Name)
+	update.Description = trim(update.Description)
+	update.CoverPhoto = trim(update.CoverPhoto)
+	if update.Name != nil && *update.Name == "" {
+		return EventUpdate{}, fmt.Errorf("%w: name must not be empty", ErrInvalidEvent)
+	}
+	return update, nil
+}
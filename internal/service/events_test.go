@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	uploads := []struct{ filename, event string }{
+		{"a.png", "Weding"},
+		{"b.png", "Wedding"},
+		{"c.png", "Wedding"},
+	}
+	for i, upload := range uploads {
+		data := encodeTestImage(t, createPatternImage(20+i, 20, i%2 == 0), "png")
+		if _, err := service.SavePhoto(newTestFileHeader(t, upload.filename, "image/png", data), User{Username: "Alice"}, upload.event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := service.ListEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", events)
+	}
+	wedding, typo := events[0], events[1]
+	if wedding.PhotoCount != 2 || wedding.CoverPhoto != "c.png" || wedding.StartDate.IsZero() || wedding.EndDate.Before(wedding.StartDate) {
+		t.Errorf("Expected count, cover and date range derived from the photos, got %+v", wedding)
+	}
+
+	cover := "a.png"
+	if _, err := service.UpdateEvent(wedding.ID, EventUpdate{CoverPhoto: &cover}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("Expected ErrInvalidEvent for a cover from another event, got %v", err)
+	}
+	start, end := time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := service.UpdateEvent(wedding.ID, EventUpdate{StartDate: &start, EndDate: &end}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("Expected ErrInvalidEvent for an end before the start, got %v", err)
+	}
+
+	merged, err := service.MergeEvents(wedding.ID, []int64{typo.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.PhotoCount != 3 {
+		t.Errorf("Expected 3 photos after merge, got %d", merged.PhotoCount)
+	}
+
+	name := "Our Wedding"
+	renamed, err := service.UpdateEvent(wedding.ID, EventUpdate{Name: &name, CoverPhoto: &cover})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Name != "Our Wedding" || renamed.CoverPhoto != "a.png" {
+		t.Errorf("Unexpected event after rename: %+v", renamed)
+	}
+
+	photos, err := service.QueryPhotos(PhotoFilter{Event: "Our Wedding"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 3 {
+		t.Errorf("Expected the index to follow the rename, got %d photos", len(photos))
+	}
+	eventNames, err := service.GetEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eventNames) != 1 || eventNames[0] != "Our Wedding" {
+		t.Errorf("Expected [Our Wedding], got %v", eventNames)
+	}
+}
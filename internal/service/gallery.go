@@ -2,7 +2,10 @@ package service
 
 import (
 	"archive/zip"
-	"encoding/json"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"image"
 	"io"
@@ -11,7 +14,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"image/gif"
@@ -23,21 +28,46 @@ import (
 
 	"github.com/rwcarlsen/goexif/exif"
 	"github.com/rwcarlsen/goexif/tiff"
+	_ "golang.org/x/image/webp" // Register WebP format
 )
 
 const (
-	filePermissions  = 0600 // File permissions for metadata files
 	thumbnailSize    = 300  // Thumbnail max width/height in pixels
-	thumbnailQuality = 80   // JPEG quality for thumbnails (0-100)
+	thumbnailQuality = 80   // JPEG quality for thumbnails and renditions (0-100)
+	maxCaptionLength = 2000 // Maximum caption length in bytes
 )
 
+// ErrInvalidMetadata is returned for metadata updates with invalid values.
+var ErrInvalidMetadata = errors.New("invalid photo metadata")
+
 type PhotoInfo struct {
+	ID        int64     `json:"id"`
 	Path      string    `json:"path"`
 	Name      string    `json:"name"`
 	Uploader  string    `json:"uploader"`
 	Event     string    `json:"event"`
-	Date      time.Time `json:"date"`      // Upload/file modification time
+	Caption   string    `json:"caption"`
+	Date      time.Time `json:"date"`       // Upload/file modification time
 	PhotoTime time.Time `json:"photo_time"` // Actual photo taken time from EXIF
+	Hash      string    `json:"hash"`       // Hex SHA-256 of the original file
+	// PerceptualHash is the dHash used to find near-duplicates, 0 if unknown
+	PerceptualHash uint64 `json:"perceptual_hash,omitempty"`
+	// OwnerID is the ID of the user account that uploaded the photo, 0 if unknown
+	OwnerID int64 `json:"owner_id,omitempty"`
+	// DeletedAt is set for photos in the trash
+	DeletedAt time.Time `json:"deleted_at,omitzero"`
+	// Contributors are further uploaders who uploaded an identical copy
+	Contributors []string `json:"contributors,omitempty"`
+	// Tags are free-form labels in canonical form, see NormalizeTag
+	Tags []string `json:"tags,omitempty"`
+}
+
+// SaveResult describes the outcome of SavePhoto.
+type SaveResult struct {
+	Photo PhotoInfo
+	// Deduplicated is set when the upload was identical to an existing photo
+	// and was recorded as a contribution to it instead of being stored again.
+	Deduplicated bool
 }
 
 // dateWalker implements exif.Walker to find date fields in EXIF data
@@ -85,145 +115,287 @@ func (w *dateWalker) Walk(name exif.FieldName, tag *tiff.Tag) error {
 }
 
 type GalleryService struct {
-	uploadDir    string
-	metadataDir  string
-	thumbnailDir string
+	uploadDir   string
+	metadataDir string
+	store       MetadataStore
+	photos      BlobStore // Original uploads
+	thumbnails  BlobStore
+	// JPEG versions of photos in formats that need conversion, e.g. HEIC,
+	// and rotated display copies
+	derivatives BlobStore
+	converter   ImageConverter
+	resampling  Resampling // Filter for scaling thumbnails
+	// rotatedCopies enables physically rotated display copies of photos
+	// with an EXIF orientation
+	rotatedCopies bool
+	// Scaled-down versions for larger screens, by size; see ServeRendition
+	renditions     map[int]BlobStore
+	renditionSizes []int
+	// Originals and thumbnails of deleted photos until they are purged
+	trashPhotos     BlobStore
+	trashThumbnails BlobStore
+	index           *photoIndex
+	watcher         *uploadWatcher
+	purger          *trashPurger
+	trashRetention  time.Duration
+	// saveMu serializes duplicate detection and filename reservation of
+	// uploads with other changes to photos. Uploads being stored are tracked
+	// by filename and by content, so two identical files uploaded at once are
+	// not both stored.
+	saveMu       sync.Mutex
+	savingNames  map[string]bool
+	savingHashes map[string]chan struct{}
+}
+
+// GalleryOption customizes a GalleryService created by NewGalleryService.
+type GalleryOption func(*GalleryService)
+
+// WithBlobStores stores originals and thumbnails in the given blob stores
+// instead of the upload directory and METADATA_DIR/thumbnails.
+func WithBlobStores(photos, thumbnails BlobStore) GalleryOption {
+	return func(s *GalleryService) {
+		s.photos = photos
+		s.thumbnails = thumbnails
+	}
+}
+
+// WithDerivativeStore stores the JPEG derivatives of converted photos in the
+// given blob store instead of METADATA_DIR/derivatives.
+func WithDerivativeStore(derivatives BlobStore) GalleryOption {
+	return func(s *GalleryService) {
+		s.derivatives = derivatives
+	}
 }
 
-func NewGalleryService(uploadDir, metadataDir string) *GalleryService {
-	thumbnailDir := filepath.Join(metadataDir, "thumbnails")
+// WithTrashStores keeps originals and thumbnails of deleted photos in the
+// given blob stores instead of a .trash directory next to the live ones.
+func WithTrashStores(photos, thumbnails BlobStore) GalleryOption {
+	return func(s *GalleryService) {
+		s.trashPhotos = photos
+		s.trashThumbnails = thumbnails
+	}
+}
 
+func NewGalleryService(uploadDir, metadataDir string, opts ...GalleryOption) (*GalleryService, error) {
 	service := &GalleryService{
 		uploadDir:    uploadDir,
 		metadataDir:  metadataDir,
-		thumbnailDir: thumbnailDir,
+		index:        newPhotoIndex(),
+		resampling:   ResampleCatmullRom,
+		savingNames:  make(map[string]bool),
+		savingHashes: make(map[string]chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	// Default to keeping blobs on the local filesystem
+	if service.photos == nil {
+		photos, err := NewFileBlobStore(uploadDir)
+		if err != nil {
+			return nil, err
+		}
+		service.photos = photos
+	}
+	if service.thumbnails == nil {
+		thumbnails, err := NewFileBlobStore(filepath.Join(metadataDir, "thumbnails"))
+		if err != nil {
+			return nil, err
+		}
+		service.thumbnails = thumbnails
+	}
+	if service.derivatives == nil {
+		derivatives, err := NewFileBlobStore(filepath.Join(metadataDir, "derivatives"))
+		if err != nil {
+			return nil, err
+		}
+		service.derivatives = derivatives
+	}
+	if err := service.initRenditions(); err != nil {
+		return nil, err
+	}
+	if service.converter == nil {
+		if heifConvert := FindHeifConvert(); heifConvert != nil {
+			service.converter = heifConvert
+		} else {
+			log.Printf("heif-convert not found, HEIC and HEIF photos get no JPEG version and thumbnail")
+		}
+	}
+	// Dot-directories are skipped when listing, and on the same filesystem
+	// moving a photo to the trash is a rename
+	if service.trashPhotos == nil {
+		trashPhotos, err := NewFileBlobStore(filepath.Join(uploadDir, ".trash"))
+		if err != nil {
+			return nil, err
+		}
+		service.trashPhotos = trashPhotos
+	}
+	if service.trashThumbnails == nil {
+		trashThumbnails, err := NewFileBlobStore(filepath.Join(metadataDir, "thumbnails", ".trash"))
+		if err != nil {
+			return nil, err
+		}
+		service.trashThumbnails = trashThumbnails
+	}
+
+	store, err := OpenSQLiteStore(metadataDir)
+	if err != nil {
+		return nil, err
+	}
+	service.store = store
+
+	// Import the per-photo JSON sidecars used by earlier versions
+	importedCount, err := store.ImportSidecars(metadataDir)
+	if err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+	if importedCount > 0 {
+		log.Printf("Imported %d metadata sidecar files into the metadata database", importedCount)
 	}
 
 	// Generate metadata and thumbnails for existing images on startup
 	service.GenerateMissingMetadata()
+	service.GenerateMissingHashes()
 	service.GenerateMissingThumbnails()
+	service.GenerateMissingRenditions()
+	service.GenerateMissingRotatedCopies()
+	service.GenerateMissingPerceptualHashes()
 
 	// Clean up orphaned files on startup
 	service.CleanupOrphanedMetadata()
 	service.CleanupOrphanedThumbnails()
+	service.CleanupOrphanedDerivatives()
+	service.CleanupOrphanedRenditions()
 
-	return service
-}
+	// Build the in-memory photo index used to serve the gallery
+	if err := service.RebuildIndex(); err != nil {
+		_ = store.Close()
+		return nil, err
+	}
 
-func (s *GalleryService) GetPhotos() ([]PhotoInfo, error) {
-	var photos []PhotoInfo
+	return service, nil
+}
 
-	files, err := os.ReadDir(s.uploadDir)
-	if err != nil {
-		return photos, err
+// Close stops the background tasks and releases the metadata store.
+func (s *GalleryService) Close() error {
+	if s.purger != nil {
+		s.purger.close()
+		s.purger = nil
 	}
-
-	for _, file := range files {
-		if !file.IsDir() && s.isImageFile(file.Name()) {
-			photoInfo := s.loadPhotoMetadata(file.Name())
-			if photoInfo.Path == "" {
-				// Fallback for photos without metadata - extract photo time
-				filePath := filepath.Join(s.uploadDir, file.Name())
-				photoTime := s.extractPhotoTime(filePath)
-
-				photoInfo = PhotoInfo{
-					Path:      "/uploads/" + file.Name(),
-					Name:      file.Name(),
-					Uploader:  "Unknown",
-					Event:     "",
-					Date:      time.Now(),
-					PhotoTime: photoTime,
-				}
-			}
-			photos = append(photos, photoInfo)
+	if s.watcher != nil {
+		if err := s.watcher.close(); err != nil {
+			log.Printf("Failed to close upload directory watcher: %v", err)
 		}
+		s.watcher = nil
 	}
+	return s.store.Close()
+}
 
-	// Sort photos by photo taken time (newest first), fall back to upload time if no photo time
-	for i := 0; i < len(photos)-1; i++ {
-		for j := i + 1; j < len(photos); j++ {
-			timeI := photos[i].PhotoTime
-			timeJ := photos[j].PhotoTime
+// RebuildIndex reloads the in-memory photo index from the metadata store.
+func (s *GalleryService) RebuildIndex() error {
+	photos, err := s.store.ListPhotos(PhotoFilter{})
+	if err != nil {
+		return fmt.Errorf("failed to load photo index: %w", err)
+	}
+	s.index.replace(photos)
+	return nil
+}
 
-			// Use upload time if photo time is not available
-			if timeI.IsZero() {
-				timeI = photos[i].Date
-			}
-			if timeJ.IsZero() {
-				timeJ = photos[j].Date
-			}
+// GetPhotos returns all photos sorted by photo taken time (newest first),
+// falling back to upload time for photos without a photo time.
+func (s *GalleryService) GetPhotos() ([]PhotoInfo, error) {
+	return s.index.list(PhotoFilter{}), nil
+}
 
-			// Sort newest first
-			if timeI.Before(timeJ) {
-				photos[i], photos[j] = photos[j], photos[i]
-			}
-		}
+// QueryPhotos returns the photos matching filter, sorted like GetPhotos.
+func (s *GalleryService) QueryPhotos(filter PhotoFilter) ([]PhotoInfo, error) {
+	return s.index.list(filter.normalized()), nil
+}
+
+// GetPhoto returns a photo that is not in the trash or ErrPhotoNotFound.
+func (s *GalleryService) GetPhoto(filename string) (PhotoInfo, error) {
+	photo, ok := s.index.get(filename)
+	if !ok {
+		return PhotoInfo{}, fmt.Errorf("%s: %w", filename, ErrPhotoNotFound)
 	}
+	return photo, nil
+}
 
-	return photos, nil
+// CountPhotos returns the total number of photos in the gallery.
+func (s *GalleryService) CountPhotos() (int, error) {
+	return s.index.count(), nil
+}
+
+// GetEvents returns all event names in alphabetical order.
+func (s *GalleryService) GetEvents() ([]string, error) {
+	return s.index.uniqueValues(func(p PhotoInfo) string { return p.Event }), nil
+}
+
+// GetUploaders returns all uploader names in alphabetical order.
+func (s *GalleryService) GetUploaders() ([]string, error) {
+	return s.index.uniqueMultiValues(func(p PhotoInfo) []string {
+		return append([]string{p.Uploader}, p.Contributors...)
+	}), nil
 }
 
 func (s *GalleryService) FilterPhotos(photos []PhotoInfo, eventFilter, uploaderFilter string) []PhotoInfo {
 	var filtered []PhotoInfo
 
+	filter := PhotoFilter{Event: eventFilter, Uploader: uploaderFilter}
 	for _, photo := range photos {
-		// Check event filter
-		if eventFilter != "" && photo.Event != eventFilter {
-			continue
-		}
-
-		// Check uploader filter
-		if uploaderFilter != "" && photo.Uploader != uploaderFilter {
-			continue
+		if filter.Matches(photo) {
+			filtered = append(filtered, photo)
 		}
-
-		filtered = append(filtered, photo)
 	}
 
 	return filtered
 }
 
 // getUniqueValues is a helper function to extract unique non-empty values from photos
-func (s *GalleryService) getUniqueValues(photos []PhotoInfo, extractor func(PhotoInfo) string) []string {
+func (s *GalleryService) getUniqueValues(photos []PhotoInfo, extractor func(PhotoInfo) []string) []string {
 	valueSet := make(map[string]bool)
 	var values []string
 
 	for _, photo := range photos {
-		value := extractor(photo)
-		if value != "" && !valueSet[value] {
-			valueSet[value] = true
-			values = append(values, value)
-		}
-	}
-
-	// Sort values alphabetically using bubble sort
-	for i := 0; i < len(values)-1; i++ {
-		for j := i + 1; j < len(values); j++ {
-			if values[i] > values[j] {
-				values[i], values[j] = values[j], values[i]
+		for _, value := range extractor(photo) {
+			if value != "" && !valueSet[value] {
+				valueSet[value] = true
+				values = append(values, value)
 			}
 		}
 	}
 
+	slices.Sort(values)
+
 	return values
 }
 
 func (s *GalleryService) GetUniqueEvents(photos []PhotoInfo) []string {
-	return s.getUniqueValues(photos, func(p PhotoInfo) string { return p.Event })
+	return s.getUniqueValues(photos, func(p PhotoInfo) []string { return []string{p.Event} })
 }
 
+// GetUniqueUploaders returns the uploaders of photos, including contributors,
+// in alphabetical order.
 func (s *GalleryService) GetUniqueUploaders(photos []PhotoInfo) []string {
-	return s.getUniqueValues(photos, func(p PhotoInfo) string { return p.Uploader })
+	return s.getUniqueValues(photos, func(p PhotoInfo) []string {
+		return append([]string{p.Uploader}, p.Contributors...)
+	})
 }
 
-func (s *GalleryService) SavePhoto(fileHeader *multipart.FileHeader, userName, eventName string) error {
+// SavePhoto stores a photo uploaded by uploader, who becomes its owner. An
+// upload identical to an existing photo of the same event is not stored
+// again; the uploader is attributed to the existing photo instead. Copies in
+// other events are stored separately, so uploads never reveal or join photos
+// of events the uploader may not see.
+func (s *GalleryService) SavePhoto(fileHeader *multipart.FileHeader, uploader User, eventName string) (SaveResult, error) {
 	if !s.isValidImageType(fileHeader.Header.Get("Content-Type")) {
-		return fmt.Errorf("invalid image type")
+		return SaveResult{}, fmt.Errorf("invalid image type")
 	}
 
 	file, err := fileHeader.Open()
 	if err != nil {
-		return err
+		return SaveResult{}, err
 	}
 	defer func() {
 		if closeErr := file.Close(); closeErr != nil {
@@ -231,51 +403,256 @@ func (s *GalleryService) SavePhoto(fileHeader *multipart.FileHeader, userName, e
 		}
 	}()
 
-	// Generate unique filename preserving original name
-	filename := s.generateUniqueFilename(fileHeader.Filename)
-	filePath := filepath.Join(s.uploadDir, filename)
-
-	// #nosec G304 - filePath is constructed from controlled uploadDir and sanitized filename
-	dst, err := os.Create(filePath)
+	// Keep a local copy while processing, the blob store may be remote
+	tmp, err := os.CreateTemp("", "upload-*"+filepath.Ext(fileHeader.Filename))
 	if err != nil {
-		return err
+		return SaveResult{}, err
 	}
 	defer func() {
-		if closeErr := dst.Close(); closeErr != nil {
-			log.Printf("Failed to close destination file: %v", closeErr)
+		if err := os.Remove(tmp.Name()); err != nil {
+			log.Printf("Failed to remove temporary file %s: %v", tmp.Name(), err)
+		}
+	}()
+	defer func() {
+		if closeErr := tmp.Close(); closeErr != nil && !errors.Is(closeErr, os.ErrClosed) {
+			log.Printf("Failed to close temporary file: %v", closeErr)
 		}
 	}()
 
-	_, err = io.Copy(dst, file)
+	// Hash the content while copying it
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), file)
 	if err != nil {
-		return err
+		return SaveResult{}, err
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	filename, result, err := s.reserveUpload(hash, eventName, fileHeader.Filename, uploader.Username)
+	if err != nil || result.Deduplicated {
+		return result, err
+	}
+	defer s.releaseUpload(hash, eventName, filename)
+
+	// Don't leave blobs behind for an upload without metadata
+	stored := false
+	defer func() {
+		if !stored {
+			s.deleteUploadBlobs(filename)
+		}
+	}()
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return SaveResult{}, err
+	}
+	if err := s.photos.Put(filename, tmp, size); err != nil {
+		return SaveResult{}, err
 	}
 
-	// Generate thumbnail
-	thumbnailPath := filepath.Join(s.thumbnailDir, filename)
-	if err := s.generateThumbnail(filePath, thumbnailPath); err != nil {
+	// Generate thumbnail and perceptual hash; formats that need conversion
+	// get a JPEG version first
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return SaveResult{}, err
+	}
+	var perceptualHash uint64
+	if needsConversion(filename) {
+		perceptualHash, err = s.generateConvertedThumbnail(tmp.Name(), filename)
+	} else {
+		perceptualHash, err = s.generateThumbnail(tmp, filename)
+	}
+	if err != nil {
 		log.Printf("Failed to generate thumbnail for %s: %v", filename, err)
 		// Don't fail the upload if thumbnail generation fails
 	}
 
 	// Extract photo taken time from EXIF
-	photoTime := s.extractPhotoTime(filePath)
+	photoTime := s.extractPhotoTime(tmp.Name())
 
 	// Save photo metadata
 	photoInfo := PhotoInfo{
-		Path:      "/uploads/" + filename,
-		Name:      filename,
-		Uploader:  userName,
-		Event:     eventName,
-		Date:      time.Now(),
-		PhotoTime: photoTime,
+		Path:           "/uploads/" + filename,
+		Name:           filename,
+		Uploader:       uploader.Username,
+		Event:          eventName,
+		Date:           time.Now(),
+		PhotoTime:      photoTime,
+		Hash:           hash,
+		PerceptualHash: perceptualHash,
+		OwnerID:        uploader.ID,
+	}
+	if err := s.store.SavePhoto(&photoInfo); err != nil {
+		return SaveResult{}, err
 	}
-	s.savePhotoMetadata(filename, &photoInfo)
+	stored = true
+	s.index.put(photoInfo)
 
-	return nil
+	return SaveResult{Photo: photoInfo}, nil
+}
+
+// reserveUpload attributes an upload to an identical photo of the same event
+// or reserves a unique filename, preserving the original name, for storing it
+// as a new photo. Identical uploads to the same event wait for the one being
+// stored and are then attributed to it.
+func (s *GalleryService) reserveUpload(hash, eventName, originalFilename, uploader string) (string, SaveResult, error) {
+	key := savingKey(hash, eventName)
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	for {
+		saving, ok := s.savingHashes[key]
+		if !ok {
+			break
+		}
+		s.saveMu.Unlock()
+		<-saving
+		s.saveMu.Lock()
+	}
+
+	existing, err := s.store.FindPhotoByHash(hash, eventName)
+	if err == nil {
+		result, err := s.addContribution(existing, uploader)
+		return "", result, err
+	}
+	if !errors.Is(err, ErrPhotoNotFound) {
+		return "", SaveResult{}, err
+	}
+
+	filename := s.generateUniqueFilename(originalFilename)
+	s.savingNames[filename] = true
+	s.savingHashes[key] = make(chan struct{})
+	return filename, SaveResult{}, nil
+}
+
+// releaseUpload ends the reservation of reserveUpload once the upload is
+// stored or has failed, and wakes identical uploads waiting for it.
+func (s *GalleryService) releaseUpload(hash, eventName, filename string) {
+	key := savingKey(hash, eventName)
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	delete(s.savingNames, filename)
+	close(s.savingHashes[key])
+	delete(s.savingHashes, key)
+}
+
+// savingKey identifies uploads that are deduplicated against each other.
+func savingKey(hash, eventName string) string {
+	return hash + "\x00" + eventName
 }
 
-func (s *GalleryService) CreateZipArchive(photos []PhotoInfo, writer io.Writer) error {
+// deleteUploadBlobs removes the original and everything generated from it
+// for an upload whose metadata could not be saved.
+func (s *GalleryService) deleteUploadBlobs(filename string) {
+	if err := s.photos.Delete(filename); err != nil {
+		log.Printf("Failed to remove %s: %v", filename, err)
+	}
+	if err := s.thumbnails.Delete(filename); err != nil {
+		log.Printf("Failed to remove thumbnail for %s: %v", filename, err)
+	}
+	if err := s.derivatives.Delete(filename); err != nil {
+		log.Printf("Failed to remove JPEG version of %s: %v", filename, err)
+	}
+	s.deleteRenditions(filename)
+}
+
+// addContribution records userName as a further uploader of an existing photo.
+func (s *GalleryService) addContribution(photo PhotoInfo, userName string) (SaveResult, error) {
+	if err := s.store.AddContributor(photo.Name, userName); err != nil {
+		return SaveResult{}, err
+	}
+
+	photo, err := s.store.GetPhoto(photo.Name)
+	if err != nil {
+		return SaveResult{}, err
+	}
+	s.index.put(photo)
+
+	log.Printf("Upload by %s is a duplicate of %s", userName, photo.Name)
+	return SaveResult{Photo: photo, Deduplicated: true}, nil
+}
+
+// UpdatePhotoMetadata changes the event, uploader or caption of a photo.
+func (s *GalleryService) UpdatePhotoMetadata(filename string, update PhotoUpdate) (PhotoInfo, error) {
+	photos, err := s.UpdatePhotosMetadata([]string{filename}, update)
+	if err != nil {
+		return PhotoInfo{}, err
+	}
+	return photos[0], nil
+}
+
+// UpdatePhotosMetadata applies the same change to several photos at once,
+// e.g. to move a selection to a corrected event name. Either all photos are
+// updated or, if one of them does not exist, none.
+func (s *GalleryService) UpdatePhotosMetadata(filenames []string, update PhotoUpdate) ([]PhotoInfo, error) {
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("%w: no photos selected", ErrInvalidMetadata)
+	}
+	update, err := normalizePhotoUpdate(update)
+	if err != nil {
+		return nil, err
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	if err := s.store.UpdatePhotos(filenames, update); err != nil {
+		return nil, err
+	}
+
+	photos := make([]PhotoInfo, 0, len(filenames))
+	for _, filename := range filenames {
+		photo, err := s.store.GetPhoto(filename)
+		if err != nil {
+			return nil, err
+		}
+		s.index.put(photo)
+		photos = append(photos, photo)
+	}
+	return photos, nil
+}
+
+// normalizePhotoUpdate trims the new values and rejects invalid ones.
+func normalizePhotoUpdate(update PhotoUpdate) (PhotoUpdate, error) {
+	trim := func(value *string) *string {
+		if value == nil {
+			return nil
+		}
+		trimmed := strings.TrimSpace(*value)
+		return &trimmed
+	}
+
+	update.Event = trim(update.Event)
+	update.Uploader = trim(update.Uploader)
+	update.Caption = trim(update.Caption)
+	if update.Uploader != nil && *update.Uploader == "" {
+		return PhotoUpdate{}, fmt.Errorf("%w: uploader must not be empty", ErrInvalidMetadata)
+	}
+	if update.Caption != nil && len(*update.Caption) > maxCaptionLength {
+		return PhotoUpdate{}, fmt.Errorf("%w: caption is longer than %d bytes", ErrInvalidMetadata, maxCaptionLength)
+	}
+
+	var err error
+	if update.Tags != nil {
+		tags, err := normalizeTags(*update.Tags)
+		if err != nil {
+			return PhotoUpdate{}, err
+		}
+		update.Tags = &tags
+	}
+	if update.AddTags, err = normalizeTags(update.AddTags); err != nil {
+		return PhotoUpdate{}, err
+	}
+	if update.RemoveTags, err = normalizeTags(update.RemoveTags); err != nil {
+		return PhotoUpdate{}, err
+	}
+	return update, nil
+}
+
+// CreateZipArchive writes the photos to writer as ZIP archive. With
+// PhotoFormatJPEG converted photos are archived as their JPEG version if
+// they have one.
+func (s *GalleryService) CreateZipArchive(photos []PhotoInfo, format PhotoFormat, writer io.Writer) error {
 	zipWriter := zip.NewWriter(writer)
 	defer func() {
 		if err := zipWriter.Close(); err != nil {
@@ -283,18 +660,37 @@ func (s *GalleryService) CreateZipArchive(photos []PhotoInfo, writer io.Writer)
 		}
 	}()
 
+	// JPEG versions are renamed, which may clash with another photo
+	names := make(map[string]bool, len(photos))
+	for _, photo := range photos {
+		names[filepath.Base(photo.Path)] = true
+	}
+
 	for _, photo := range photos {
 		filename := filepath.Base(photo.Path)
-		filePath := filepath.Join(s.uploadDir, filename)
 
-		// #nosec G304 - filePath is constructed from controlled uploadDir and photo.Name
-		fileReader, err := os.Open(filePath)
-		if err != nil {
-			log.Printf("Failed to open file %s: %v", filename, err)
-			continue
+		entryName := filename
+		var fileReader io.ReadSeekCloser
+		if format == PhotoFormatJPEG && needsConversion(filename) {
+			// Photos that could not be converted are archived as original
+			if derivative, _, err := s.derivatives.Open(filename); err == nil {
+				fileReader, entryName = derivative, jpegName(filename)
+				if names[entryName] {
+					entryName = filename + ".jpg"
+				}
+				names[entryName] = true
+			}
+		}
+		if fileReader == nil {
+			original, _, err := s.photos.Open(filename)
+			if err != nil {
+				log.Printf("Failed to open file %s: %v", filename, err)
+				continue
+			}
+			fileReader = original
 		}
 
-		zipFile, err := zipWriter.Create(filename)
+		zipFile, err := zipWriter.Create(entryName)
 		if err != nil {
 			log.Printf("Failed to create zip entry for %s: %v", filename, err)
 			if closeErr := fileReader.Close(); closeErr != nil {
@@ -316,174 +712,296 @@ func (s *GalleryService) CreateZipArchive(photos []PhotoInfo, writer io.Writer)
 	return nil
 }
 
-func (s *GalleryService) ServePhoto(filename string) (string, error) {
-	filePath := filepath.Join(s.uploadDir, filename)
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return "", fmt.Errorf("file not found")
-	}
-	return filePath, nil
+// ServePhoto opens an original photo. The caller must close the reader.
+func (s *GalleryService) ServePhoto(filename string) (io.ReadSeekCloser, BlobInfo, error) {
+	return s.photos.Open(filename)
 }
 
 func (s *GalleryService) CleanupOrphanedMetadata() {
-	metadataFiles, err := os.ReadDir(s.metadataDir)
+	photos, err := s.store.ListPhotos(PhotoFilter{})
 	if err != nil {
-		log.Printf("Failed to read metadata directory: %v", err)
+		log.Printf("Failed to list photo metadata: %v", err)
 		return
 	}
 
-	removedCount := 0
-	for _, metadataFile := range metadataFiles {
-		if metadataFile.IsDir() || !strings.HasSuffix(metadataFile.Name(), ".json") {
-			continue
-		}
-
-		imageFilename := strings.TrimSuffix(metadataFile.Name(), ".json")
-		imagePath := filepath.Join(s.uploadDir, imageFilename)
+	originals, err := s.blobNames(s.photos)
+	if err != nil {
+		log.Printf("Failed to list photos: %v", err)
+		return
+	}
 
-		if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-			metadataPath := filepath.Join(s.metadataDir, metadataFile.Name())
-			if err := os.Remove(metadataPath); err != nil {
-				log.Printf("Failed to remove orphaned metadata file %s: %v", metadataFile.Name(), err)
+	removedCount := 0
+	for _, photo := range photos {
+		if !originals[photo.Name] {
+			if err := s.store.DeletePhoto(photo.Name); err != nil {
+				log.Printf("Failed to remove orphaned metadata for %s: %v", photo.Name, err)
 			} else {
-				log.Printf("Removed orphaned metadata file: %s", metadataFile.Name())
+				log.Printf("Removed orphaned metadata for: %s", photo.Name)
+				s.index.remove(photo.Name)
 				removedCount++
 			}
 		}
 	}
 
 	if removedCount > 0 {
-		log.Printf("Cleanup complete: removed %d orphaned metadata files", removedCount)
+		log.Printf("Cleanup complete: removed %d orphaned metadata entries", removedCount)
 	}
 }
 
 func (s *GalleryService) CleanupOrphanedThumbnails() {
-	thumbnailFiles, err := os.ReadDir(s.thumbnailDir)
+	s.cleanupOrphanedBlobs(s.thumbnails, "thumbnail")
+}
+
+// CleanupOrphanedDerivatives removes the JPEG versions of converted photos
+// whose original is gone.
+func (s *GalleryService) CleanupOrphanedDerivatives() {
+	s.cleanupOrphanedBlobs(s.derivatives, "JPEG version")
+}
+
+// cleanupOrphanedBlobs removes the blobs derived from photos, named kind in
+// log messages, whose original is gone.
+func (s *GalleryService) cleanupOrphanedBlobs(store BlobStore, kind string) {
+	blobs, err := store.List()
+	if err != nil {
+		log.Printf("Failed to list %s files: %v", kind, err)
+		return
+	}
+
+	originals, err := s.blobNames(s.photos)
 	if err != nil {
-		log.Printf("Failed to read thumbnail directory: %v", err)
+		log.Printf("Failed to list photos: %v", err)
 		return
 	}
 
 	removedCount := 0
-	for _, thumbnailFile := range thumbnailFiles {
-		if thumbnailFile.IsDir() || !s.isImageFile(thumbnailFile.Name()) {
+	for _, blob := range blobs {
+		if !s.isImageFile(blob.Name) {
 			continue
 		}
 
 		// Check if corresponding original image exists
-		originalImagePath := filepath.Join(s.uploadDir, thumbnailFile.Name())
-		if _, err := os.Stat(originalImagePath); os.IsNotExist(err) {
-			thumbnailPath := filepath.Join(s.thumbnailDir, thumbnailFile.Name())
-			if err := os.Remove(thumbnailPath); err != nil {
-				log.Printf("Failed to remove orphaned thumbnail file %s: %v", thumbnailFile.Name(), err)
+		if !originals[blob.Name] {
+			if err := store.Delete(blob.Name); err != nil {
+				log.Printf("Failed to remove orphaned %s file %s: %v", kind, blob.Name, err)
 			} else {
-				log.Printf("Removed orphaned thumbnail file: %s", thumbnailFile.Name())
+				log.Printf("Removed orphaned %s file: %s", kind, blob.Name)
 				removedCount++
 			}
 		}
 	}
 
 	if removedCount > 0 {
-		log.Printf("Thumbnail cleanup complete: removed %d orphaned thumbnail files", removedCount)
+		log.Printf("Cleanup complete: removed %d orphaned %s files", removedCount, kind)
 	}
 }
 
 func (s *GalleryService) GenerateMissingMetadata() {
-	// Ensure directories exist
-	if err := os.MkdirAll(s.uploadDir, 0755); err != nil {
-		log.Printf("Failed to create upload directory: %v", err)
-		return
-	}
-	if err := os.MkdirAll(s.metadataDir, 0755); err != nil {
-		log.Printf("Failed to create metadata directory: %v", err)
-		return
-	}
-
-	files, err := os.ReadDir(s.uploadDir)
+	files, err := s.photos.List()
 	if err != nil {
-		log.Printf("Failed to read upload directory: %v", err)
+		log.Printf("Failed to list photos: %v", err)
 		return
 	}
 
 	generatedCount := 0
 	for _, file := range files {
-		if file.IsDir() || !s.isImageFile(file.Name()) {
+		if !s.isImageFile(file.Name) {
 			continue
 		}
 
 		// Check if metadata already exists
-		metadataFile := filepath.Join(s.metadataDir, file.Name()+".json")
-		if _, err := os.Stat(metadataFile); err == nil {
+		if _, err := s.store.GetPhoto(file.Name); err == nil {
 			continue // Metadata already exists
-		}
-
-		// Get file info for creation date
-		fileInfo, err := file.Info()
-		if err != nil {
-			log.Printf("Failed to get file info for %s: %v", file.Name(), err)
+		} else if !errors.Is(err, ErrPhotoNotFound) {
+			log.Printf("Failed to look up metadata for %s: %v", file.Name, err)
 			continue
 		}
 
-		// Extract photo taken time from EXIF
-		filePath := filepath.Join(s.uploadDir, file.Name())
-		photoTime := s.extractPhotoTime(filePath)
-
-		// Generate default metadata
-		photoInfo := PhotoInfo{
-			Path:      "/uploads/" + file.Name(),
-			Name:      file.Name(),
-			Uploader:  "Unknown",
-			Event:     "",
-			Date:      fileInfo.ModTime(),
-			PhotoTime: photoTime,
+		if _, err := s.generateMetadata(file.Name, file.ModTime); err != nil {
+			log.Printf("Failed to save metadata for %s: %v", file.Name, err)
+			continue
 		}
-
-		// Save the generated metadata
-		s.savePhotoMetadata(file.Name(), &photoInfo)
 		generatedCount++
-		log.Printf("Generated metadata for existing image: %s", file.Name())
+		log.Printf("Generated metadata for existing image: %s", file.Name)
 	}
 
 	if generatedCount > 0 {
-		log.Printf("Startup metadata generation complete: created %d metadata files", generatedCount)
+		log.Printf("Startup metadata generation complete: created %d metadata entries", generatedCount)
 	} else {
 		log.Printf("All existing images already have metadata")
 	}
 }
 
+// generateMetadata creates and saves default metadata for an image that was
+// placed in the upload directory without going through SavePhoto.
+func (s *GalleryService) generateMetadata(filename string, modTime time.Time) (PhotoInfo, error) {
+	// Extract photo taken time from EXIF
+	var photoTime time.Time
+	var hash string
+	err := withLocalFile(s.photos, filename, func(path string) error {
+		photoTime = s.extractPhotoTime(path)
+
+		var err error
+		hash, err = hashFile(path)
+		return err
+	})
+	if err != nil {
+		return PhotoInfo{}, err
+	}
+
+	// Generate default metadata
+	photoInfo := PhotoInfo{
+		Path:      "/uploads/" + filename,
+		Name:      filename,
+		Uploader:  "Unknown",
+		Event:     "",
+		Date:      modTime,
+		PhotoTime: photoTime,
+		Hash:      hash,
+	}
+
+	if err := s.store.SavePhoto(&photoInfo); err != nil {
+		return PhotoInfo{}, err
+	}
+	return photoInfo, nil
+}
+
+// syncUploadedFile brings metadata, thumbnail and index in line with the
+// current state of a single file in the upload directory. Files SavePhoto is
+// still storing and files that already have metadata are left alone.
+func (s *GalleryService) syncUploadedFile(filename string) {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	if s.savingNames[filename] {
+		return
+	}
+
+	fileInfo, err := s.photos.Stat(filename)
+	if errors.Is(err, ErrBlobNotFound) {
+		if _, ok := s.index.get(filename); !ok {
+			return
+		}
+		if err := s.store.DeletePhoto(filename); err != nil {
+			log.Printf("Failed to remove metadata for %s: %v", filename, err)
+			return
+		}
+		if err := s.thumbnails.Delete(filename); err != nil {
+			log.Printf("Failed to remove thumbnail for %s: %v", filename, err)
+		}
+		if err := s.derivatives.Delete(filename); err != nil {
+			log.Printf("Failed to remove JPEG version of %s: %v", filename, err)
+		}
+		s.deleteRenditions(filename)
+		s.index.remove(filename)
+		log.Printf("Removed deleted image from gallery: %s", filename)
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	if _, err := s.store.GetPhoto(filename); !errors.Is(err, ErrPhotoNotFound) {
+		if err != nil {
+			log.Printf("Failed to look up %s: %v", filename, err)
+		}
+		return // Saved by SavePhoto or indexed before
+	}
+	photoInfo, err := s.generateMetadata(filename, fileInfo.ModTime)
+	if err != nil {
+		log.Printf("Failed to index %s: %v", filename, err)
+		return
+	}
+	log.Printf("Added new image to gallery: %s", filename)
+
+	if _, err := s.thumbnails.Stat(filename); errors.Is(err, ErrBlobNotFound) {
+		if err := s.generateThumbnailFromPhoto(filename); err != nil {
+			log.Printf("Failed to generate thumbnail for %s: %v", filename, err)
+		} else if updated, err := s.store.GetPhoto(filename); err == nil {
+			photoInfo = updated // Picks up the perceptual hash
+		}
+	}
+
+	s.index.put(photoInfo)
+}
+
+// GenerateMissingHashes computes the content hash of photos stored before
+// duplicate detection existed.
+func (s *GalleryService) GenerateMissingHashes() {
+	photos, err := s.store.ListPhotos(PhotoFilter{})
+	if err != nil {
+		log.Printf("Failed to list photo metadata: %v", err)
+		return
+	}
+
+	hashedCount := 0
+	for _, photo := range photos {
+		if photo.Hash != "" {
+			continue
+		}
+
+		err := withLocalFile(s.photos, photo.Name, func(path string) error {
+			var err error
+			photo.Hash, err = hashFile(path)
+			return err
+		})
+		if err != nil {
+			log.Printf("Failed to hash %s: %v", photo.Name, err)
+			continue
+		}
+		if err := s.store.SavePhoto(&photo); err != nil {
+			log.Printf("Failed to save hash for %s: %v", photo.Name, err)
+			continue
+		}
+		hashedCount++
+	}
+
+	if hashedCount > 0 {
+		log.Printf("Computed content hashes for %d existing photos", hashedCount)
+	}
+}
+
 func (s *GalleryService) GenerateMissingThumbnails() {
-	// Ensure thumbnail directory exists
-	if err := os.MkdirAll(s.thumbnailDir, 0755); err != nil {
-		log.Printf("Failed to create thumbnail directory: %v", err)
+	files, err := s.photos.List()
+	if err != nil {
+		log.Printf("Failed to list photos for thumbnails: %v", err)
 		return
 	}
 
-	files, err := os.ReadDir(s.uploadDir)
+	existing, err := s.blobNames(s.thumbnails)
 	if err != nil {
-		log.Printf("Failed to read upload directory for thumbnails: %v", err)
+		log.Printf("Failed to list thumbnails: %v", err)
+		return
+	}
+	derivatives, err := s.blobNames(s.derivatives)
+	if err != nil {
+		log.Printf("Failed to list JPEG versions: %v", err)
 		return
 	}
 
 	generatedCount := 0
 	for _, file := range files {
-		if file.IsDir() || !s.isImageFile(file.Name()) {
+		if !s.isImageFile(file.Name) {
 			continue
 		}
 
-		// Check if thumbnail already exists
-		thumbnailPath := filepath.Join(s.thumbnailDir, file.Name())
-		if _, err := os.Stat(thumbnailPath); err == nil {
+		// Photos that need conversion also need their JPEG version, which
+		// is only created if a converter is available
+		if needsConversion(file.Name) {
+			if s.converter == nil || (existing[file.Name] && derivatives[file.Name]) {
+				continue
+			}
+		} else if existing[file.Name] {
 			continue // Thumbnail already exists
 		}
 
 		// Generate thumbnail
-		originalPath := filepath.Join(s.uploadDir, file.Name())
-		if err := s.generateThumbnail(originalPath, thumbnailPath); err != nil {
-			log.Printf("Failed to generate thumbnail for %s: %v", file.Name(), err)
+		if err := s.generateThumbnailFromPhoto(file.Name); err != nil {
+			log.Printf("Failed to generate thumbnail for %s: %v", file.Name, err)
 			continue
 		}
 
 		generatedCount++
-		log.Printf("Generated thumbnail for existing image: %s", file.Name())
+		log.Printf("Generated thumbnail for existing image: %s", file.Name)
 	}
 
 	if generatedCount > 0 {
@@ -493,95 +1011,118 @@ func (s *GalleryService) GenerateMissingThumbnails() {
 	}
 }
 
-func (s *GalleryService) generateThumbnail(originalPath, thumbnailPath string) error {
-	// Open original image
-	originalFile, err := os.Open(originalPath)
+// generateThumbnailFromPhoto creates the thumbnail for a stored original and
+// records its perceptual hash.
+func (s *GalleryService) generateThumbnailFromPhoto(filename string) error {
+	if needsConversion(filename) {
+		var perceptualHash uint64
+		err := withLocalFile(s.photos, filename, func(path string) error {
+			var err error
+			perceptualHash, err = s.generateConvertedThumbnail(path, filename)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return s.store.SetPerceptualHash(filename, perceptualHash)
+	}
+
+	original, _, err := s.photos.Open(filename)
 	if err != nil {
 		return fmt.Errorf("failed to open original image: %w", err)
 	}
-	defer originalFile.Close()
+	defer original.Close()
 
-	// Decode image
-	img, format, err := image.Decode(originalFile)
+	perceptualHash, err := s.generateThumbnail(original, filename)
 	if err != nil {
-		return fmt.Errorf("failed to decode image: %w", err)
+		return err
 	}
+	return s.store.SetPerceptualHash(filename, perceptualHash)
+}
 
-	// Calculate thumbnail dimensions maintaining aspect ratio
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
+// generateConvertedThumbnail converts the original of filename at path to
+// its JPEG version and creates the thumbnail from it. It returns the image's
+// perceptual hash.
+func (s *GalleryService) generateConvertedThumbnail(path, filename string) (uint64, error) {
+	derivative, err := s.createDerivative(path, filename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert image: %w", err)
+	}
+	return s.generateThumbnail(bytes.NewReader(derivative), filename)
+}
 
-	var newWidth, newHeight int
-	if width > height {
-		newWidth = thumbnailSize
-		newHeight = (height * thumbnailSize) / width
-	} else {
-		newHeight = thumbnailSize
-		newWidth = (width * thumbnailSize) / height
+// generateThumbnail decodes an original image from r, stores its thumbnail
+// and renditions under filename and returns the perceptual hash of the image
+// shown with its EXIF orientation.
+func (s *GalleryService) generateThumbnail(r io.ReadSeeker, filename string) (uint64, error) {
+	orientation := readOrientation(r)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, err
 	}
 
-	// Create thumbnail using simple nearest neighbor scaling
-	thumbnail := s.resizeImage(img, newWidth, newHeight)
+	// Decode image
+	img, format, err := image.Decode(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+	perceptualHash := dHash(orientedView(img, orientation))
 
-	// Create thumbnail file
-	thumbnailFile, err := os.Create(thumbnailPath)
+	// Scale to thumbnail size maintaining the displayed aspect ratio
+	width, height := fitWithin(orientedBounds(img.Bounds(), orientation), thumbnailSize)
+	scaled, _ := resizeOriented(img, width, height, orientation, s.resampling)
+	thumbnail, err := encodeImage(scaled, format)
 	if err != nil {
-		return fmt.Errorf("failed to create thumbnail file: %w", err)
+		return 0, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	if err := s.thumbnails.Put(filename, thumbnail, int64(thumbnail.Len())); err != nil {
+		return 0, fmt.Errorf("failed to store thumbnail: %w", err)
 	}
-	defer thumbnailFile.Close()
 
-	// Encode thumbnail based on original format
+	s.generateRenditions(img, format, orientation, filename)
+	return perceptualHash, nil
+}
+
+// fitWithin returns the size of an image with the given bounds scaled so its
+// longer side is size pixels. The shorter side is at least one pixel.
+func fitWithin(bounds image.Rectangle, size int) (int, int) {
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > height {
+		return size, max(1, (height*size)/width)
+	}
+	return max(1, (width*size)/height), size
+}
+
+// encodeImage encodes a scaled version of an image decoded from format in
+// the same format, or as JPEG for formats without encoder.
+func encodeImage(img image.Image, format string) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	var err error
 	switch format {
 	case "jpeg", "jpg":
-		err = jpeg.Encode(thumbnailFile, thumbnail, &jpeg.Options{Quality: thumbnailQuality})
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: thumbnailQuality})
 	case "png":
-		err = png.Encode(thumbnailFile, thumbnail)
+		err = png.Encode(&buf, img)
 	case "gif":
-		err = gif.Encode(thumbnailFile, thumbnail, nil)
+		err = gif.Encode(&buf, img, nil)
 	default:
-		// Default to JPEG for unknown formats
-		err = jpeg.Encode(thumbnailFile, thumbnail, &jpeg.Options{Quality: thumbnailQuality})
+		// Default to JPEG for formats without encoder, e.g. WebP
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: thumbnailQuality})
 	}
-
 	if err != nil {
-		return fmt.Errorf("failed to encode thumbnail: %w", err)
-	}
-
-	return nil
-}
-
-// Simple image resizing using nearest neighbor
-func (s *GalleryService) resizeImage(src image.Image, width, height int) image.Image {
-	srcBounds := src.Bounds()
-	srcWidth := srcBounds.Dx()
-	srcHeight := srcBounds.Dy()
-
-	dst := image.NewRGBA(image.Rect(0, 0, width, height))
-
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			srcX := (x * srcWidth) / width
-			srcY := (y * srcHeight) / height
-			dst.Set(x, y, src.At(srcBounds.Min.X+srcX, srcBounds.Min.Y+srcY))
-		}
+		return nil, err
 	}
-
-	return dst
+	return &buf, nil
 }
 
-func (s *GalleryService) ServeThumbnail(filename string) (string, error) {
-	thumbnailPath := filepath.Join(s.thumbnailDir, filename)
-	if _, err := os.Stat(thumbnailPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("thumbnail not found")
-	}
-	return thumbnailPath, nil
+// ServeThumbnail opens the thumbnail of a photo. The caller must close the reader.
+func (s *GalleryService) ServeThumbnail(filename string) (io.ReadSeekCloser, BlobInfo, error) {
+	return s.thumbnails.Open(filename)
 }
 
 // Private helper methods
 
 func (s *GalleryService) isValidImageType(contentType string) bool {
-	validTypes := []string{"image/jpeg", "image/jpg", "image/png", "image/gif", "image/webp"}
+	validTypes := []string{"image/jpeg", "image/jpg", "image/png", "image/gif", "image/webp", "image/heic", "image/heif"}
 	for _, validType := range validTypes {
 		if contentType == validType {
 			return true
@@ -592,7 +1133,7 @@ func (s *GalleryService) isValidImageType(contentType string) bool {
 
 func (s *GalleryService) isImageFile(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
-	validExts := []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
+	validExts := []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".heic", ".heif"}
 	for _, validExt := range validExts {
 		if ext == validExt {
 			return true
@@ -602,10 +1143,9 @@ func (s *GalleryService) isImageFile(filename string) bool {
 }
 
 func (s *GalleryService) generateUniqueFilename(originalFilename string) string {
-	originalFilename = filepath.Base(originalFilename)
+	originalFilename = photoFilename(originalFilename)
 
-	filePath := filepath.Join(s.uploadDir, originalFilename)
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	if !s.isNameTaken(originalFilename) {
 		return originalFilename
 	}
 
@@ -615,40 +1155,77 @@ func (s *GalleryService) generateUniqueFilename(originalFilename string) string
 	counter := 1
 	for {
 		newFilename := fmt.Sprintf("%s_%d%s", nameWithoutExt, counter, ext)
-		filePath := filepath.Join(s.uploadDir, newFilename)
-		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		if !s.isNameTaken(newFilename) {
 			return newFilename
 		}
 		counter++
 	}
 }
 
-func (s *GalleryService) savePhotoMetadata(filename string, info *PhotoInfo) {
-	metadataFile := filepath.Join(s.metadataDir, filename+".json")
-	data, err := json.Marshal(info)
-	if err != nil {
-		log.Printf("Failed to marshal metadata for %s: %v", filename, err)
-		return
+// photoFilename returns the base name of an uploaded file without leading
+// dots. Blob stores don't list names starting with a dot, they are reserved
+// for temporary files and the trash.
+func photoFilename(originalFilename string) string {
+	name := filepath.Base(originalFilename)
+	ext := filepath.Ext(name)
+	base := strings.TrimLeft(strings.TrimSuffix(name, ext), ".")
+	if base == "" {
+		base = "photo"
 	}
+	return base + ext
+}
 
-	err = os.WriteFile(metadataFile, data, filePermissions)
+// isNameTaken reports whether a photo, live, in the trash or being
+// uploaded, uses filename. The caller must hold saveMu.
+func (s *GalleryService) isNameTaken(filename string) bool {
+	if s.savingNames[filename] {
+		return true
+	}
+	if _, err := s.photos.Stat(filename); !errors.Is(err, ErrBlobNotFound) {
+		return true
+	}
+	if _, err := s.store.GetTrashedPhoto(filename); !errors.Is(err, ErrPhotoNotFound) {
+		return true
+	}
+	return false
+}
+
+// hashFile returns the hex SHA-256 of a file's content.
+func hashFile(path string) (string, error) {
+	// #nosec G304 - path points into a blob store or a temporary copy
+	file, err := os.Open(path)
 	if err != nil {
-		log.Printf("Failed to save metadata for %s: %v", filename, err)
+		return "", err
 	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
-func (s *GalleryService) loadPhotoMetadata(filename string) PhotoInfo {
-	metadataFile := filepath.Join(s.metadataDir, filename+".json")
-	// #nosec G304 - metadataFile is constructed from controlled metadataDir and filename
-	data, err := os.ReadFile(metadataFile)
+// blobNames returns the set of blob names in store.
+func (s *GalleryService) blobNames(store BlobStore) (map[string]bool, error) {
+	blobs, err := store.List()
 	if err != nil {
-		return PhotoInfo{}
+		return nil, err
 	}
 
-	var info PhotoInfo
-	err = json.Unmarshal(data, &info)
+	names := make(map[string]bool, len(blobs))
+	for _, blob := range blobs {
+		names[blob.Name] = true
+	}
+	return names, nil
+}
+
+func (s *GalleryService) loadPhotoMetadata(filename string) PhotoInfo {
+	info, err := s.store.GetPhoto(filename)
 	if err != nil {
-		log.Printf("Failed to unmarshal metadata for %s: %v", filename, err)
+		if !errors.Is(err, ErrPhotoNotFound) {
+			log.Printf("Failed to load metadata for %s: %v", filename, err)
+		}
 		return PhotoInfo{}
 	}
 
@@ -718,13 +1295,13 @@ func (s *GalleryService) extractExifPhotoTime(filePath string) time.Time {
 
 	// Try to extract from any field that might contain date information
 	log.Printf("Checking all EXIF fields for date information in %s", filepath.Base(filePath))
-	
+
 	// Create a walker to find date fields
 	walker := &dateWalker{}
 	if err := exifData.Walk(walker); err != nil {
 		log.Printf("Error walking EXIF data for %s: %v", filepath.Base(filePath), err)
 	}
-	
+
 	if !walker.foundDate.IsZero() {
 		return walker.foundDate
 	}
@@ -733,7 +1310,6 @@ func (s *GalleryService) extractExifPhotoTime(filePath string) time.Time {
 	return time.Time{} // Return zero time if no date fields found
 }
 
-
 func (s *GalleryService) extractPhotoTimeWithExifTool(filePath string) time.Time {
 	// Check if exiftool is available
 	if _, err := exec.LookPath("exiftool"); err != nil {
@@ -1,11 +1,14 @@
 package service
 
 import (
+	"errors"
 	"image"
 	"image/color"
 	"image/png"
+	"io"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -20,7 +23,7 @@ func TestNewGalleryService(t *testing.T) {
 		os.RemoveAll(metadataDir)
 	}()
 
-	service := NewGalleryService(uploadDir, metadataDir)
+	service := newTestGalleryService(t, uploadDir, metadataDir)
 
 	if service == nil {
 		t.Fatal("Expected service to be created, got nil")
@@ -36,7 +39,7 @@ func TestNewGalleryService(t *testing.T) {
 }
 
 func TestFilterPhotos(t *testing.T) {
-	service := NewGalleryService("uploads", "metadata")
+	service := newTestGalleryService(t, filepath.Join(t.TempDir(), "uploads"), filepath.Join(t.TempDir(), "metadata"))
 
 	photos := []PhotoInfo{
 		{
@@ -85,7 +88,7 @@ func TestFilterPhotos(t *testing.T) {
 }
 
 func TestGetUniqueEvents(t *testing.T) {
-	service := NewGalleryService("uploads", "metadata")
+	service := newTestGalleryService(t, filepath.Join(t.TempDir(), "uploads"), filepath.Join(t.TempDir(), "metadata"))
 
 	photos := []PhotoInfo{
 		{Event: "Birthday"},
@@ -114,7 +117,7 @@ func TestGetUniqueEvents(t *testing.T) {
 }
 
 func TestGetUniqueUploaders(t *testing.T) {
-	service := NewGalleryService("uploads", "metadata")
+	service := newTestGalleryService(t, filepath.Join(t.TempDir(), "uploads"), filepath.Join(t.TempDir(), "metadata"))
 
 	photos := []PhotoInfo{
 		{Uploader: "Alice"},
@@ -144,7 +147,7 @@ func TestGetUniqueUploaders(t *testing.T) {
 
 func TestServePhoto(t *testing.T) {
 	uploadDir := "test_uploads"
-	service := NewGalleryService(uploadDir, "metadata")
+	service := newTestGalleryService(t, uploadDir, filepath.Join(t.TempDir(), "metadata"))
 
 	// Clean up test directory
 	defer os.RemoveAll(uploadDir)
@@ -162,17 +165,25 @@ func TestServePhoto(t *testing.T) {
 	}
 
 	// Test existing file
-	path, err := service.ServePhoto("test.jpg")
+	photo, info, err := service.ServePhoto("test.jpg")
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("Expected no error, got %v", err)
 	}
+	defer photo.Close()
 
-	if path != testFile {
-		t.Errorf("Expected path %s, got %s", testFile, path)
+	content, err := io.ReadAll(photo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "test content" {
+		t.Errorf("Expected content %q, got %q", "test content", content)
+	}
+	if info.Name != "test.jpg" || info.Size != int64(len("test content")) {
+		t.Errorf("Unexpected blob info: %+v", info)
 	}
 
 	// Test non-existing file
-	_, err = service.ServePhoto("nonexistent.jpg")
+	_, _, err = service.ServePhoto("nonexistent.jpg")
 	if err == nil {
 		t.Error("Expected error for non-existent file, got nil")
 	}
@@ -205,13 +216,12 @@ func TestGenerateMissingMetadata(t *testing.T) {
 	}
 
 	// Create service (this should trigger metadata generation)
-	service := NewGalleryService(uploadDir, metadataDir)
+	service := newTestGalleryService(t, uploadDir, metadataDir)
 
-	// Verify metadata files were created
+	// Verify metadata entries were created
 	for _, filename := range testFiles {
-		metadataFile := filepath.Join(metadataDir, filename+".json")
-		if _, err := os.Stat(metadataFile); os.IsNotExist(err) {
-			t.Errorf("Expected metadata file %s to be created", metadataFile)
+		if _, err := service.store.GetPhoto(filename); err != nil {
+			t.Errorf("Expected metadata for %s to be created, got %v", filename, err)
 		}
 
 		// Verify metadata content
@@ -264,14 +274,19 @@ func TestGenerateMissingMetadataSkipsExisting(t *testing.T) {
 		Date:     time.Now(),
 	}
 
-	service := &GalleryService{
-		uploadDir:   uploadDir,
-		metadataDir: metadataDir,
+	store, err := OpenSQLiteStore(metadataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SavePhoto(&existingMetadata); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
 	}
-	service.savePhotoMetadata("existing.png", &existingMetadata)
 
 	// Now create service (should not overwrite existing metadata)
-	service = NewGalleryService(uploadDir, metadataDir)
+	service := newTestGalleryService(t, uploadDir, metadataDir)
 
 	// Verify existing metadata was preserved
 	photoInfo := service.loadPhotoMetadata("existing.png")
@@ -308,23 +323,29 @@ func TestServeThumbnail(t *testing.T) {
 	}
 
 	service := &GalleryService{
-		uploadDir:    uploadDir,
-		metadataDir:  metadataDir,
-		thumbnailDir: thumbnailDir,
+		uploadDir:   uploadDir,
+		metadataDir: metadataDir,
+		photos:      newTestFileBlobStore(t, uploadDir),
+		thumbnails:  newTestFileBlobStore(t, thumbnailDir),
 	}
 
 	// Test existing thumbnail
-	path, err := service.ServeThumbnail("test.jpg")
+	thumbnail, _, err := service.ServeThumbnail("test.jpg")
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("Expected no error, got %v", err)
 	}
+	defer thumbnail.Close()
 
-	if path != testThumbnail {
-		t.Errorf("Expected path %s, got %s", testThumbnail, path)
+	content, err := io.ReadAll(thumbnail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "test thumbnail content" {
+		t.Errorf("Expected content of %s, got %q", testThumbnail, content)
 	}
 
 	// Test non-existing thumbnail
-	_, err = service.ServeThumbnail("nonexistent.jpg")
+	_, _, err = service.ServeThumbnail("nonexistent.jpg")
 	if err == nil {
 		t.Error("Expected error for non-existent thumbnail, got nil")
 	}
@@ -352,9 +373,11 @@ func TestGenerateThumbnail(t *testing.T) {
 	}
 
 	service := &GalleryService{
-		uploadDir:    uploadDir,
-		metadataDir:  metadataDir,
-		thumbnailDir: thumbnailDir,
+		uploadDir:   uploadDir,
+		metadataDir: metadataDir,
+		store:       openTestStore(t, metadataDir),
+		photos:      newTestFileBlobStore(t, uploadDir),
+		thumbnails:  newTestFileBlobStore(t, thumbnailDir),
 	}
 
 	// Create a proper test PNG image programmatically
@@ -366,7 +389,7 @@ func TestGenerateThumbnail(t *testing.T) {
 
 	// Generate thumbnail
 	thumbnailPath := filepath.Join(thumbnailDir, "test.png")
-	err = service.generateThumbnail(testImagePath, thumbnailPath)
+	err = service.generateThumbnailFromPhoto("test.png")
 	if err != nil {
 		t.Errorf("Expected no error generating thumbnail, got %v", err)
 	}
@@ -377,6 +400,82 @@ func TestGenerateThumbnail(t *testing.T) {
 	}
 }
 
+func TestSaveWebPPhoto(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	tests := []struct {
+		fixture       string
+		width, height int
+	}{
+		{"video-001.lossy.webp", 150, 103},
+		{"gopher-doc.8bpp.lossless.webp", 75, 100},
+		{"yellow_rose.lossy-with-alpha.webp", 400, 301},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved, err := service.SavePhoto(newTestFileHeader(t, tt.fixture, "image/webp", data), User{Username: "Alice"}, "")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.fixture, err)
+		}
+		if saved.Photo.PerceptualHash == 0 {
+			t.Errorf("%s: expected a perceptual hash", tt.fixture)
+		}
+
+		photo, _, err := service.ServePhoto(saved.Photo.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, format, err := image.DecodeConfig(photo)
+		photo.Close()
+		if err != nil || format != "webp" || config.Width != tt.width || config.Height != tt.height {
+			t.Errorf("%s: expected %dx%d webp, got %dx%d %s (%v)", tt.fixture, tt.width, tt.height, config.Width, config.Height, format, err)
+		}
+
+		// WebP thumbnails are encoded as JPEG
+		thumbnail, _, err := service.ServeThumbnail(saved.Photo.Name)
+		if err != nil {
+			t.Fatalf("%s: expected a thumbnail, got %v", tt.fixture, err)
+		}
+		config, format, err = image.DecodeConfig(thumbnail)
+		thumbnail.Close()
+		if err != nil || format != "jpeg" || max(config.Width, config.Height) != thumbnailSize {
+			t.Errorf("%s: expected JPEG thumbnail, got %dx%d %s (%v)", tt.fixture, config.Width, config.Height, format, err)
+		}
+	}
+}
+
+// Helper function to create a gallery service that is closed when the test ends
+func newTestGalleryService(t *testing.T, uploadDir, metadataDir string, opts ...GalleryOption) *GalleryService {
+	t.Helper()
+
+	service, err := NewGalleryService(uploadDir, metadataDir, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create gallery service: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := service.Close(); err != nil {
+			t.Errorf("Failed to close gallery service: %v", err)
+		}
+	})
+
+	return service
+}
+
+// Helper function to create a filesystem blob store
+func newTestFileBlobStore(t *testing.T, dir string) *FileBlobStore {
+	t.Helper()
+
+	store, err := NewFileBlobStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
 // Helper function to create a valid test PNG image
 func createTestPNG(filename string) error {
 	// Create a 10x10 red image
@@ -419,9 +518,10 @@ func TestCleanupOrphanedThumbnails(t *testing.T) {
 	}
 
 	service := &GalleryService{
-		uploadDir:    uploadDir,
-		metadataDir:  metadataDir,
-		thumbnailDir: thumbnailDir,
+		uploadDir:   uploadDir,
+		metadataDir: metadataDir,
+		photos:      newTestFileBlobStore(t, uploadDir),
+		thumbnails:  newTestFileBlobStore(t, thumbnailDir),
 	}
 
 	// Create a valid image and its thumbnail
@@ -479,7 +579,7 @@ func TestExtractPhotoTime(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	service := NewGalleryService(uploadDir, metadataDir)
+	service := newTestGalleryService(t, uploadDir, metadataDir)
 
 	// Test with a non-existent file
 	photoTime := service.extractPhotoTime("nonexistent.jpg")
@@ -516,7 +616,7 @@ func TestPhotoSorting(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	service := NewGalleryService(uploadDir, metadataDir)
+	service := newTestGalleryService(t, uploadDir, metadataDir)
 
 	// Create test files with different times
 	now := time.Now()
@@ -554,7 +654,9 @@ func TestPhotoSorting(t *testing.T) {
 
 	// Save metadata files
 	for _, photo := range photos {
-		service.savePhotoMetadata(photo.Name, &photo)
+		if err := service.store.SavePhoto(&photo); err != nil {
+			t.Fatal(err)
+		}
 		// Create empty image files
 		filePath := filepath.Join(uploadDir, photo.Name)
 		file, err := os.Create(filePath)
@@ -564,6 +666,11 @@ func TestPhotoSorting(t *testing.T) {
 		file.Close()
 	}
 
+	// Metadata was written behind the service's back, reload the index
+	if err := service.RebuildIndex(); err != nil {
+		t.Fatal(err)
+	}
+
 	// Get photos (should be sorted)
 	sortedPhotos, err := service.GetPhotos()
 	if err != nil {
@@ -588,3 +695,243 @@ func TestPhotoSorting(t *testing.T) {
 		t.Errorf("Expected third photo to be old.jpg, got %s", sortedPhotos[2].Name)
 	}
 }
+
+func TestSavePhotoDeduplicates(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	data := testPNGBytes(t)
+	first, err := service.SavePhoto(newTestFileHeader(t, "IMG_001.png", "image/png", data), User{Username: "Alice"}, "Wedding")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Deduplicated {
+		t.Error("Expected first upload to be stored")
+	}
+	if first.Photo.Hash == "" {
+		t.Error("Expected content hash to be recorded")
+	}
+
+	second, err := service.SavePhoto(newTestFileHeader(t, "IMG_001.png", "image/png", data), User{Username: "Bob"}, "Wedding")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.Deduplicated || second.Photo.Name != "IMG_001.png" {
+		t.Errorf("Expected duplicate of IMG_001.png, got %+v", second)
+	}
+
+	blobs, err := service.photos.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 1 {
+		t.Errorf("Expected a single stored copy, got %d", len(blobs))
+	}
+
+	photos, err := service.QueryPhotos(PhotoFilter{Uploader: "Bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 1 || photos[0].Uploader != "Alice" || len(photos[0].Contributors) != 1 {
+		t.Errorf("Expected Bob to be attributed to Alice's photo, got %+v", photos)
+	}
+
+	uploaders, err := service.GetUploaders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploaders) != 2 {
+		t.Errorf("Expected Alice and Bob as uploaders, got %v", uploaders)
+	}
+}
+
+func TestSavePhotoConcurrentDuplicates(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	data := testPNGBytes(t)
+	results := make([]SaveResult, 8)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := service.SavePhoto(newTestFileHeader(t, "IMG_001.png", "image/png", data), User{Username: "Alice"}, "Wedding")
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+
+	stored := 0
+	for _, result := range results {
+		if !result.Deduplicated {
+			stored++
+		}
+		if result.Photo.Name != "IMG_001.png" {
+			t.Errorf("Expected every upload to end up as IMG_001.png, got %s", result.Photo.Name)
+		}
+	}
+	if stored != 1 {
+		t.Errorf("Expected a single stored upload, got %d", stored)
+	}
+}
+
+func TestSavePhotoMetadataFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"), WithRenditionSizes(350))
+
+	// An owner that doesn't exist makes saving the metadata fail
+	data := encodeTestImage(t, createPatternImage(500, 400, false), "png")
+	if _, err := service.SavePhoto(newTestFileHeader(t, "IMG_001.png", "image/png", data), User{ID: 42, Username: "Ghost"}, "Wedding"); err == nil {
+		t.Fatal("Expected upload by an unknown owner to fail")
+	}
+
+	for name, store := range map[string]BlobStore{"originals": service.photos, "thumbnails": service.thumbnails, "renditions": service.renditions[350]} {
+		blobs, err := store.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(blobs) != 0 {
+			t.Errorf("Expected no %s left behind, got %v", name, blobs)
+		}
+	}
+
+	// and the name is free again
+	result, err := service.SavePhoto(newTestFileHeader(t, "IMG_001.png", "image/png", data), User{Username: "Alice"}, "Wedding")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Photo.Name != "IMG_001.png" {
+		t.Errorf("Expected IMG_001.png, got %s", result.Photo.Name)
+	}
+}
+
+func TestSavePhotoHiddenFilename(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	uploads := []struct{ filename, want string }{
+		{".hidden.png", "hidden.png"},
+		{"...png", "photo.png"},
+		{".trash", "photo.trash"},
+	}
+	for i, upload := range uploads {
+		data := encodeTestImage(t, createPatternImage(20+i, 20, false), "png")
+		result, err := service.SavePhoto(newTestFileHeader(t, upload.filename, "image/png", data), User{Username: "Alice"}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Photo.Name != upload.want {
+			t.Errorf("Expected %s to be stored as %s, got %s", upload.filename, upload.want, result.Photo.Name)
+		}
+	}
+
+	// The photos are listed by the blob store, so the cleanup keeps them
+	service.CleanupOrphanedMetadata()
+	for _, upload := range uploads {
+		if _, err := service.store.GetPhoto(upload.want); err != nil {
+			t.Errorf("Expected %s to survive the cleanup, got %v", upload.want, err)
+		}
+	}
+}
+
+func TestGenerateMissingHashes(t *testing.T) {
+	tempDir := t.TempDir()
+	uploadDir := filepath.Join(tempDir, "uploads")
+	metadataDir := filepath.Join(tempDir, "metadata")
+
+	if err := os.MkdirAll(uploadDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := createTestPNG(filepath.Join(uploadDir, "old.png")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Metadata written before hashes were recorded
+	store := openTestStore(t, metadataDir)
+	info := PhotoInfo{Path: "/uploads/old.png", Name: "old.png", Uploader: "Alice", Date: time.Now()}
+	if err := store.SavePhoto(&info); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	service := newTestGalleryService(t, uploadDir, metadataDir)
+
+	photo, err := service.store.GetPhoto("old.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if photo.Hash == "" {
+		t.Fatal("Expected hash to be generated on startup")
+	}
+
+	result, err := service.SavePhoto(newTestFileHeader(t, "copy.png", "image/png", testPNGBytes(t)), User{Username: "Bob"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Deduplicated || result.Photo.Name != "old.png" {
+		t.Errorf("Expected upload to be deduplicated against old.png, got %+v", result)
+	}
+}
+
+func TestUpdatePhotoMetadata(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	uploads := []struct{ filename, event string }{
+		{"a.png", "Weding"},
+		{"b.png", "Weding"},
+		{"c.png", "Wedding"},
+	}
+	for i, upload := range uploads {
+		data := encodeTestImage(t, createPatternImage(20+i, 20, i%2 == 0), "png")
+		if _, err := service.SavePhoto(newTestFileHeader(t, upload.filename, "image/png", data), User{Username: "Alice"}, upload.event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	caption := "  Cutting the cake "
+	photo, err := service.UpdatePhotoMetadata("a.png", PhotoUpdate{Caption: &caption})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if photo.Caption != "Cutting the cake" || photo.Event != "Weding" {
+		t.Errorf("Expected trimmed caption and unchanged event, got %+v", photo)
+	}
+
+	empty := ""
+	if _, err := service.UpdatePhotoMetadata("a.png", PhotoUpdate{Uploader: &empty}); !errors.Is(err, ErrInvalidMetadata) {
+		t.Errorf("Expected ErrInvalidMetadata for empty uploader, got %v", err)
+	}
+	if _, err := service.UpdatePhotoMetadata("missing.png", PhotoUpdate{Caption: &caption}); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected ErrPhotoNotFound, got %v", err)
+	}
+
+	// Fix the misspelled event so both spellings merge into one filter entry
+	event := "Wedding"
+	photos, err := service.UpdatePhotosMetadata([]string{"a.png", "b.png"}, PhotoUpdate{Event: &event})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 2 || photos[0].Caption != "Cutting the cake" {
+		t.Errorf("Expected two updated photos keeping their caption, got %+v", photos)
+	}
+
+	events, err := service.GetEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0] != "Wedding" {
+		t.Errorf("Expected [Wedding], got %v", events)
+	}
+	filtered, err := service.QueryPhotos(PhotoFilter{Event: "Wedding"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 3 {
+		t.Errorf("Expected 3 photos in Wedding, got %d", len(filtered))
+	}
+}
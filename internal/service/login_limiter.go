@@ -0,0 +1,170 @@
+package service
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backoff configures the lockouts after repeated failed logins.
+type backoff struct {
+	free   int           // failures before the first lockout
+	base   time.Duration // lockout after the first failure beyond free, doubled for each further one
+	max    time.Duration // longest lockout
+	forget time.Duration // failures are forgotten after this long without a new one
+}
+
+var (
+	// clientBackoff limits the attempts of a single client IP.
+	clientBackoff = backoff{free: 5, base: 2 * time.Second, max: 15 * time.Minute, forget: time.Hour}
+	// globalBackoff limits the attempts of all clients together, e.g. against
+	// a distributed attack. Lockouts are short as they affect everybody.
+	globalBackoff = backoff{free: 100, base: time.Second, max: time.Minute, forget: time.Minute}
+)
+
+// attempts tracks the failed logins of a client or of all clients.
+type attempts struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// fail records a failed login at now and returns the lockout it starts, or 0.
+func (a *attempts) fail(now time.Time, b backoff) time.Duration {
+	if now.Sub(a.lastFailure) > b.forget {
+		a.failures = 0
+	}
+	a.failures++
+	a.lastFailure = now
+
+	excess := a.failures - b.free
+	if excess <= 0 {
+		return 0
+	}
+	lockout := b.max
+	if excess <= 30 && b.base<<(excess-1) < b.max {
+		lockout = b.base << (excess - 1)
+	}
+	a.lockedUntil = now.Add(lockout)
+	return lockout
+}
+
+// LoginLimiter throttles failed logins per client IP and globally with
+// exponentially growing lockouts. It is safe for concurrent use.
+type LoginLimiter struct {
+	mu        sync.Mutex
+	clients   map[string]*attempts
+	global    attempts
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// NewLoginLimiter creates a limiter without any recorded failures.
+func NewLoginLimiter() *LoginLimiter {
+	return &LoginLimiter{clients: make(map[string]*attempts), now: time.Now}
+}
+
+// Wait returns how long client has to wait before it may try to log in again,
+// or 0 if it may try now.
+func (l *LoginLimiter) Wait(client string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	lockedUntil := l.global.lockedUntil
+	if a, ok := l.clients[client]; ok && a.lockedUntil.After(lockedUntil) {
+		lockedUntil = a.lockedUntil
+	}
+	return max(lockedUntil.Sub(now), 0)
+}
+
+// Failure records a failed login of client.
+func (l *LoginLimiter) Failure(client string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+	a, ok := l.clients[client]
+	if !ok {
+		a = &attempts{}
+		l.clients[client] = a
+	}
+	if lockout := a.fail(now, clientBackoff); lockout > 0 {
+		log.Printf("Locked out %s for %s after %d failed logins", client, lockout, a.failures)
+	}
+	if lockout := l.global.fail(now, globalBackoff); lockout > 0 {
+		log.Printf("Locked out all logins for %s after %d failed logins within %s", lockout, l.global.failures, globalBackoff.forget)
+	}
+}
+
+// Success forgets the failed logins of client.
+func (l *LoginLimiter) Success(client string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.clients, client)
+}
+
+// prune drops clients whose failures have been forgotten, at most once a minute.
+func (l *LoginLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+	l.lastPrune = now
+	for client, a := range l.clients {
+		if now.Sub(a.lastFailure) > clientBackoff.forget && !a.lockedUntil.After(now) {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// DefaultTrustedProxies are the networks whose X-Forwarded-For headers are
+// trusted: loopback and private networks, such as the Docker network of the
+// bundled nginx proxy.
+var DefaultTrustedProxies = []netip.Prefix{
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("fc00::/7"),
+}
+
+// clientIP returns the IP address of the client that sent r. X-Forwarded-For
+// is only followed through trusted proxies, so clients cannot spoof it.
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+
+	// Proxies append the address they received the request from, so the
+	// client is the rightmost address that is not a trusted proxy
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0 && isTrusted(ip, trusted); i-- {
+		next, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+		ip = next
+	}
+	return ip.Unmap().String()
+}
+
+func isTrusted(ip netip.Addr, trusted []netip.Prefix) bool {
+	ip = ip.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
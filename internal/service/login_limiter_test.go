@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoginLimiter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewLoginLimiter()
+	limiter.now = func() time.Time { return now }
+
+	for range clientBackoff.free {
+		limiter.Failure("1.2.3.4")
+	}
+	if wait := limiter.Wait("1.2.3.4"); wait != 0 {
+		t.Fatalf("Expected no lockout within the free attempts, got %s", wait)
+	}
+
+	// Lockouts double with every further failure
+	want := clientBackoff.base
+	for range 3 {
+		limiter.Failure("1.2.3.4")
+		if wait := limiter.Wait("1.2.3.4"); wait != want {
+			t.Errorf("Expected lockout of %s, got %s", want, wait)
+		}
+		want *= 2
+	}
+	if wait := limiter.Wait("5.6.7.8"); wait != 0 {
+		t.Errorf("Expected other clients not to be locked out, got %s", wait)
+	}
+
+	now = now.Add(time.Minute)
+	if wait := limiter.Wait("1.2.3.4"); wait != 0 {
+		t.Errorf("Expected lockout to end, got %s", wait)
+	}
+	limiter.Success("1.2.3.4")
+	limiter.Failure("1.2.3.4")
+	if wait := limiter.Wait("1.2.3.4"); wait != 0 {
+		t.Errorf("Expected a successful login to reset the failures, got %s", wait)
+	}
+
+	// Many clients together trigger the global lockout
+	now = now.Add(2 * globalBackoff.forget)
+	for i := range globalBackoff.free + 1 {
+		limiter.Failure(fmt.Sprintf("10.0.0.%d", i))
+	}
+	if wait := limiter.Wait("5.6.7.8"); wait != globalBackoff.base {
+		t.Errorf("Expected global lockout of %s, got %s", globalBackoff.base, wait)
+	}
+}
+
+func TestLoginBackoffMax(t *testing.T) {
+	now := time.Now()
+	var a attempts
+	var lockout time.Duration
+	for range clientBackoff.free + 100 {
+		lockout = a.fail(now, clientBackoff)
+	}
+	if lockout != clientBackoff.max {
+		t.Errorf("Expected lockout capped at %s, got %s", clientBackoff.max, lockout)
+	}
+	if lockout := a.fail(now.Add(2*clientBackoff.forget), clientBackoff); lockout != 0 {
+		t.Errorf("Expected old failures to be forgotten, got lockout %s", lockout)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded string
+		want      string
+	}{
+		{"direct", "203.0.113.7:1234", "", "203.0.113.7"},
+		{"spoofed", "203.0.113.7:1234", "198.51.100.1", "203.0.113.7"},
+		{"proxied", "172.18.0.2:1234", "198.51.100.1", "198.51.100.1"},
+		{"proxy chain", "127.0.0.1:1234", "198.51.100.1, 10.0.0.5", "198.51.100.1"},
+		{"spoofed behind proxy", "172.18.0.2:1234", "192.0.2.9, 198.51.100.1", "198.51.100.1"},
+		{"invalid header", "172.18.0.2:1234", "unknown", "172.18.0.2"},
+		{"ipv6", "[2001:db8::1]:1234", "", "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/login", http.NoBody)
+		r.RemoteAddr = tt.remote
+		if tt.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", tt.forwarded)
+		}
+		if got := clientIP(r, DefaultTrustedProxies); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	r := httptest.NewRequest("POST", "/login", http.NoBody)
+	r.RemoteAddr = "172.18.0.2:1234"
+	r.Header.Set("X-Forwarded-For", "198.51.100.1")
+	if got := clientIP(r, []netip.Prefix{}); got != "172.18.0.2" {
+		t.Errorf("Expected X-Forwarded-For to be ignored without trusted proxies, got %s", got)
+	}
+}
+
+func TestLoginThrottling(t *testing.T) {
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!")
+	r := httptest.NewRequest("POST", "/login", http.NoBody)
+
+	for range clientBackoff.free + 1 {
+		ok := service.Login(httptest.NewRecorder(), r, "wrong")
+		service.RecordLogin(r, "", ok)
+	}
+	if service.LoginWait(r, "") == 0 {
+		t.Fatal("Expected client to be locked out after repeated failures")
+	}
+
+	other := httptest.NewRequest("POST", "/login", http.NoBody)
+	other.RemoteAddr = "198.51.100.1:1234"
+	if wait := service.LoginWait(other, ""); wait != 0 {
+		t.Errorf("Expected other client not to be locked out, got %s", wait)
+	}
+}
+
+func TestLoginThrottlingInterleaved(t *testing.T) {
+	gallery := newTestGalleryService(t, filepath.Join(t.TempDir(), "uploads"), filepath.Join(t.TempDir(), "metadata"))
+	if _, err := gallery.CreateUser("root", "root-password", RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!", WithAccounts(gallery))
+	r := httptest.NewRequest("POST", "/login", http.NoBody)
+
+	// Guessing the admin password between guest logins with the known guest
+	// password doesn't reset the failures
+	for range clientBackoff.free + 1 {
+		for range clientBackoff.free - 1 {
+			if service.LoginWait(r, "root") > 0 {
+				return
+			}
+			ok := service.LoginUser(httptest.NewRecorder(), r, "root", "guess")
+			service.RecordLogin(r, "root", ok)
+		}
+		ok := service.Login(httptest.NewRecorder(), r, "password")
+		service.RecordLogin(r, "", ok)
+	}
+	t.Error("Expected client to be locked out of the admin account")
+}
+
+func TestLoginThrottlingAccounts(t *testing.T) {
+	gallery := newTestGalleryService(t, filepath.Join(t.TempDir(), "uploads"), filepath.Join(t.TempDir(), "metadata"))
+	if _, err := gallery.CreateUser("carol", "carol-password", RoleContributor); err != nil {
+		t.Fatal(err)
+	}
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!", WithAccounts(gallery))
+	r := httptest.NewRequest("POST", "/login", http.NoBody)
+
+	for range clientBackoff.free + 1 {
+		ok := service.LoginUser(httptest.NewRecorder(), r, "root", "guess")
+		service.RecordLogin(r, "root", ok)
+	}
+	if service.LoginWait(r, "root") == 0 {
+		t.Fatal("Expected client to be locked out of the admin account")
+	}
+
+	// Other accounts of the same client are not affected, and logging in to
+	// one doesn't unlock the other
+	if wait := service.LoginWait(r, "carol"); wait != 0 {
+		t.Errorf("Expected other account not to be locked out, got %s", wait)
+	}
+	ok := service.LoginUser(httptest.NewRecorder(), r, "carol", "carol-password")
+	service.RecordLogin(r, "carol", ok)
+	if service.LoginWait(r, "root") == 0 {
+		t.Error("Expected lockout to survive a login to another account")
+	}
+}
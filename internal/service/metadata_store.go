@@ -0,0 +1,237 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrPhotoNotFound is returned by a MetadataStore when no metadata exists for a photo.
+	ErrPhotoNotFound = errors.New("photo metadata not found")
+	// ErrEventNotFound is returned by a MetadataStore for an unknown event ID.
+	ErrEventNotFound = errors.New("event not found")
+	// ErrEventExists is returned when renaming an event to the name of another
+	// event; such events have to be merged instead.
+	ErrEventExists = errors.New("an event with this name already exists")
+	// ErrAlbumNotFound is returned by a MetadataStore for an unknown album ID.
+	ErrAlbumNotFound = errors.New("album not found")
+	// ErrUserNotFound is returned by a MetadataStore for an unknown user.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUserExists is returned when creating a user with a taken username.
+	ErrUserExists = errors.New("a user with this name already exists")
+	// ErrShareNotFound is returned by a MetadataStore for an unknown or revoked share link.
+	ErrShareNotFound = errors.New("share not found")
+	// ErrSessionNotFound is returned by a MetadataStore for an unknown, expired or revoked session.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrAPITokenNotFound is returned by a MetadataStore for an unknown or revoked API token.
+	ErrAPITokenNotFound = errors.New("API token not found")
+)
+
+// PhotoFilter narrows down the photos returned by a MetadataStore.
+// Empty fields do not filter. Uploader also matches photos the uploader
+// contributed a duplicate of. Tags matches photos with any of the tags, or
+// with all of them if AllTags is set. Access limits the photos to those a
+// session may see.
+type PhotoFilter struct {
+	Event    string
+	Uploader string
+	Tags     []string
+	AllTags  bool
+	Access   *PhotoAccess
+}
+
+// Matches reports whether a photo passes the filter.
+func (f PhotoFilter) Matches(photo PhotoInfo) bool {
+	if !f.Access.Allows(photo) {
+		return false
+	}
+	if f.Event != "" && photo.Event != f.Event {
+		return false
+	}
+	if f.Uploader != "" && photo.Uploader != f.Uploader && !slices.Contains(photo.Contributors, f.Uploader) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		matched := 0
+		for _, tag := range f.Tags {
+			if slices.Contains(photo.Tags, tag) {
+				matched++
+			}
+		}
+		if matched == 0 || f.AllTags && matched < len(f.Tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// PhotoUpdate changes the editable metadata of photos. Nil fields are left
+// unchanged. Tags replaces all tags of a photo; AddTags and RemoveTags change
+// individual tags and are applied after Tags.
+type PhotoUpdate struct {
+	Event      *string
+	Uploader   *string
+	Caption    *string
+	Tags       *[]string
+	AddTags    []string
+	RemoveTags []string
+}
+
+// EventUpdate changes the details of an event. Nil fields are left unchanged;
+// a zero date, an empty cover photo or an empty password clears the value.
+type EventUpdate struct {
+	Name        *string
+	Description *string
+	StartDate   *time.Time
+	EndDate     *time.Time
+	CoverPhoto  *string // name of a photo of the event
+	// Password protects the event; GalleryService replaces it with its hash
+	Password *string
+}
+
+// MetadataStore persists photo metadata and answers the gallery queries.
+// Photos in the trash are only returned by GetTrashedPhoto and ListTrash.
+type MetadataStore interface {
+	// SavePhoto inserts or replaces the metadata for info.Name and sets info.ID.
+	// An OwnerID of 0 saves the photo without an owner.
+	// A photo in the trash with the same name is restored. Contributors and
+	// tags are not saved, see AddContributor and UpdatePhotos.
+	SavePhoto(info *PhotoInfo) error
+	// GetPhoto returns the metadata for a photo or ErrPhotoNotFound.
+	GetPhoto(name string) (PhotoInfo, error)
+	// GetTrashedPhoto returns the metadata for a photo in the trash or ErrPhotoNotFound.
+	GetTrashedPhoto(name string) (PhotoInfo, error)
+	// FindPhotoByHash returns the oldest photo in event with the given content
+	// hash or ErrPhotoNotFound.
+	FindPhotoByHash(hash, event string) (PhotoInfo, error)
+	// AddContributor attributes a photo to an additional uploader who uploaded
+	// an identical copy. Adding the original uploader or an existing contributor is a no-op.
+	AddContributor(name, uploader string) error
+	// UpdatePhotos applies update to all named photos in one transaction. If a
+	// photo does not exist nothing is changed and ErrPhotoNotFound is returned.
+	UpdatePhotos(names []string, update PhotoUpdate) error
+	// SetPerceptualHash stores the perceptual hash of an existing photo.
+	SetPerceptualHash(name string, hash uint64) error
+	// ResolveDuplicates records in one transaction that the kept photos are
+	// not to be reported as near-duplicates of each other, credits the
+	// uploaders of the removed photos on the first kept one and moves the
+	// removed photos to the trash. If a removed photo is not live, nothing is
+	// changed and ErrPhotoNotFound is returned.
+	ResolveDuplicates(kept, removed []PhotoInfo, deletedAt time.Time) error
+	// DismissedDuplicates returns all dismissed pairs, keyed by ascending photo ID.
+	DismissedDuplicates() (map[[2]int64]bool, error)
+	// TrashPhoto moves a photo to the trash or returns ErrPhotoNotFound.
+	TrashPhoto(name string, deletedAt time.Time) error
+	// RestorePhoto moves a photo out of the trash or returns ErrPhotoNotFound.
+	RestorePhoto(name string) error
+	// ListTrash returns the photos in the trash, most recently deleted first.
+	ListTrash() ([]PhotoInfo, error)
+	// DeletePhoto permanently removes the metadata for a photo, in the trash or not.
+	// Deleting a missing photo is not an error.
+	DeletePhoto(name string) error
+	// ListPhotos returns the matching photos sorted by photo time (newest first),
+	// falling back to the upload time for photos without a photo time.
+	ListPhotos(filter PhotoFilter) ([]PhotoInfo, error)
+	// CountPhotos returns the number of matching photos.
+	CountPhotos(filter PhotoFilter) (int, error)
+	// DistinctEvents returns all non-empty event names in alphabetical order.
+	DistinctEvents() ([]string, error)
+	// DistinctUploaders returns all non-empty uploader names, including
+	// contributors, in alphabetical order.
+	DistinctUploaders() ([]string, error)
+	// ListEvents returns all events in alphabetical order. Events are created
+	// implicitly when a photo is saved with a new event name; the returned
+	// events only carry the details stored for them, not values derived from photos.
+	ListEvents() ([]Event, error)
+	// GetEvent returns an event or ErrEventNotFound.
+	GetEvent(id int64) (Event, error)
+	// UpdateEvent changes an event in one transaction. Renaming rewrites the
+	// event name of all its photos and fails with ErrEventExists if the new name
+	// is taken. update.Password holds the password hash.
+	UpdateEvent(id int64, update EventUpdate) error
+	// EventPasswordHashes returns the password hashes of all protected events by event ID.
+	EventPasswordHashes() (map[int64]string, error)
+	// MergeEvents moves all photos of the source events to the target event and
+	// deletes the source events, in one transaction.
+	MergeEvents(target int64, sources []int64) error
+	// CreateAlbum creates an empty album.
+	CreateAlbum(name string) (Album, error)
+	// ListAlbums returns all albums sorted by name.
+	ListAlbums() ([]Album, error)
+	// GetAlbum returns an album or ErrAlbumNotFound.
+	GetAlbum(id int64) (Album, error)
+	// RenameAlbum changes the name of an album or returns ErrAlbumNotFound.
+	RenameAlbum(id int64, name string) error
+	// DeleteAlbum deletes an album, not its photos, or returns ErrAlbumNotFound.
+	DeleteAlbum(id int64) error
+	// AlbumPhotos returns the photos of an album in album order. Photos in the
+	// trash stay in the album but are not returned.
+	AlbumPhotos(id int64) ([]PhotoInfo, error)
+	// AddAlbumPhotos appends photos to an album in one transaction. Photos
+	// already in the album keep their position. If a photo does not exist
+	// nothing is changed and ErrPhotoNotFound is returned.
+	AddAlbumPhotos(id int64, names []string) error
+	// RemoveAlbumPhoto removes a photo from an album or returns ErrPhotoNotFound
+	// if it is not part of the album.
+	RemoveAlbumPhoto(id int64, name string) error
+	// ReorderAlbum sets the order of the photos in an album. names must list
+	// every photo returned by AlbumPhotos exactly once, else ErrInvalidAlbum is returned.
+	ReorderAlbum(id int64, names []string) error
+	// CreateUser creates an account or returns ErrUserExists.
+	CreateUser(username, passwordHash string, role Role) (User, error)
+	// UserCredentials returns a user and their password hash by username or ErrUserNotFound.
+	UserCredentials(username string) (User, string, error)
+	// GetUser returns a user or ErrUserNotFound.
+	GetUser(id int64) (User, error)
+	// ListUsers returns all users sorted by username.
+	ListUsers() ([]User, error)
+	// UpdateUser changes the role and/or password hash of a user; nil values
+	// are left unchanged. A new password hash deletes the user's sessions.
+	// It returns ErrUserNotFound for an unknown user.
+	UpdateUser(id int64, role *Role, passwordHash *string) error
+	// DeleteUser deletes a user or returns ErrUserNotFound. Their photos are
+	// kept without an owner.
+	DeleteUser(id int64) error
+	// CreateShare saves a new share link and returns it with its ID.
+	CreateShare(share Share) (Share, error)
+	// ListShares returns all share links, newest first.
+	ListShares() ([]Share, error)
+	// GetShare returns a share link or ErrShareNotFound.
+	GetShare(id int64) (Share, error)
+	// DeleteShare deletes a share link or returns ErrShareNotFound. Share links
+	// are also deleted with their photo, event or album.
+	DeleteShare(id int64) error
+	// CreateSession saves a new session identified by the hash of its token
+	// and deletes expired sessions. Sessions are deleted with their user or
+	// share link.
+	CreateSession(tokenHash string, session Session) (Session, error)
+	// SessionByToken returns an unexpired session by the hash of its token or
+	// ErrSessionNotFound.
+	SessionByToken(tokenHash string) (Session, error)
+	// UpdateSession replaces the values and expiry of a session or returns
+	// ErrSessionNotFound.
+	UpdateSession(tokenHash string, data []byte, expires time.Time) error
+	// TouchSession records that a session was used now, from lastIP with
+	// userAgent. It returns ErrSessionNotFound for an unknown session.
+	TouchSession(tokenHash string, lastIP, userAgent string) error
+	// ListSessions returns all unexpired sessions, most recently used first.
+	ListSessions() ([]Session, error)
+	// DeleteSession deletes a session or returns ErrSessionNotFound.
+	DeleteSession(id int64) error
+	// DeleteSessionByToken deletes a session by the hash of its token or
+	// returns ErrSessionNotFound.
+	DeleteSessionByToken(tokenHash string) error
+	// CreateAPIToken saves a new API token identified by the hash of its value.
+	CreateAPIToken(tokenHash string, token APIToken) (APIToken, error)
+	// APITokenByHash returns an API token by the hash of its value or ErrAPITokenNotFound.
+	APITokenByHash(tokenHash string) (APIToken, error)
+	// TouchAPIToken records that an API token was used now.
+	TouchAPIToken(tokenHash string) error
+	// ListAPITokens returns all API tokens, newest first.
+	ListAPITokens() ([]APIToken, error)
+	// DeleteAPIToken deletes an API token or returns ErrAPITokenNotFound.
+	DeleteAPIToken(id int64) error
+	// Close releases the resources held by the store.
+	Close() error
+}
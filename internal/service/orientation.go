@@ -0,0 +1,217 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"log"
+
+	"github.com/rwcarlsen/goexif/exif"
+	"golang.org/x/image/draw"
+)
+
+// WithRotatedDisplayCopies stores a physically rotated JPEG copy of photos
+// with an EXIF orientation next to the original and serves it as their
+// display version (see ServeDisplayPhoto), for browsers that ignore the
+// orientation of full-size images.
+func WithRotatedDisplayCopies() GalleryOption {
+	return func(s *GalleryService) {
+		s.rotatedCopies = true
+	}
+}
+
+// RotatedDisplayCopies reports whether photos with an EXIF orientation are
+// shown from their rotated display copy.
+func (s *GalleryService) RotatedDisplayCopies() bool {
+	return s.rotatedCopies
+}
+
+// storeRotatedCopy stores the photo shown with the orientation as its
+// display version.
+func (s *GalleryService) storeRotatedCopy(img image.Image, orientation int, filename string) error {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, orient(img, orientation), &jpeg.Options{Quality: derivativeQuality}); err != nil {
+		return fmt.Errorf("failed to encode rotated copy: %w", err)
+	}
+	if err := s.derivatives.Put(filename, &buf, int64(buf.Len())); err != nil {
+		return fmt.Errorf("failed to store rotated copy: %w", err)
+	}
+	return nil
+}
+
+// GenerateMissingRotatedCopies creates the rotated display copies of photos
+// stored before they were enabled. Only photos with an EXIF orientation get
+// one, which takes reading the EXIF data of every photo without a copy.
+func (s *GalleryService) GenerateMissingRotatedCopies() {
+	if !s.rotatedCopies {
+		return
+	}
+
+	files, err := s.photos.List()
+	if err != nil {
+		log.Printf("Failed to list photos for rotated copies: %v", err)
+		return
+	}
+	existing, err := s.blobNames(s.derivatives)
+	if err != nil {
+		log.Printf("Failed to list display versions: %v", err)
+		return
+	}
+
+	generatedCount := 0
+	for _, file := range files {
+		// Converted photos are rotated by their JPEG version
+		if !s.isImageFile(file.Name) || needsConversion(file.Name) || existing[file.Name] {
+			continue
+		}
+		generated, err := s.generateRotatedCopyFromPhoto(file.Name)
+		if err != nil {
+			log.Printf("Failed to generate rotated copy of %s: %v", file.Name, err)
+			continue
+		}
+		if generated {
+			generatedCount++
+		}
+	}
+
+	if generatedCount > 0 {
+		log.Printf("Generated rotated copies of %d existing photos", generatedCount)
+	}
+}
+
+// generateRotatedCopyFromPhoto stores the rotated copy of a stored photo if it
+// has an EXIF orientation, and reports whether it did.
+func (s *GalleryService) generateRotatedCopyFromPhoto(filename string) (bool, error) {
+	photo, _, err := s.photos.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer photo.Close()
+
+	orientation := readOrientation(photo)
+	if orientation == 1 {
+		return false, nil
+	}
+	if _, err := photo.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+	img, _, err := image.Decode(photo)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return true, s.storeRotatedCopy(img, orientation, filename)
+}
+
+// readOrientation returns the EXIF orientation (1-8) of an image, 1 for
+// images without EXIF data or orientation.
+func readOrientation(r io.Reader) int {
+	x, err := exif.Decode(r)
+	if err != nil {
+		return 1 // Not an error - many images have no EXIF data
+	}
+	tag, err := x.Get(exif.Orientation)
+	if err != nil {
+		return 1
+	}
+	orientation, err := tag.Int(0)
+	if err != nil || orientation < 1 || orientation > 8 {
+		return 1
+	}
+	return orientation
+}
+
+// orientedBounds returns the size of an image with the given bounds when
+// shown with the orientation; orientations 5-8 swap width and height.
+func orientedBounds(bounds image.Rectangle, orientation int) image.Rectangle {
+	if orientation >= 5 {
+		return image.Rect(0, 0, bounds.Dy(), bounds.Dx())
+	}
+	return image.Rect(0, 0, bounds.Dx(), bounds.Dy())
+}
+
+// resizeOriented scales src with the resampling filter and applies the
+// orientation, so the result is width x height. It also returns the scaled
+// image before orientation, which further renditions are scaled from.
+func resizeOriented(src image.Image, width, height, orientation int, resampling Resampling) (oriented, scaled image.Image) {
+	if orientation >= 5 {
+		scaled = resizeImage(src, height, width, resampling)
+	} else {
+		scaled = resizeImage(src, width, height, resampling)
+	}
+	return orient(scaled, orientation), scaled
+}
+
+// orientedImage shows an image stored with an EXIF orientation upright
+// without copying it, for reading a few of its pixels.
+type orientedImage struct {
+	image.Image
+	orientation int
+}
+
+// orientedView returns img as shown with the orientation.
+func orientedView(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	return orientedImage{img, orientation}
+}
+
+func (o orientedImage) Bounds() image.Rectangle {
+	return orientedBounds(o.Image.Bounds(), o.orientation)
+}
+
+func (o orientedImage) At(x, y int) color.Color {
+	bounds := o.Image.Bounds()
+	sx, sy := storedPoint(x, y, bounds.Dx(), bounds.Dy(), o.orientation)
+	return o.Image.At(bounds.Min.X+sx, bounds.Min.Y+sy)
+}
+
+// storedPoint returns the position in a width x height image stored with the
+// orientation of the pixel shown at x, y.
+func storedPoint(x, y, width, height, orientation int) (int, int) {
+	switch orientation {
+	case 2: // Mirrored horizontally
+		return width - 1 - x, y
+	case 3: // Rotated 180°
+		return width - 1 - x, height - 1 - y
+	case 4: // Mirrored vertically
+		return x, height - 1 - y
+	case 5: // Transposed
+		return y, x
+	case 6: // Rotated 90° clockwise to show
+		return y, height - 1 - x
+	case 7: // Transversed
+		return width - 1 - y, height - 1 - x
+	case 8: // Rotated 90° counter-clockwise to show
+		return width - 1 - y, x
+	}
+	return x, y
+}
+
+// orient mirrors and rotates an image stored with an EXIF orientation, so it
+// is shown upright.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	src, ok := img.(*image.RGBA)
+	if !ok {
+		src = image.NewRGBA(img.Bounds())
+		draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+	}
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(orientedBounds(src.Rect, orientation))
+
+	for y := range dst.Rect.Dy() {
+		for x := range dst.Rect.Dx() {
+			sx, sy := storedPoint(x, y, width, height, orientation)
+			i := src.PixOffset(src.Rect.Min.X+sx, src.Rect.Min.Y+sy)
+			copy(dst.Pix[dst.PixOffset(x, y):], src.Pix[i:i+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+)
+
+// withEXIFOrientation inserts an EXIF segment with the orientation into a JPEG.
+func withEXIFOrientation(t *testing.T, data []byte, orientation uint16) []byte {
+	t.Helper()
+
+	var tiff bytes.Buffer
+	tiff.WriteString("II*\x00")
+	for _, v := range []any{
+		uint32(8),      // Offset of the first IFD
+		uint16(1),      // Number of entries
+		uint16(0x0112), // Orientation tag
+		uint16(3),      // Type SHORT
+		uint32(1),      // Count
+		orientation,    // Value
+		uint16(0),      // Padding of the value to 4 bytes
+		uint32(0),      // No next IFD
+	} {
+		if err := binary.Write(&tiff, binary.LittleEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:2]) // SOI
+	out.Write([]byte{0xFF, 0xE1})
+	if err := binary.Write(&out, binary.BigEndian, uint16(2+6+tiff.Len())); err != nil {
+		t.Fatal(err)
+	}
+	out.WriteString("Exif\x00\x00")
+	out.Write(tiff.Bytes())
+	out.Write(data[2:])
+	return out.Bytes()
+}
+
+func TestOrient(t *testing.T) {
+	// 3x2 image with a red top-left and a blue top-right pixel
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	src.Set(0, 0, red)
+	src.Set(2, 0, blue)
+
+	tests := []struct {
+		orientation int
+		size        image.Point
+		red, blue   image.Point
+	}{
+		{1, image.Pt(3, 2), image.Pt(0, 0), image.Pt(2, 0)},
+		{2, image.Pt(3, 2), image.Pt(2, 0), image.Pt(0, 0)},
+		{3, image.Pt(3, 2), image.Pt(2, 1), image.Pt(0, 1)},
+		{4, image.Pt(3, 2), image.Pt(0, 1), image.Pt(2, 1)},
+		{5, image.Pt(2, 3), image.Pt(0, 0), image.Pt(0, 2)},
+		{6, image.Pt(2, 3), image.Pt(1, 0), image.Pt(1, 2)},
+		{7, image.Pt(2, 3), image.Pt(1, 2), image.Pt(1, 0)},
+		{8, image.Pt(2, 3), image.Pt(0, 2), image.Pt(0, 0)},
+	}
+	for _, tt := range tests {
+		// The view shows the same pixels as the copy
+		for _, dst := range []image.Image{orient(src, tt.orientation), orientedView(src, tt.orientation)} {
+			if dst.Bounds().Size() != tt.size {
+				t.Errorf("Orientation %d: expected size %v, got %v", tt.orientation, tt.size, dst.Bounds().Size())
+				continue
+			}
+			if dst.At(tt.red.X, tt.red.Y) != red || dst.At(tt.blue.X, tt.blue.Y) != blue {
+				t.Errorf("Orientation %d: expected red at %v and blue at %v", tt.orientation, tt.red, tt.blue)
+			}
+		}
+	}
+}
+
+func TestOrientedPerceptualHash(t *testing.T) {
+	stored := createPatternImage(300, 200, false)
+	upright := dHash(orient(stored, 6))
+	if hammingDistance(upright, dHash(stored)) <= similarityThreshold {
+		t.Fatal("Expected the pattern to hash differently when rotated")
+	}
+
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+	photo := withEXIFOrientation(t, encodeTestImage(t, stored, "jpeg"), 6)
+	saved, err := service.SavePhoto(newTestFileHeader(t, "rotated.jpg", "image/jpeg", photo), User{Username: "Alice"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := hammingDistance(saved.Photo.PerceptualHash, upright); diff > 2 {
+		t.Errorf("Expected the hash of the upright photo, dHash distance %d", diff)
+	}
+
+	// Hashes computed on startup are the same
+	hash, err := service.perceptualHashFromPhoto("rotated.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != saved.Photo.PerceptualHash {
+		t.Errorf("Expected startup hash %x, got %x", saved.Photo.PerceptualHash, hash)
+	}
+}
+
+func TestSaveOrientedPhoto(t *testing.T) {
+	// Stored landscape with a red left half, shown portrait with a red top
+	stored := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := range 200 {
+		for x := range 400 {
+			c := color.RGBA{0, 0, 255, 255}
+			if x < 200 {
+				c = color.RGBA{255, 0, 0, 255}
+			}
+			stored.Set(x, y, c)
+		}
+	}
+	photo := withEXIFOrientation(t, encodeTestImage(t, stored, "jpeg"), 6)
+	if got := readOrientation(bytes.NewReader(photo)); got != 6 {
+		t.Fatalf("Expected orientation 6, got %d", got)
+	}
+
+	for _, rotatedCopies := range []bool{false, true} {
+		tempDir := t.TempDir()
+		opts := []GalleryOption{WithRenditionSizes(350)}
+		if rotatedCopies {
+			opts = append(opts, WithRotatedDisplayCopies())
+		}
+		service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"), opts...)
+		if _, err := service.SavePhoto(newTestFileHeader(t, "portrait.jpg", "image/jpeg", photo), User{Username: "Alice"}, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, size := range []int{thumbnailSize, 350} {
+			rendition, _, err := service.ServeRendition(size, "portrait.jpg")
+			if err != nil {
+				t.Fatal(err)
+			}
+			img, _, err := image.Decode(rendition)
+			rendition.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			width, height := size/2, size
+			if img.Bounds().Dx() != width || img.Bounds().Dy() != height {
+				t.Errorf("Expected %dx%d rendition, got %v", width, height, img.Bounds())
+			}
+			if r, _, b, _ := img.At(width/2, height/4).RGBA(); r < b {
+				t.Errorf("Expected red top half in %dpx rendition", size)
+			}
+		}
+
+		// The display version is the rotated copy if enabled, else the original
+		display, info, err := service.ServeDisplayPhoto("portrait.jpg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, _, err := image.DecodeConfig(display)
+		display.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := image.Pt(400, 200)
+		if rotatedCopies {
+			want = image.Pt(200, 400)
+		}
+		if got := image.Pt(config.Width, config.Height); got != want || info.Name != "portrait.jpg" {
+			t.Errorf("Rotated copies %v: expected %v display version, got %v %s", rotatedCopies, want, got, info.Name)
+		}
+	}
+}
+
+func TestGenerateMissingRotatedCopies(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	landscape := encodeTestImage(t, createPatternImage(400, 200, false), "jpeg")
+	for name, photo := range map[string][]byte{
+		"portrait.jpg":  withEXIFOrientation(t, landscape, 6),
+		"landscape.jpg": landscape,
+	} {
+		if _, err := service.SavePhoto(newTestFileHeader(t, name, "image/jpeg", photo), User{Username: "Alice"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Enabling rotated copies later creates them for existing photos
+	service.GenerateMissingRotatedCopies()
+	if _, err := service.derivatives.Stat("portrait.jpg"); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("Expected no rotated copy while disabled, got %v", err)
+	}
+	service.rotatedCopies = true
+	service.GenerateMissingRotatedCopies()
+
+	for name, want := range map[string]image.Point{"portrait.jpg": {200, 400}, "landscape.jpg": {400, 200}} {
+		display, _, err := service.ServeDisplayPhoto(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, _, err := image.DecodeConfig(display)
+		display.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := image.Pt(config.Width, config.Height); got != want {
+			t.Errorf("Expected %v display version of %s, got %v", want, name, got)
+		}
+	}
+	if _, err := service.derivatives.Stat("landscape.jpg"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected no rotated copy without orientation, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Argon2id parameters of new hashes, the second recommended option of RFC 9106.
+// Existing hashes are checked with the parameters encoded in them.
+const (
+	argon2Time       = 3         // Number of passes over the memory
+	argon2Memory     = 64 * 1024 // Memory in KiB
+	argon2Threads    = 4         // Degree of parallelism
+	argon2SaltLength = 16        // Salt length in bytes
+	argon2KeyLength  = 32        // Hash length in bytes
+)
+
+// ErrInvalidPasswordHash is returned for malformed or unsupported password hashes.
+var ErrInvalidPasswordHash = errors.New("invalid password hash")
+
+// PasswordHashAlgorithm is an algorithm for hashing the shared gallery password.
+type PasswordHashAlgorithm string
+
+const (
+	// PasswordHashArgon2id hashes with argon2id in the PHC string format
+	// ($argon2id$v=19$m=...,t=...,p=...$salt$hash).
+	PasswordHashArgon2id PasswordHashAlgorithm = "argon2id"
+	// PasswordHashBcrypt hashes with bcrypt ($2a$...), like account passwords.
+	PasswordHashBcrypt PasswordHashAlgorithm = "bcrypt"
+)
+
+// HashPassword returns a hash of password for use with WithPasswordHash.
+func HashPassword(password string, algorithm PasswordHashAlgorithm) (string, error) {
+	if password == "" {
+		return "", fmt.Errorf("%w: password must not be empty", ErrInvalidPasswordHash)
+	}
+
+	switch algorithm {
+	case PasswordHashArgon2id:
+		salt := make([]byte, argon2SaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return "", fmt.Errorf("failed to generate salt: %w", err)
+		}
+		key := argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLength)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argon2Memory, argon2Time, argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	case PasswordHashBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidPasswordHash, err)
+		}
+		return string(hash), nil
+	}
+	return "", fmt.Errorf("%w: unknown algorithm %q", ErrInvalidPasswordHash, algorithm)
+}
+
+// ValidatePasswordHash checks that hash is a bcrypt or argon2id hash that
+// CheckPasswordHash can verify passwords against.
+func ValidatePasswordHash(hash string) error {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		_, err := parseArgon2Hash(hash)
+		return err
+	}
+	if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+		return fmt.Errorf("%w: expected a bcrypt or argon2id hash: %w", ErrInvalidPasswordHash, err)
+	}
+	return nil
+}
+
+// CheckPasswordHash reports whether password matches the bcrypt or argon2id
+// hash. It returns ErrInvalidPasswordHash if hash is malformed.
+func CheckPasswordHash(hash, password string) (bool, error) {
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrInvalidPasswordHash, err)
+		}
+		return true, nil
+	}
+
+	params, err := parseArgon2Hash(hash)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), params.salt, params.time, params.memory, params.threads, uint32(len(params.key))) // #nosec G115 -- key length is checked when parsing
+	return subtle.ConstantTimeCompare(key, params.key) == 1, nil
+}
+
+// argon2Hash holds the parts of an argon2id hash in PHC string format.
+type argon2Hash struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func parseArgon2Hash(hash string) (argon2Hash, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return argon2Hash{}, fmt.Errorf("%w: malformed argon2id hash", ErrInvalidPasswordHash)
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return argon2Hash{}, fmt.Errorf("%w: unsupported argon2 version %q", ErrInvalidPasswordHash, parts[2])
+	}
+
+	var h argon2Hash
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.memory, &h.time, &h.threads); err != nil {
+		return argon2Hash{}, fmt.Errorf("%w: malformed argon2id parameters %q", ErrInvalidPasswordHash, parts[3])
+	}
+	if h.memory == 0 || h.time == 0 || h.threads == 0 {
+		return argon2Hash{}, fmt.Errorf("%w: argon2id parameters must be positive", ErrInvalidPasswordHash)
+	}
+
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return argon2Hash{}, fmt.Errorf("%w: malformed argon2id salt", ErrInvalidPasswordHash)
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(h.key) < 16 || len(h.key) > 1024 {
+		return argon2Hash{}, fmt.Errorf("%w: malformed argon2id hash value", ErrInvalidPasswordHash)
+	}
+	return h, nil
+}
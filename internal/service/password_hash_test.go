@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	for _, algorithm := range []PasswordHashAlgorithm{PasswordHashArgon2id, PasswordHashBcrypt} {
+		hash, err := HashPassword("gallery-password", algorithm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidatePasswordHash(hash); err != nil {
+			t.Errorf("%s: expected valid hash %q, got %v", algorithm, hash, err)
+		}
+		if ok, err := CheckPasswordHash(hash, "gallery-password"); !ok || err != nil {
+			t.Errorf("%s: expected password to match (%v)", algorithm, err)
+		}
+		if ok, err := CheckPasswordHash(hash, "wrong-password"); ok || err != nil {
+			t.Errorf("%s: expected wrong password not to match (%v)", algorithm, err)
+		}
+	}
+
+	if _, err := HashPassword("", PasswordHashArgon2id); !errors.Is(err, ErrInvalidPasswordHash) {
+		t.Errorf("Expected ErrInvalidPasswordHash for an empty password, got %v", err)
+	}
+	if _, err := HashPassword("gallery-password", "md5"); !errors.Is(err, ErrInvalidPasswordHash) {
+		t.Errorf("Expected ErrInvalidPasswordHash for an unknown algorithm, got %v", err)
+	}
+}
+
+func TestCheckPasswordHash(t *testing.T) {
+	// Reference hash of "password" with salt "somesalt", as produced by the argon2 CLI
+	reference := "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+	if ok, err := CheckPasswordHash(reference, "password"); !ok || err != nil {
+		t.Errorf("Expected reference hash to match (%v)", err)
+	}
+
+	invalid := []string{
+		"",
+		"password",
+		"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ",
+		"$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=0,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$not*base64",
+		"$2a$10$tooshort",
+	}
+	for _, hash := range invalid {
+		if err := ValidatePasswordHash(hash); !errors.Is(err, ErrInvalidPasswordHash) {
+			t.Errorf("Expected ErrInvalidPasswordHash for %q, got %v", hash, err)
+		}
+		if ok, err := CheckPasswordHash(hash, "password"); ok || !errors.Is(err, ErrInvalidPasswordHash) {
+			t.Errorf("Expected ErrInvalidPasswordHash checking %q, got %v (%v)", hash, err, ok)
+		}
+	}
+}
+
+func TestLoginWithPasswordHash(t *testing.T) {
+	hash, err := HashPassword("hashed-password", PasswordHashBcrypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewAuthService("", "test-session-key-32-bytes-long!!", WithPasswordHash(hash))
+
+	if service.Login(httptest.NewRecorder(), httptest.NewRequest("POST", "/login", http.NoBody), "wrong-password") {
+		t.Error("Expected login to fail with a wrong password")
+	}
+	if service.Login(httptest.NewRecorder(), httptest.NewRequest("POST", "/login", http.NoBody), hash) {
+		t.Error("Expected login to fail with the hash itself")
+	}
+	w := httptest.NewRecorder()
+	if !service.Login(w, httptest.NewRequest("POST", "/login", http.NoBody), "hashed-password") {
+		t.Fatal("Expected login to succeed with the hashed password")
+	}
+	if cookies := w.Result().Cookies(); len(cookies) == 0 || cookies[0].Name != "gallery-session" {
+		t.Errorf("Expected a session cookie, got %v", cookies)
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// photoIndex is a concurrency-safe in-memory view of all photo metadata.
+// It serves the gallery queries so that rendering a page does not touch the disk.
+type photoIndex struct {
+	mu     sync.RWMutex
+	byName map[string]PhotoInfo
+	sorted []PhotoInfo // cached sort order, nil when it needs to be rebuilt
+}
+
+func newPhotoIndex() *photoIndex {
+	return &photoIndex{byName: make(map[string]PhotoInfo)}
+}
+
+// replace swaps the whole index content for photos.
+func (idx *photoIndex) replace(photos []PhotoInfo) {
+	byName := make(map[string]PhotoInfo, len(photos))
+	for _, photo := range photos {
+		byName[photo.Name] = photo
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.byName = byName
+	idx.sorted = nil
+}
+
+// put adds or replaces a single photo.
+func (idx *photoIndex) put(photo PhotoInfo) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.byName[photo.Name] = photo
+	idx.sorted = nil
+}
+
+// remove drops a photo from the index.
+func (idx *photoIndex) remove(name string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, ok := idx.byName[name]; ok {
+		delete(idx.byName, name)
+		idx.sorted = nil
+	}
+}
+
+// get returns the photo with the given name.
+func (idx *photoIndex) get(name string) (PhotoInfo, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	photo, ok := idx.byName[name]
+	return photo, ok
+}
+
+// list returns the photos matching filter sorted newest first. The returned
+// slice is owned by the caller.
+func (idx *photoIndex) list(filter PhotoFilter) []PhotoInfo {
+	sorted := idx.sortedPhotos()
+
+	var photos []PhotoInfo
+	for _, photo := range sorted {
+		if filter.Matches(photo) {
+			photos = append(photos, photo)
+		}
+	}
+	return photos
+}
+
+// count returns the number of indexed photos.
+func (idx *photoIndex) count() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.byName)
+}
+
+// uniqueValues returns the sorted non-empty values extracted from all photos.
+func (idx *photoIndex) uniqueValues(extractor func(PhotoInfo) string) []string {
+	return idx.uniqueMultiValues(func(p PhotoInfo) []string { return []string{extractor(p)} })
+}
+
+// uniqueMultiValues is like uniqueValues for fields holding several values per photo.
+func (idx *photoIndex) uniqueMultiValues(extractor func(PhotoInfo) []string) []string {
+	idx.mu.RLock()
+	valueSet := make(map[string]bool)
+	for _, photo := range idx.byName {
+		for _, value := range extractor(photo) {
+			if value != "" {
+				valueSet[value] = true
+			}
+		}
+	}
+	idx.mu.RUnlock()
+
+	values := make([]string, 0, len(valueSet))
+	for value := range valueSet {
+		values = append(values, value)
+	}
+	slices.Sort(values)
+	return values
+}
+
+// sortedPhotos returns the cached sort order, rebuilding it if necessary.
+// The returned slice must not be modified.
+func (idx *photoIndex) sortedPhotos() []PhotoInfo {
+	idx.mu.RLock()
+	sorted := idx.sorted
+	idx.mu.RUnlock()
+	if sorted != nil {
+		return sorted
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.sorted != nil {
+		return idx.sorted
+	}
+
+	sorted = make([]PhotoInfo, 0, len(idx.byName))
+	for _, photo := range idx.byName {
+		sorted = append(sorted, photo)
+	}
+	slices.SortFunc(sorted, comparePhotos)
+	idx.sorted = sorted
+	return sorted
+}
+
+// comparePhotos orders photos by photo time (newest first), falling back to
+// the upload time, and by name for equal times.
+func comparePhotos(a, b PhotoInfo) int {
+	timeA, timeB := sortTime(a), sortTime(b)
+	switch {
+	case timeA > timeB:
+		return -1
+	case timeA < timeB:
+		return 1
+	default:
+		return strings.Compare(a.Name, b.Name)
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPhotoIndex(t *testing.T) {
+	idx := newPhotoIndex()
+
+	now := time.Now()
+	idx.replace([]PhotoInfo{
+		{Name: "old.jpg", Uploader: "Alice", Event: "Birthday", Date: now, PhotoTime: now.Add(-48 * time.Hour)},
+		{Name: "new.jpg", Uploader: "Bob", Event: "Wedding", Date: now, PhotoTime: now.Add(24 * time.Hour)},
+	})
+	idx.put(PhotoInfo{Name: "middle.jpg", Uploader: "Alice", Date: now.Add(-12 * time.Hour)})
+
+	photos := idx.list(PhotoFilter{})
+	if len(photos) != 3 {
+		t.Fatalf("Expected 3 photos, got %d", len(photos))
+	}
+	if photos[0].Name != "new.jpg" || photos[1].Name != "middle.jpg" || photos[2].Name != "old.jpg" {
+		t.Errorf("Unexpected order: %s, %s, %s", photos[0].Name, photos[1].Name, photos[2].Name)
+	}
+
+	if filtered := idx.list(PhotoFilter{Uploader: "Alice"}); len(filtered) != 2 {
+		t.Errorf("Expected 2 photos for Alice, got %d", len(filtered))
+	}
+
+	events := idx.uniqueValues(func(p PhotoInfo) string { return p.Event })
+	if len(events) != 2 || events[0] != "Birthday" || events[1] != "Wedding" {
+		t.Errorf("Expected [Birthday Wedding], got %v", events)
+	}
+
+	idx.remove("new.jpg")
+	if idx.count() != 2 {
+		t.Errorf("Expected 2 photos after remove, got %d", idx.count())
+	}
+	if _, ok := idx.get("new.jpg"); ok {
+		t.Error("Expected removed photo to be gone")
+	}
+	if photos := idx.list(PhotoFilter{}); photos[0].Name != "middle.jpg" {
+		t.Errorf("Expected middle.jpg first after remove, got %s", photos[0].Name)
+	}
+}
+
+func TestSavePhotoUpdatesIndex(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	fileHeader := newTestFileHeader(t, "party.png", "image/png", testPNGBytes(t))
+	if _, err := service.SavePhoto(fileHeader, User{Username: "Alice"}, "Party"); err != nil {
+		t.Fatal(err)
+	}
+
+	photos, err := service.QueryPhotos(PhotoFilter{Event: "Party"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 1 || photos[0].Name != "party.png" || photos[0].Uploader != "Alice" {
+		t.Errorf("Expected saved photo in index, got %+v", photos)
+	}
+}
+
+func TestWatchUploadDir(t *testing.T) {
+	tempDir := t.TempDir()
+	uploadDir := filepath.Join(tempDir, "uploads")
+	service := newTestGalleryService(t, uploadDir, filepath.Join(tempDir, "metadata"))
+
+	if err := service.WatchUploadDir(); err != nil {
+		t.Fatal(err)
+	}
+
+	droppedPath := filepath.Join(uploadDir, "dropped.png")
+	if err := createTestPNG(droppedPath); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "dropped file to be indexed", func() bool {
+		_, ok := service.index.get("dropped.png")
+		return ok
+	})
+
+	if _, err := service.thumbnails.Stat("dropped.png"); err != nil {
+		t.Errorf("Expected thumbnail for dropped file, got %v", err)
+	}
+
+	if err := os.Remove(droppedPath); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "removed file to leave the index", func() bool {
+		_, ok := service.index.get("dropped.png")
+		return !ok
+	})
+}
+
+func TestSavePhotoDuringWatcherSync(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+	alice, err := service.CreateUser("alice", "alice-password", RoleContributor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The watcher sees the file while SavePhoto is still processing it
+	done := make(chan struct{})
+	synced := make(chan struct{})
+	go func() {
+		defer close(synced)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				service.syncUploadedFile("party.png")
+			}
+		}
+	}()
+
+	_, err = service.SavePhoto(newTestFileHeader(t, "party.png", "image/png", testPNGBytes(t)), alice, "Party")
+	close(done)
+	<-synced
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.syncUploadedFile("party.png")
+
+	stored, err := service.store.GetPhoto("party.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexed, _ := service.index.get("party.png")
+	for _, photo := range []PhotoInfo{stored, indexed} {
+		if photo.Uploader != "alice" || photo.Event != "Party" || photo.OwnerID != alice.ID {
+			t.Errorf("Expected upload metadata to survive the watcher, got %+v", photo)
+		}
+	}
+}
+
+// Helper function to poll until condition holds or fail after a timeout
+func waitFor(t *testing.T, description string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %s", description)
+}
+
+// Helper function to build a multipart file header as produced by ParseMultipartForm
+func newTestFileHeader(t *testing.T, filename, contentType string, data []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="photos"; filename="`+filename+`"`)
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+
+	return form.File["photos"][0]
+}
+
+// Helper function to get the bytes of a valid test PNG image
+func testPNGBytes(t *testing.T) []byte {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.png")
+	if err := createTestPNG(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
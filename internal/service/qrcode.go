@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultQRCodeSize = 512  // Default QR code PNG width and height in pixels
+	maxQRCodeSize     = 2048 // Largest QR code PNG that can be requested
+)
+
+// ErrInvalidQRCode is returned for QR code requests with invalid options.
+var ErrInvalidQRCode = errors.New("invalid QR code")
+
+// QRCodeFormat is the image format of a QR code.
+type QRCodeFormat string
+
+const (
+	// QRCodePNG renders a QR code as PNG image.
+	QRCodePNG QRCodeFormat = "png"
+	// QRCodeSVG renders a QR code as scalable SVG, e.g. for printing.
+	QRCodeSVG QRCodeFormat = "svg"
+)
+
+// ContentType returns the MIME type of the format.
+func (f QRCodeFormat) ContentType() string {
+	if f == QRCodeSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// WriteQRCode writes a QR code encoding content to w. size is the width and
+// height of PNG images in pixels, 0 for the default; SVG images scale freely.
+func WriteQRCode(w io.Writer, content string, format QRCodeFormat, size int) error {
+	if size == 0 {
+		size = defaultQRCodeSize
+	}
+	if size < 0 || size > maxQRCodeSize {
+		return fmt.Errorf("%w: size must be between 1 and %d pixels", ErrInvalidQRCode, maxQRCodeSize)
+	}
+
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidQRCode, err)
+	}
+
+	switch format {
+	case QRCodePNG:
+		return code.Write(size, w)
+	case QRCodeSVG:
+		_, err := io.WriteString(w, qrCodeSVG(code.Bitmap()))
+		return err
+	}
+	return fmt.Errorf("%w: unknown format %q", ErrInvalidQRCode, format)
+}
+
+// qrCodeSVG draws the modules of a QR code bitmap, including its quiet zone,
+// as one path with a unit square per dark module.
+func qrCodeSVG(bitmap [][]bool) string {
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	size := len(bitmap)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="%d" height="%d" fill="#fff"/><path d="%s" fill="#000"/></svg>`,
+		size, size, size, size, path.String())
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestWriteQRCode(t *testing.T) {
+	const link = "https://gallery.example.com/share/token?event=Table+5"
+
+	var image bytes.Buffer
+	if err := WriteQRCode(&image, link, QRCodePNG, 256); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(&image)
+	if err != nil {
+		t.Fatalf("Expected a PNG image: %v", err)
+	}
+	if bounds := decoded.Bounds(); bounds.Dx() != 256 || bounds.Dy() != 256 {
+		t.Errorf("Expected a 256x256 image, got %v", bounds)
+	}
+
+	image.Reset()
+	if err := WriteQRCode(&image, link, QRCodeSVG, 0); err != nil {
+		t.Fatal(err)
+	}
+	if svg := image.String(); !strings.HasPrefix(svg, "<svg ") || !strings.Contains(svg, "h1v1h-1z") {
+		t.Errorf("Expected an SVG image with modules, got %q", svg)
+	}
+
+	invalid := []struct {
+		format QRCodeFormat
+		size   int
+	}{
+		{"gif", 0},
+		{QRCodePNG, -1},
+		{QRCodePNG, maxQRCodeSize + 1},
+	}
+	for _, tt := range invalid {
+		if err := WriteQRCode(&image, link, tt.format, tt.size); !errors.Is(err, ErrInvalidQRCode) {
+			t.Errorf("Expected ErrInvalidQRCode for %s at %d pixels, got %v", tt.format, tt.size, err)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+// DefaultRenditionSizes are the sizes of the renditions created next to the
+// thumbnail, in pixels of their longer side.
+var DefaultRenditionSizes = []int{800, 1600, 2560}
+
+// ErrUnknownRenditionSize is returned by ServeRendition for sizes that are
+// not configured.
+var ErrUnknownRenditionSize = errors.New("unknown rendition size")
+
+// WithRenditionSizes creates renditions of the given sizes instead of
+// DefaultRenditionSizes. Sizes up to the thumbnail size are served by the
+// thumbnail and need no rendition.
+func WithRenditionSizes(sizes ...int) GalleryOption {
+	return func(s *GalleryService) {
+		s.renditionSizes = sizes
+	}
+}
+
+// WithRenditionStores stores the renditions of each size in the given blob
+// stores instead of METADATA_DIR/renditions/<size>. Sizes without store get
+// the default one.
+func WithRenditionStores(stores map[int]BlobStore) GalleryOption {
+	return func(s *GalleryService) {
+		s.renditions = stores
+	}
+}
+
+// initRenditions keeps the rendition sizes above the thumbnail size, in
+// ascending order, and creates the missing blob stores.
+func (s *GalleryService) initRenditions() error {
+	if s.renditionSizes == nil {
+		s.renditionSizes = DefaultRenditionSizes
+	}
+	s.renditionSizes = slices.DeleteFunc(slices.Clone(s.renditionSizes), func(size int) bool {
+		return size <= thumbnailSize
+	})
+	slices.Sort(s.renditionSizes)
+	s.renditionSizes = slices.Compact(s.renditionSizes)
+
+	stores := make(map[int]BlobStore, len(s.renditionSizes))
+	for _, size := range s.renditionSizes {
+		store := s.renditions[size]
+		if store == nil {
+			fileStore, err := NewFileBlobStore(filepath.Join(s.metadataDir, "renditions", strconv.Itoa(size)))
+			if err != nil {
+				return err
+			}
+			store = fileStore
+		}
+		stores[size] = store
+	}
+	s.renditions = stores
+	return nil
+}
+
+// RenditionSizes returns the sizes photos are served in, in ascending order:
+// the thumbnail size followed by the rendition sizes.
+func (s *GalleryService) RenditionSizes() []int {
+	return append([]int{thumbnailSize}, s.renditionSizes...)
+}
+
+// ServeRendition opens the rendition of a photo with the given size, the
+// thumbnail for the thumbnail size. Photos smaller than the size have no
+// rendition and return ErrBlobNotFound; callers serve the original instead.
+// The caller must close the reader.
+func (s *GalleryService) ServeRendition(size int, filename string) (io.ReadSeekCloser, BlobInfo, error) {
+	if size == thumbnailSize {
+		return s.thumbnails.Open(filename)
+	}
+	store, ok := s.renditions[size]
+	if !ok {
+		return nil, BlobInfo{}, ErrUnknownRenditionSize
+	}
+	return store.Open(filename)
+}
+
+// generateRenditions stores the renditions of a decoded photo smaller than
+// the photo, each scaled from the next larger one and shown with the EXIF
+// orientation, and its rotated display copy if enabled. Failures are logged,
+// the original is served in place of missing renditions.
+func (s *GalleryService) generateRenditions(img image.Image, format string, orientation int, filename string) {
+	if s.rotatedCopies && orientation != 1 && !needsConversion(filename) {
+		if err := s.storeRotatedCopy(img, orientation, filename); err != nil {
+			log.Printf("Failed to create rotated copy of %s: %v", filename, err)
+		}
+	}
+
+	longest := max(img.Bounds().Dx(), img.Bounds().Dy())
+	src := img
+	for _, size := range slices.Backward(s.renditionSizes) {
+		if size >= longest {
+			continue
+		}
+		width, height := fitWithin(orientedBounds(img.Bounds(), orientation), size)
+		rendition, scaled := resizeOriented(src, width, height, orientation, s.resampling)
+		buf, err := encodeImage(rendition, format)
+		if err != nil {
+			log.Printf("Failed to encode %dpx rendition of %s: %v", size, filename, err)
+			continue
+		}
+		if err := s.renditions[size].Put(filename, buf, int64(buf.Len())); err != nil {
+			log.Printf("Failed to store %dpx rendition of %s: %v", size, filename, err)
+			continue
+		}
+		src = scaled
+	}
+}
+
+// deleteRenditions removes all renditions of a photo.
+func (s *GalleryService) deleteRenditions(filename string) {
+	for size, store := range s.renditions {
+		if err := store.Delete(filename); err != nil {
+			log.Printf("Failed to remove %dpx rendition of %s: %v", size, filename, err)
+		}
+	}
+}
+
+// CleanupOrphanedRenditions removes renditions whose original is gone.
+func (s *GalleryService) CleanupOrphanedRenditions() {
+	for _, size := range s.renditionSizes {
+		s.cleanupOrphanedBlobs(s.renditions[size], fmt.Sprintf("%dpx rendition", size))
+	}
+}
+
+// GenerateMissingRenditions creates the renditions of photos stored before
+// renditions existed or their sizes were changed.
+func (s *GalleryService) GenerateMissingRenditions() {
+	files, err := s.photos.List()
+	if err != nil {
+		log.Printf("Failed to list photos for renditions: %v", err)
+		return
+	}
+
+	existing := make(map[int]map[string]bool, len(s.renditionSizes))
+	for _, size := range s.renditionSizes {
+		if existing[size], err = s.blobNames(s.renditions[size]); err != nil {
+			log.Printf("Failed to list %dpx renditions: %v", size, err)
+			return
+		}
+	}
+
+	generatedCount := 0
+	for _, file := range files {
+		if !s.isImageFile(file.Name) {
+			continue
+		}
+		missing := slices.ContainsFunc(s.renditionSizes, func(size int) bool {
+			return !existing[size][file.Name]
+		})
+		if !missing {
+			continue
+		}
+
+		// Photos smaller than a size never get its rendition, which only
+		// the image header tells
+		config, err := s.decodeConfig(file.Name)
+		if errors.Is(err, ErrBlobNotFound) {
+			continue // Converted photo without JPEG version
+		}
+		if err != nil {
+			log.Printf("Failed to read size of %s: %v", file.Name, err)
+			continue
+		}
+		longest := max(config.Width, config.Height)
+		missing = slices.ContainsFunc(s.renditionSizes, func(size int) bool {
+			return size < longest && !existing[size][file.Name]
+		})
+		if !missing {
+			continue
+		}
+
+		if err := s.generateRenditionsFromPhoto(file.Name); err != nil {
+			log.Printf("Failed to generate renditions for %s: %v", file.Name, err)
+			continue
+		}
+		generatedCount++
+	}
+
+	if generatedCount > 0 {
+		log.Printf("Generated renditions for %d existing photos", generatedCount)
+	}
+}
+
+// decodeConfig reads the dimensions of the decodable version of a photo.
+func (s *GalleryService) decodeConfig(filename string) (image.Config, error) {
+	photo, _, err := s.openDecodable(filename)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer photo.Close()
+
+	config, _, err := image.DecodeConfig(photo)
+	return config, err
+}
+
+// generateRenditionsFromPhoto creates the renditions of a stored photo.
+func (s *GalleryService) generateRenditionsFromPhoto(filename string) error {
+	photo, _, err := s.openDecodable(filename)
+	if err != nil {
+		return err
+	}
+	defer photo.Close()
+
+	orientation := readOrientation(photo)
+	if _, err := photo.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	img, format, err := image.Decode(photo)
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+	s.generateRenditions(img, format, orientation, filename)
+	return nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"image"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRenditions(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"),
+		WithRenditionSizes(1600, 300, 800, 2560, 100))
+
+	if got, want := service.RenditionSizes(), []int{300, 800, 1600, 2560}; !slices.Equal(got, want) {
+		t.Fatalf("Expected sizes %v, got %v", want, got)
+	}
+
+	photo := encodeTestImage(t, createPatternImage(2000, 1500, false), "jpeg")
+	if _, err := service.SavePhoto(newTestFileHeader(t, "large.jpg", "image/jpeg", photo), User{Username: "Alice"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	checkRenditions := func(want map[int]image.Point) {
+		t.Helper()
+		for size, dimensions := range want {
+			rendition, _, err := service.ServeRendition(size, "large.jpg")
+			if dimensions == (image.Point{}) {
+				if !errors.Is(err, ErrBlobNotFound) {
+					t.Errorf("Expected no %dpx rendition, got %v", size, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("Expected %dpx rendition, got %v", size, err)
+				continue
+			}
+			config, format, err := image.DecodeConfig(rendition)
+			rendition.Close()
+			if err != nil || format != "jpeg" || config.Width != dimensions.X || config.Height != dimensions.Y {
+				t.Errorf("Expected %v JPEG for %dpx, got %dx%d %s (%v)", dimensions, size, config.Width, config.Height, format, err)
+			}
+		}
+	}
+	// No rendition is larger than the photo
+	checkRenditions(map[int]image.Point{300: {300, 225}, 800: {800, 600}, 1600: {1600, 1200}, 2560: {}})
+
+	if _, _, err := service.ServeRendition(500, "large.jpg"); !errors.Is(err, ErrUnknownRenditionSize) {
+		t.Errorf("Expected ErrUnknownRenditionSize, got %v", err)
+	}
+
+	// Renditions are removed with the photo and created again on restore
+	if err := service.DeletePhoto("large.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	checkRenditions(map[int]image.Point{800: {}, 1600: {}})
+	if err := service.RestorePhoto("large.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	checkRenditions(map[int]image.Point{800: {800, 600}, 1600: {1600, 1200}})
+
+	// Missing renditions are created on startup
+	if err := service.renditions[800].Delete("large.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	service.GenerateMissingRenditions()
+	checkRenditions(map[int]image.Point{800: {800, 600}})
+
+	// and orphaned ones removed
+	if err := service.photos.Delete("large.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	service.CleanupOrphanedRenditions()
+	checkRenditions(map[int]image.Point{800: {}, 1600: {}})
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// Resampling is the filter used to scale photos down to thumbnails, from
+// fastest to sharpest.
+type Resampling string
+
+const (
+	// ResampleNearest picks the nearest pixel. Fast, but aliased.
+	ResampleNearest Resampling = "nearest"
+	// ResampleBilinear interpolates linearly between pixels.
+	ResampleBilinear Resampling = "bilinear"
+	// ResampleCatmullRom uses the Catmull-Rom cubic kernel, the default.
+	ResampleCatmullRom Resampling = "catmull-rom"
+	// ResampleLanczos uses the three-lobed Lanczos kernel.
+	ResampleLanczos Resampling = "lanczos"
+)
+
+// lanczos3 is the Lanczos kernel with a support of 3 pixels.
+var lanczos3 = &draw.Kernel{Support: 3, At: func(t float64) float64 {
+	if t == 0 {
+		return 1
+	}
+	if t >= 3 {
+		return 0
+	}
+	t *= math.Pi
+	return 3 * math.Sin(t) * math.Sin(t/3) / (t * t)
+}}
+
+// Valid reports whether r is one of the resampling filters.
+func (r Resampling) Valid() bool {
+	return r.scaler() != nil
+}
+
+func (r Resampling) scaler() draw.Scaler {
+	switch r {
+	case ResampleNearest:
+		return draw.NearestNeighbor
+	case ResampleBilinear:
+		return draw.BiLinear
+	case ResampleCatmullRom:
+		return draw.CatmullRom
+	case ResampleLanczos:
+		return lanczos3
+	}
+	return nil
+}
+
+// WithResampling scales thumbnails with the given filter instead of
+// ResampleCatmullRom.
+func WithResampling(resampling Resampling) GalleryOption {
+	return func(s *GalleryService) {
+		s.resampling = resampling
+	}
+}
+
+// resizeImage scales src to width x height with the resampling filter.
+//
+// Kernel filters look at every source pixel under the kernel, which for a
+// 24MP photo and a 300px thumbnail is a lot of work. YCbCr images, i.e.
+// decoded JPEGs, are therefore first shrunk by box-averaging their planes to
+// at least twice the target size, which is cheap and anti-aliases as well.
+// Images less than four times the target size are halved.
+func resizeImage(src image.Image, width, height int, resampling Resampling) image.Image {
+	scaler := resampling.scaler()
+	if scaler == nil {
+		scaler = draw.CatmullRom
+	}
+
+	width, height = max(width, 1), max(height, 1)
+	if ycc, ok := src.(*image.YCbCr); ok && resampling != ResampleNearest {
+		// Images less than twice the target size on either side are not shrunk
+		ratio := min(ycc.Rect.Dx()/width, ycc.Rect.Dy()/height)
+		if ratio >= 2 && ycc.Rect.Min == (image.Point{}) {
+			src = shrinkYCbCr(ycc, max(ratio/2, 2))
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	scaler.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// shrinkYCbCr scales src, which must start at the origin, down by an integer
+// factor, averaging each factor x factor block of its planes. The subsample
+// ratio is kept, so the chroma planes shrink by the same factor.
+func shrinkYCbCr(src *image.YCbCr, factor int) *image.YCbCr {
+	width, height := src.Rect.Dx()/factor, src.Rect.Dy()/factor
+	dst := image.NewYCbCr(image.Rect(0, 0, width, height), src.SubsampleRatio)
+
+	boxShrink(dst.Y, dst.YStride, width, height, src.Y, src.YStride, src.Rect.Dx(), src.Rect.Dy(), factor)
+	srcCW, srcCH := chromaSize(src.Rect, src.SubsampleRatio)
+	dstCW, dstCH := chromaSize(dst.Rect, dst.SubsampleRatio)
+	boxShrink(dst.Cb, dst.CStride, dstCW, dstCH, src.Cb, src.CStride, srcCW, srcCH, factor)
+	boxShrink(dst.Cr, dst.CStride, dstCW, dstCH, src.Cr, src.CStride, srcCW, srcCH, factor)
+	return dst
+}
+
+// boxShrink fills the dstW x dstH plane dst with the averages of the
+// factor x factor blocks of the srcW x srcH plane src. Blocks at the edges
+// are cut off at the end of src.
+func boxShrink(dst []uint8, dstStride, dstW, dstH int, src []uint8, srcStride, srcW, srcH, factor int) {
+	sums := make([]int, dstW)
+	counts := make([]int, dstW)
+	for dy := range dstH {
+		clear(sums)
+		clear(counts)
+		for sy := dy * factor; sy < min((dy+1)*factor, srcH); sy++ {
+			row := src[sy*srcStride : sy*srcStride+srcW]
+			for dx := range dstW {
+				for sx := dx * factor; sx < min((dx+1)*factor, srcW); sx++ {
+					sums[dx] += int(row[sx])
+				}
+				counts[dx] += min((dx+1)*factor, srcW) - dx*factor
+			}
+		}
+		out := dst[dy*dstStride : dy*dstStride+dstW]
+		for dx := range out {
+			if counts[dx] > 0 {
+				out[dx] = uint8((sums[dx] + counts[dx]/2) / counts[dx]) // #nosec G115 - the average of uint8 values fits
+			}
+		}
+	}
+}
+
+// chromaSize returns the size of the chroma planes of a YCbCr image with
+// bounds r starting at the origin, like image.NewYCbCr allocates them.
+func chromaSize(r image.Rectangle, ratio image.YCbCrSubsampleRatio) (int, int) {
+	w, h := r.Dx(), r.Dy()
+	switch ratio {
+	case image.YCbCrSubsampleRatio422:
+		return (w + 1) / 2, h
+	case image.YCbCrSubsampleRatio420:
+		return (w + 1) / 2, (h + 1) / 2
+	case image.YCbCrSubsampleRatio440:
+		return w, (h + 1) / 2
+	case image.YCbCrSubsampleRatio411:
+		return (w + 3) / 4, h
+	case image.YCbCrSubsampleRatio410:
+		return (w + 3) / 4, (h + 1) / 2
+	}
+	return w, h
+}
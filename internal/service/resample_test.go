@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/draw"
+)
+
+// newTestYCbCr returns a YCbCr image with a 1px checkerboard in luma and
+// gradients in chroma.
+func newTestYCbCr(width, height int, ratio image.YCbCrSubsampleRatio) *image.YCbCr {
+	img := image.NewYCbCr(image.Rect(0, 0, width, height), ratio)
+	for y := range height {
+		for x := range width {
+			img.Y[img.YOffset(x, y)] = uint8(255 * ((x + y) % 2)) // #nosec G115 - 0 or 255
+		}
+	}
+	for i := range img.Cb {
+		img.Cb[i] = uint8(i % 256) // #nosec G115 - modulo 256
+		img.Cr[i] = 128
+	}
+	return img
+}
+
+func TestResizeImage(t *testing.T) {
+	ratios := []image.YCbCrSubsampleRatio{
+		image.YCbCrSubsampleRatio444, image.YCbCrSubsampleRatio422, image.YCbCrSubsampleRatio420,
+		image.YCbCrSubsampleRatio440, image.YCbCrSubsampleRatio411, image.YCbCrSubsampleRatio410,
+	}
+	for _, ratio := range ratios {
+		// Odd sizes leave partial blocks at the edges
+		src := newTestYCbCr(1201, 799, ratio)
+		shrunk := shrinkYCbCr(src, 4)
+		if shrunk.Rect != image.Rect(0, 0, 300, 199) || shrunk.SubsampleRatio != ratio {
+			t.Fatalf("%v: unexpected shrunk image %v %v", ratio, shrunk.Rect, shrunk.SubsampleRatio)
+		}
+		// Averaging a checkerboard gives gray
+		for _, p := range []image.Point{{0, 0}, {150, 100}, {299, 198}} {
+			if y := shrunk.Y[shrunk.YOffset(p.X, p.Y)]; y < 126 || y > 129 {
+				t.Errorf("%v: expected gray at %v, got %d", ratio, p, y)
+			}
+		}
+		if cr := shrunk.Cr[shrunk.COffset(299, 198)]; cr != 128 {
+			t.Errorf("%v: expected unchanged chroma, got %d", ratio, cr)
+		}
+	}
+
+	src := newTestYCbCr(1200, 800, image.YCbCrSubsampleRatio420)
+	for _, resampling := range []Resampling{ResampleNearest, ResampleBilinear, ResampleCatmullRom, ResampleLanczos} {
+		dst := resizeImage(src, 300, 200, resampling)
+		if dst.Bounds() != image.Rect(0, 0, 300, 200) {
+			t.Fatalf("%s: unexpected bounds %v", resampling, dst.Bounds())
+		}
+		// Only nearest neighbour sampling keeps the checkerboard's extremes
+		gray := color.GrayModel.Convert(dst.At(150, 100)).(color.Gray).Y
+		if aliased := gray < 64 || gray > 192; aliased != (resampling == ResampleNearest) {
+			t.Errorf("%s: unexpected gray %d in the middle of the thumbnail", resampling, gray)
+		}
+	}
+
+	// The fast path gives about the same result as resampling the full image
+	photo := createPatternImage(2400, 1600, false)
+	ycc := image.NewYCbCr(photo.Bounds(), image.YCbCrSubsampleRatio444)
+	for y := range 1600 {
+		for x := range 2400 {
+			r, g, b, _ := photo.At(x, y).RGBA()
+			yy, cb, cr := color.RGBToYCbCr(uint8(r>>8), uint8(g>>8), uint8(b>>8)) // #nosec G115 - 8-bit channels
+			ycc.Y[ycc.YOffset(x, y)], ycc.Cb[ycc.COffset(x, y)], ycc.Cr[ycc.COffset(x, y)] = yy, cb, cr
+		}
+	}
+	fast, full := resizeImage(ycc, 300, 200, ResampleCatmullRom), resizeImage(photo, 300, 200, ResampleCatmullRom)
+	if diff := hammingDistance(dHash(fast), dHash(full)); diff > 2 {
+		t.Errorf("Expected fast path to look like the full resampling, dHash distance %d", diff)
+	}
+}
+
+func TestResizeThinImage(t *testing.T) {
+	// Panorama strips scale to a side of less than a pixel
+	for _, size := range []image.Point{{1, 4000}, {4000, 1}, {3, 4000}, {4000, 3}} {
+		src := newTestYCbCr(size.X, size.Y, image.YCbCrSubsampleRatio420)
+		width, height := fitWithin(src.Bounds(), thumbnailSize)
+		if width < 1 || height < 1 || max(width, height) != thumbnailSize {
+			t.Errorf("%v: unexpected thumbnail size %dx%d", size, width, height)
+			continue
+		}
+		for _, resampling := range []Resampling{ResampleNearest, ResampleCatmullRom} {
+			if dst := resizeImage(src, width, height, resampling); dst.Bounds() != image.Rect(0, 0, width, height) {
+				t.Errorf("%v %s: unexpected bounds %v", size, resampling, dst.Bounds())
+			}
+		}
+	}
+
+	// Sizes below a pixel are rounded up instead of dividing by zero
+	if dst := resizeImage(newTestYCbCr(10, 10, image.YCbCrSubsampleRatio420), 0, 5, ResampleCatmullRom); dst.Bounds() != image.Rect(0, 0, 1, 5) {
+		t.Errorf("Unexpected bounds %v", dst.Bounds())
+	}
+}
+
+// resizeNearestAtSet is the per-pixel nearest neighbour scaling thumbnails
+// used before, for comparison.
+func resizeNearestAtSet(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			dst.Set(x, y, src.At(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height))
+		}
+	}
+	return dst
+}
+
+// BenchmarkResizeImage scales a 24MP photo to a thumbnail, as decoded JPEG
+// (YCbCr) and as RGBA. "at-set" is the per-pixel scaling used before and
+// "no-fast-path" resamples the full YCbCr image.
+func BenchmarkResizeImage(b *testing.B) {
+	sources := []struct {
+		name string
+		img  image.Image
+	}{
+		{"YCbCr", newTestYCbCr(6000, 4000, image.YCbCrSubsampleRatio420)},
+		{"RGBA", image.NewRGBA(image.Rect(0, 0, 6000, 4000))},
+	}
+	for _, src := range sources {
+		b.Run(src.name+"/at-set", func(b *testing.B) {
+			for b.Loop() {
+				resizeNearestAtSet(src.img, 300, 200)
+			}
+		})
+		for _, resampling := range []Resampling{ResampleNearest, ResampleBilinear, ResampleCatmullRom, ResampleLanczos} {
+			b.Run(fmt.Sprintf("%s/%s", src.name, resampling), func(b *testing.B) {
+				for b.Loop() {
+					resizeImage(src.img, 300, 200, resampling)
+				}
+			})
+		}
+	}
+	b.Run("YCbCr/catmull-rom/no-fast-path", func(b *testing.B) {
+		src := sources[0].img
+		for b.Loop() {
+			dst := image.NewRGBA(image.Rect(0, 0, 300, 200))
+			draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+		}
+	})
+}
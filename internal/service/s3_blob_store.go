@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config holds the connection settings for an S3-compatible object store.
+type S3Config struct {
+	Endpoint  string // host[:port] without scheme, e.g. "minio:9000"
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Region    string
+	UseSSL    bool
+	// Transport overrides the HTTP transport, mainly for tests
+	Transport http.RoundTripper
+}
+
+// S3BlobStore is a BlobStore keeping blobs as objects below a key prefix
+// in an S3-compatible bucket.
+type S3BlobStore struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3BlobStore connects to the bucket described by cfg. All blobs are stored
+// below prefix, so several stores can share one bucket.
+func NewS3BlobStore(cfg S3Config, prefix string) (*S3BlobStore, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 endpoint and bucket are required")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       cfg.UseSSL,
+		Region:       region,
+		BucketLookup: minio.BucketLookupPath,
+		Transport:    cfg.Transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &S3BlobStore{
+		client: client,
+		bucket: cfg.Bucket,
+		prefix: prefix,
+	}, nil
+}
+
+func (s *S3BlobStore) key(name string) string {
+	return s.prefix + path.Base(name)
+}
+
+func (s *S3BlobStore) Put(name string, r io.Reader, size int64) error {
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	_, err := s.client.PutObject(context.Background(), s.bucket, s.key(name), r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) Open(name string) (io.ReadSeekCloser, BlobInfo, error) {
+	object, err := s.client.GetObject(context.Background(), s.bucket, s.key(name), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, BlobInfo{}, s.translateError(err)
+	}
+
+	// GetObject is lazy, Stat performs the request and reports missing objects
+	objectInfo, err := object.Stat()
+	if err != nil {
+		_ = object.Close()
+		return nil, BlobInfo{}, s.translateError(err)
+	}
+	return object, s.blobInfo(objectInfo), nil
+}
+
+func (s *S3BlobStore) Stat(name string) (BlobInfo, error) {
+	objectInfo, err := s.client.StatObject(context.Background(), s.bucket, s.key(name), minio.StatObjectOptions{})
+	if err != nil {
+		return BlobInfo{}, s.translateError(err)
+	}
+	return s.blobInfo(objectInfo), nil
+}
+
+func (s *S3BlobStore) Delete(name string) error {
+	err := s.client.RemoveObject(context.Background(), s.bucket, s.key(name), minio.RemoveObjectOptions{})
+	if err != nil && s.translateError(err) != ErrBlobNotFound {
+		return fmt.Errorf("failed to delete %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) List() ([]BlobInfo, error) {
+	var blobs []BlobInfo
+	for objectInfo := range s.client.ListObjects(context.Background(), s.bucket, minio.ListObjectsOptions{
+		Prefix:    s.prefix,
+		Recursive: true,
+	}) {
+		if objectInfo.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", objectInfo.Err)
+		}
+		name := strings.TrimPrefix(objectInfo.Key, s.prefix)
+		if name == "" || strings.Contains(name, "/") {
+			continue // Belongs to a nested prefix
+		}
+		blobs = append(blobs, s.blobInfo(objectInfo))
+	}
+	return blobs, nil
+}
+
+func (s *S3BlobStore) blobInfo(objectInfo minio.ObjectInfo) BlobInfo {
+	return BlobInfo{
+		Name:    strings.TrimPrefix(objectInfo.Key, s.prefix),
+		Size:    objectInfo.Size,
+		ModTime: objectInfo.LastModified,
+	}
+}
+
+func (s *S3BlobStore) translateError(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NotFound":
+		return ErrBlobNotFound
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testBucket = "gallery"
+
+// fakeS3 is a minimal in-memory S3 server implementing the requests used by S3BlobStore.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	modTime time.Time
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != testBucket {
+		f.writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		f.list(w, r.URL.Query())
+	case r.Method == http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		f.objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		http.ServeContent(w, r, key, f.modTime, bytes.NewReader(data))
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unsupported", http.StatusNotImplemented)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, query url.Values) {
+	type content struct {
+		Key          string
+		Size         int64
+		LastModified string
+		ETag         string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []content
+	}{Name: testBucket, Prefix: query.Get("prefix"), MaxKeys: 1000}
+
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, result.Prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		result.Contents = append(result.Contents, content{
+			Key:          key,
+			Size:         int64(len(f.objects[key])),
+			LastModified: f.modTime.Format(time.RFC3339),
+			ETag:         `"etag"`,
+		})
+	}
+	result.KeyCount = len(result.Contents)
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeS3) writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, "<Error><Code>"+code+"</Code><Message>"+code+"</Message></Error>")
+}
+
+// Helper function to create an S3BlobStore backed by a fake S3 server
+func newTestS3BlobStore(t *testing.T, prefix string) *S3BlobStore {
+	t.Helper()
+
+	return newTestS3BlobStoreOn(t, newTestS3Server(t), prefix)
+}
+
+func newTestS3Server(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewTLSServer(&fakeS3{
+		objects: make(map[string][]byte),
+		modTime: time.Now().UTC().Truncate(time.Second),
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestS3BlobStoreOn(t *testing.T, server *httptest.Server, prefix string) *S3BlobStore {
+	t.Helper()
+
+	store, err := NewS3BlobStore(S3Config{
+		Endpoint:  strings.TrimPrefix(server.URL, "https://"),
+		Bucket:    testBucket,
+		AccessKey: "access",
+		SecretKey: "secret",
+		UseSSL:    true,
+		Transport: server.Client().Transport,
+	}, prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestS3BlobStore(t *testing.T) {
+	testBlobStore(t, newTestS3BlobStore(t, "photos"))
+}
+
+func TestS3BlobStorePrefixesAreSeparate(t *testing.T) {
+	server := newTestS3Server(t)
+	photos := newTestS3BlobStoreOn(t, server, "photos")
+	thumbnails := newTestS3BlobStoreOn(t, server, "thumbnails")
+
+	if err := photos.Put("a.jpg", bytes.NewReader([]byte("photo")), 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := thumbnails.Stat("a.jpg"); err != ErrBlobNotFound {
+		t.Errorf("Expected photo to be invisible to the thumbnail store, got %v", err)
+	}
+	blobs, err := thumbnails.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 0 {
+		t.Errorf("Expected no thumbnails, got %v", blobs)
+	}
+}
+
+func TestGalleryServiceWithS3BlobStores(t *testing.T) {
+	server := newTestS3Server(t)
+	tempDir := t.TempDir()
+
+	service, err := NewGalleryService(
+		filepath.Join(tempDir, "uploads"),
+		filepath.Join(tempDir, "metadata"),
+		WithBlobStores(newTestS3BlobStoreOn(t, server, "photos"), newTestS3BlobStoreOn(t, server, "thumbnails")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { service.Close() })
+
+	fileHeader := newTestFileHeader(t, "remote.png", "image/png", testPNGBytes(t))
+	if _, err := service.SavePhoto(fileHeader, User{Username: "Alice"}, "Party"); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, _, err := service.ServeThumbnail("remote.png")
+	if err != nil {
+		t.Fatalf("Expected thumbnail in S3, got %v", err)
+	}
+	reader.Close()
+
+	var buf bytes.Buffer
+	if err := service.CreateZipArchive([]PhotoInfo{{Name: "remote.png"}}, PhotoFormatOriginal, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Expected ZIP archive with the photo from S3")
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Security schemes of the API. Their scopes are described with the schemes
+// in api/openapi.yaml.
+const (
+	// SchemeSession is a session cookie of a user, guest or event password
+	// login. Its scope is the minimum role of the user.
+	SchemeSession = "sessionAuth"
+	// SchemeBearer is an API token. Its scope is the minimum role the scope
+	// of the token grants, see TokenScope.Role.
+	SchemeBearer = "bearerAuth"
+	// SchemeShare is a share link, opened as session or passed as "share"
+	// query parameter. Its scope is one of ShareScopeBrowse and ShareScopeUpload.
+	SchemeShare = "shareAuth"
+)
+
+const (
+	// ShareScopeBrowse requires a share link that shows photos.
+	ShareScopeBrowse = "browse"
+	// ShareScopeUpload requires a share link that allows uploads.
+	ShareScopeUpload = "upload"
+)
+
+var (
+	// ErrUnauthenticated is returned by Authorize for requests that are not logged in.
+	ErrUnauthenticated = errors.New("not authenticated")
+	// ErrForbidden is returned by Authorize for logged-in requests that meet
+	// none of the security requirements.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// SecurityRequirement maps security schemes to their required scopes, like a
+// security requirement of an OpenAPI operation. A request meets it if it
+// satisfies all of its schemes; an empty requirement is met by every request.
+type SecurityRequirement map[string][]string
+
+// Authorize checks that the request meets at least one of the requirements.
+// Without requirements every request is allowed. Unknown schemes and scopes
+// are never satisfied.
+func (a *AuthService) Authorize(r *http.Request, requirements []SecurityRequirement) error {
+	if len(requirements) == 0 {
+		return nil
+	}
+	for _, requirement := range requirements {
+		if len(requirement) == 0 {
+			return nil
+		}
+	}
+
+	user, share, ok := a.identify(r)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	bearer := a.IsBearer(r)
+	for _, requirement := range requirements {
+		if satisfies(requirement, user, share, bearer) {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+func satisfies(requirement SecurityRequirement, user User, share *Share, bearer bool) bool {
+	for scheme, scopes := range requirement {
+		var ok bool
+		switch scheme {
+		case SchemeSession:
+			ok = !bearer && share == nil && hasRoles(user, scopes)
+		case SchemeBearer:
+			ok = bearer && hasRoles(user, scopes)
+		case SchemeShare:
+			ok = share != nil && share.allows(scopes)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hasRoles reports whether user has at least each of the roles.
+func hasRoles(user User, roles []string) bool {
+	for _, role := range roles {
+		if !Role(role).Valid() || !user.HasRole(Role(role)) {
+			return false
+		}
+	}
+	return true
+}
+
+// allows reports whether the share link grants each of the share scopes.
+func (s Share) allows(scopes []string) bool {
+	for _, scope := range scopes {
+		switch scope {
+		case ShareScopeBrowse:
+			if s.UploadOnly() {
+				return false
+			}
+		case ShareScopeUpload:
+			if !s.AllowUpload {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
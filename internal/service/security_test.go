@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	gallery, _, _ := newTestServiceWithSecretEvent(t)
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!",
+		WithAccounts(gallery), WithShares(gallery), WithAPITokens(gallery))
+
+	guest, bob := httptest.NewRecorder(), httptest.NewRecorder()
+	if !service.Login(guest, httptest.NewRequest("POST", "/login", http.NoBody), "password") ||
+		!service.LoginUser(bob, httptest.NewRequest("POST", "/login", http.NoBody), "bob", "bob-password") {
+		t.Fatal("Expected logins to succeed")
+	}
+
+	bearer := func(scope TokenScope) *http.Request {
+		_, value, err := gallery.CreateAPIToken("Script", scope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest("GET", "/", http.NoBody)
+		r.Header.Set("Authorization", "Bearer "+value)
+		return r
+	}
+	shared := func(share Share) *http.Request {
+		share, err := gallery.CreateShare(share)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return httptest.NewRequest("GET", "/?share="+service.ShareToken(share), http.NoBody)
+	}
+
+	browse := []SecurityRequirement{
+		{SchemeSession: {"viewer"}}, {SchemeBearer: {"viewer"}}, {SchemeShare: {ShareScopeBrowse}},
+	}
+	upload := []SecurityRequirement{
+		{SchemeSession: {"contributor"}}, {SchemeBearer: {"contributor"}}, {SchemeShare: {ShareScopeUpload}},
+	}
+	admin := []SecurityRequirement{{SchemeSession: {"admin"}}, {SchemeBearer: {"admin"}}}
+	gallerySchemes := []SecurityRequirement{{SchemeSession: {}}, {SchemeBearer: {}}, {SchemeShare: {}}}
+
+	requests := []struct {
+		name string
+		r    *http.Request
+		// expected errors for browse, upload, admin and gallery requirements
+		want [4]error
+	}{
+		{"anonymous", httptest.NewRequest("GET", "/", http.NoBody),
+			[4]error{ErrUnauthenticated, ErrUnauthenticated, ErrUnauthenticated, ErrUnauthenticated}},
+		{"guest", withCookies(guest), [4]error{nil, ErrForbidden, ErrForbidden, nil}},
+		{"contributor", withCookies(bob), [4]error{nil, nil, ErrForbidden, nil}},
+		{"read token", bearer(TokenScopeRead), [4]error{nil, ErrForbidden, ErrForbidden, nil}},
+		{"upload token", bearer(TokenScopeUpload), [4]error{nil, nil, ErrForbidden, nil}},
+		{"admin token", bearer(TokenScopeAdmin), [4]error{nil, nil, nil, nil}},
+		{"photo share", shared(Share{Photo: "public.png", Label: "Aunt May"}), [4]error{nil, ErrForbidden, ErrForbidden, nil}},
+		{"upload share", shared(Share{Kind: ShareKindUpload, Label: "Table 5"}), [4]error{ErrForbidden, nil, ErrForbidden, nil}},
+	}
+	for _, tt := range requests {
+		for i, requirements := range [][]SecurityRequirement{browse, upload, admin, gallerySchemes} {
+			if err := service.Authorize(tt.r, requirements); !errors.Is(err, tt.want[i]) {
+				t.Errorf("%s, requirements %d: expected %v, got %v", tt.name, i, tt.want[i], err)
+			}
+		}
+	}
+
+	// Public operations
+	anonymous := httptest.NewRequest("GET", "/", http.NoBody)
+	for _, requirements := range [][]SecurityRequirement{nil, {{}}, {{SchemeSession: {}}, {}}} {
+		if err := service.Authorize(anonymous, requirements); err != nil {
+			t.Errorf("Expected %v to allow anonymous requests, got %v", requirements, err)
+		}
+	}
+
+	// Unknown schemes and scopes are never satisfied
+	unknown := []struct {
+		r           *http.Request
+		requirement SecurityRequirement
+	}{
+		{withCookies(bob), SecurityRequirement{"basicAuth": {}}},
+		{withCookies(bob), SecurityRequirement{SchemeSession: {"owner"}}},
+		{shared(Share{Photo: "public.png", Label: "Uncle Ben"}), SecurityRequirement{SchemeShare: {"edit"}}},
+	}
+	for _, tt := range unknown {
+		if err := service.Authorize(tt.r, []SecurityRequirement{tt.requirement}); !errors.Is(err, ErrForbidden) {
+			t.Errorf("Expected %v to be forbidden, got %v", tt.requirement, err)
+		}
+	}
+}
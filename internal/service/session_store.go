@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/sessions"
+)
+
+const (
+	sessionTokenLength   = 32          // Length of session tokens in bytes
+	sessionTouchInterval = time.Minute // How often the last-seen time of a session is updated
+)
+
+// serverSessionStore is a sessions.Store that keeps the session values in
+// Sessions. The cookie only holds a random token, so sessions can be listed
+// and revoked.
+type serverSessionStore struct {
+	sessions Sessions
+	options  *sessions.Options
+	clientIP func(*http.Request) string
+}
+
+// Get returns the session of the request, cached for the duration of the request.
+func (s *serverSessionStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New loads the session of the request's cookie. Unknown, expired and
+// revoked sessions yield a new, empty session.
+func (s *serverSessionStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	options := *s.options
+	session.Options = &options
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	stored, err := s.sessions.LookupSession(cookie.Value)
+	if errors.Is(err, ErrSessionNotFound) {
+		return session, nil
+	}
+	if err != nil {
+		return session, err
+	}
+	if err := gob.NewDecoder(bytes.NewReader(stored.Data)).Decode(&session.Values); err != nil {
+		return session, fmt.Errorf("failed to decode session %d: %w", stored.ID, err)
+	}
+	session.ID = cookie.Value
+	session.IsNew = false
+
+	ip, userAgent := s.clientIP(r), r.UserAgent()
+	if time.Since(stored.LastSeen) >= sessionTouchInterval || ip != stored.LastIP || userAgent != stored.UserAgent {
+		if err := s.sessions.TouchSession(cookie.Value, ip, userAgent); err != nil {
+			log.Printf("Failed to update session %d: %v", stored.ID, err)
+		}
+	}
+	return session, nil
+}
+
+// Save stores the session values and sets the session cookie. A session
+// without ID gets a new token, replacing the one of the request's cookie, so
+// a token known before logging in cannot be used afterwards. A negative
+// MaxAge ends the session.
+func (s *serverSessionStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			if err := s.sessions.EndSession(session.ID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+				return err
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(session.Values); err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	expires := time.Now().Add(time.Duration(session.Options.MaxAge) * time.Second)
+
+	if session.ID != "" {
+		if err := s.sessions.UpdateSession(session.ID, data.Bytes(), expires); err != nil {
+			return err
+		}
+	} else {
+		if cookie, err := r.Cookie(session.Name()); err == nil {
+			if err := s.sessions.EndSession(cookie.Value); err != nil && !errors.Is(err, ErrSessionNotFound) {
+				return err
+			}
+		}
+
+		token := make([]byte, sessionTokenLength)
+		if _, err := rand.Read(token); err != nil {
+			return fmt.Errorf("failed to generate session token: %w", err)
+		}
+		session.ID = base64.RawURLEncoding.EncodeToString(token)
+
+		kind, _ := session.Values["kind"].(string)
+		userID, _ := session.Values["user_id"].(int64)
+		shareID, _ := session.Values["share_id"].(int64)
+		if _, err := s.sessions.CreateSession(session.ID, Session{
+			Kind:      SessionKind(kind),
+			UserID:    userID,
+			ShareID:   shareID,
+			Data:      data.Bytes(),
+			LastIP:    s.clientIP(r),
+			UserAgent: r.UserAgent(),
+			Expires:   expires,
+		}); err != nil {
+			session.ID = ""
+			return err
+		}
+	}
+
+	http.SetCookie(w, sessions.NewCookie(session.Name(), session.ID, session.Options))
+	return nil
+}
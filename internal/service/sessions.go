@@ -0,0 +1,79 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// SessionKind is how a session logged in.
+type SessionKind string
+
+const (
+	// SessionKindUser is logged in with a user account.
+	SessionKindUser SessionKind = "user"
+	// SessionKindGuest is logged in with the shared gallery password.
+	SessionKindGuest SessionKind = "guest"
+	// SessionKindEvent is logged in with an event password.
+	SessionKindEvent SessionKind = "event"
+	// SessionKindShare is logged in with a share link.
+	SessionKindShare SessionKind = "share"
+)
+
+// Session is a login session stored server-side, see WithSessions. Only a
+// random token is kept in the session cookie; the store knows its hash.
+type Session struct {
+	ID        int64       `json:"id"`
+	Kind      SessionKind `json:"kind"`
+	UserID    int64       `json:"user_id,omitempty"`  // account of user sessions
+	ShareID   int64       `json:"share_id,omitempty"` // share link of share sessions
+	Name      string      `json:"name"`               // username or share link label
+	Data      []byte      `json:"-"`                  // encoded session values
+	LastIP    string      `json:"last_ip"`
+	UserAgent string      `json:"user_agent"`
+	Created   time.Time   `json:"created"`
+	LastSeen  time.Time   `json:"last_seen"`
+	Expires   time.Time   `json:"expires"`
+}
+
+// hashToken returns the hash a session or API token is stored as, so the
+// tokens cannot be taken from the database.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateSession stores a new session with the given token.
+func (s *GalleryService) CreateSession(token string, session Session) (Session, error) {
+	return s.store.CreateSession(hashToken(token), session)
+}
+
+// LookupSession returns the unexpired session with the given token or ErrSessionNotFound.
+func (s *GalleryService) LookupSession(token string) (Session, error) {
+	return s.store.SessionByToken(hashToken(token))
+}
+
+// UpdateSession replaces the values and expiry of the session with the given token.
+func (s *GalleryService) UpdateSession(token string, data []byte, expires time.Time) error {
+	return s.store.UpdateSession(hashToken(token), data, expires)
+}
+
+// TouchSession records that the session with the given token was used now.
+func (s *GalleryService) TouchSession(token, lastIP, userAgent string) error {
+	return s.store.TouchSession(hashToken(token), lastIP, userAgent)
+}
+
+// EndSession deletes the session with the given token, e.g. on logout.
+func (s *GalleryService) EndSession(token string) error {
+	return s.store.DeleteSessionByToken(hashToken(token))
+}
+
+// ListSessions returns all active sessions, most recently used first.
+func (s *GalleryService) ListSessions() ([]Session, error) {
+	return s.store.ListSessions()
+}
+
+// RevokeSession deletes a session; its next request is no longer logged in.
+func (s *GalleryService) RevokeSession(id int64) error {
+	return s.store.DeleteSession(id)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// withCookies returns a request carrying the cookies set in w.
+func withCookies(w *httptest.ResponseRecorder) *http.Request {
+	r := httptest.NewRequest("GET", "/", http.NoBody)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+func TestServerSessions(t *testing.T) {
+	gallery := newTestServiceWithPhoto(t, "photo.png")
+	alice, err := gallery.CreateUser("alice", "alice-password", RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!", WithAccounts(gallery), WithSessions(gallery))
+
+	login := httptest.NewRequest("POST", "/login", http.NoBody)
+	login.Header.Set("User-Agent", "TestBrowser/1.0")
+	w := httptest.NewRecorder()
+	if !service.LoginUser(w, login, "alice", "alice-password") {
+		t.Fatal("Expected login to succeed")
+	}
+	r := withCookies(w)
+	r.Header.Set("User-Agent", "TestBrowser/1.0")
+	if user, ok := service.CurrentUser(r); !ok || user.ID != alice.ID {
+		t.Fatalf("Expected session of alice, got %+v (%v)", user, ok)
+	}
+
+	// The cookie only holds a token; the values live in the store
+	if cookie := w.Result().Cookies()[0]; len(cookie.Value) > 50 {
+		t.Errorf("Expected a short session token, got %q", cookie.Value)
+	}
+	guest := httptest.NewRecorder()
+	if !service.Login(guest, httptest.NewRequest("POST", "/login", http.NoBody), "password") {
+		t.Fatal("Expected guest login to succeed")
+	}
+
+	sessions, err := gallery.ListSessions()
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %+v (%v)", sessions, err)
+	}
+	var session Session
+	for _, s := range sessions {
+		if s.Kind == SessionKindUser {
+			session = s
+		}
+	}
+	if session.UserID != alice.ID || session.Name != "alice" || session.UserAgent != "TestBrowser/1.0" || session.LastIP != "192.0.2.1" {
+		t.Errorf("Unexpected session %+v", session)
+	}
+	if id, ok := service.CurrentSessionID(r); !ok || id != session.ID {
+		t.Errorf("Expected current session %d, got %d (%v)", session.ID, id, ok)
+	}
+
+	// Logging in again replaces the session
+	first := w
+	w = httptest.NewRecorder()
+	if !service.LoginUser(w, withCookies(first), "alice", "alice-password") {
+		t.Fatal("Expected second login to succeed")
+	}
+	if service.IsAuthenticated(withCookies(first)) {
+		t.Error("Expected the token from before the login to be ended")
+	}
+	r = withCookies(w)
+
+	// Changing the password ends the user's sessions, but not others
+	password := "new-password"
+	if _, err := gallery.UpdateUser(alice.ID, UserUpdate{Password: &password}); err != nil {
+		t.Fatal(err)
+	}
+	if service.IsAuthenticated(r) {
+		t.Error("Expected session to end with the password change")
+	}
+	if !service.IsAuthenticated(withCookies(guest)) {
+		t.Error("Expected guest session to survive the password change")
+	}
+
+	// Revoked and logged out sessions end
+	sessions, err = gallery.ListSessions()
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("Expected only the guest session, got %+v (%v)", sessions, err)
+	}
+	if err := gallery.RevokeSession(sessions[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if service.IsAuthenticated(withCookies(guest)) {
+		t.Error("Expected revoked session to end")
+	}
+	if err := gallery.RevokeSession(sessions[0].ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	if !service.LoginUser(w, login, "alice", "new-password") {
+		t.Fatal("Expected login with the new password to succeed")
+	}
+	service.Logout(httptest.NewRecorder(), withCookies(w))
+	if service.IsAuthenticated(withCookies(w)) {
+		t.Error("Expected logged out session to end")
+	}
+	if sessions, err := gallery.ListSessions(); err != nil || len(sessions) != 0 {
+		t.Errorf("Expected no sessions, got %+v (%v)", sessions, err)
+	}
+}
+
+func TestGuestSessionsEndWithPassword(t *testing.T) {
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!")
+	w := httptest.NewRecorder()
+	if !service.Login(w, httptest.NewRequest("POST", "/login", http.NoBody), "password") {
+		t.Fatal("Expected login to succeed")
+	}
+	if !service.IsAuthenticated(withCookies(w)) {
+		t.Fatal("Expected guest session")
+	}
+
+	// Restarted with another gallery password
+	changed := NewAuthService("new-password", "test-session-key-32-bytes-long!!")
+	if changed.IsAuthenticated(withCookies(w)) {
+		t.Error("Expected guest session to end with a new gallery password")
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultShareLifetime = 7 * 24 * time.Hour   // Lifetime of a share link without an explicit expiry
+	maxShareLifetime     = 365 * 24 * time.Hour // Longest lifetime of a share link
+	maxShareLabelLength  = 100                  // Maximum share label length in bytes
+)
+
+// ErrInvalidShare is returned for share links with invalid values.
+var ErrInvalidShare = errors.New("invalid share")
+
+// ShareKind is what a share link grants access to.
+type ShareKind string
+
+const (
+	// ShareKindPhoto shares a single photo.
+	ShareKindPhoto ShareKind = "photo"
+	// ShareKindEvent shares all photos of an event.
+	ShareKindEvent ShareKind = "event"
+	// ShareKindAlbum shares the photos of an album.
+	ShareKindAlbum ShareKind = "album"
+	// ShareKindUpload only allows uploading photos, without seeing any, e.g.
+	// for QR codes handed out to the guests of an event.
+	ShareKindUpload ShareKind = "upload"
+)
+
+// Share is an expiring link that works without a password. Photo, event and
+// album shares are read-only links to their target; exactly one of Photo,
+// EventID and AlbumID is set, depending on Kind. Event shares may allow
+// uploading photos to the event. Upload shares have no target; they always
+// allow uploads and never show photos.
+type Share struct {
+	ID          int64     `json:"id"`
+	Kind        ShareKind `json:"kind"`
+	Photo       string    `json:"photo,omitempty"`
+	EventID     int64     `json:"event_id,omitempty"`
+	AlbumID     int64     `json:"album_id,omitempty"`
+	Label       string    `json:"label"` // who the link was given to, also recorded as uploader
+	AllowUpload bool      `json:"allow_upload"`
+	Expires     time.Time `json:"expires"`
+	Created     time.Time `json:"created"`
+}
+
+// Expired reports whether the share link is no longer valid at now.
+func (s Share) Expired(now time.Time) bool {
+	return !now.Before(s.Expires)
+}
+
+// UploadOnly reports whether the share link only allows uploads.
+func (s Share) UploadOnly() bool {
+	return s.Kind == ShareKindUpload
+}
+
+// Scope returns the part of the gallery the share link grants access to.
+func (s Share) Scope() AccessScope {
+	switch s.Kind {
+	case ShareKindPhoto:
+		return AccessScope{Photos: []string{s.Photo}}
+	case ShareKindEvent:
+		return AccessScope{Events: []int64{s.EventID}}
+	case ShareKindAlbum:
+		return AccessScope{Albums: []int64{s.AlbumID}}
+	}
+	return AccessScope{}
+}
+
+// CreateShare creates a share link for the photo, event or album set in
+// share, or an upload-only link if share.Kind is ShareKindUpload. A zero
+// Expires defaults to one week from now.
+func (s *GalleryService) CreateShare(share Share) (Share, error) {
+	targets := 0
+	for _, set := range []bool{share.Photo != "", share.EventID != 0, share.AlbumID != 0} {
+		if set {
+			targets++
+		}
+	}
+	switch {
+	case share.UploadOnly() && targets != 0:
+		return Share{}, fmt.Errorf("%w: upload links cannot share photos", ErrInvalidShare)
+	case !share.UploadOnly() && targets != 1:
+		return Share{}, fmt.Errorf("%w: exactly one photo, event or album must be shared", ErrInvalidShare)
+	}
+
+	var err error
+	switch {
+	case share.UploadOnly():
+		share.AllowUpload = true
+	case share.Photo != "":
+		share.Kind = ShareKindPhoto
+		_, err = s.GetPhoto(share.Photo)
+	case share.EventID != 0:
+		share.Kind = ShareKindEvent
+		_, err = s.store.GetEvent(share.EventID)
+	default:
+		share.Kind = ShareKindAlbum
+		_, err = s.store.GetAlbum(share.AlbumID)
+	}
+	if err != nil {
+		return Share{}, err
+	}
+	if share.AllowUpload && share.Kind != ShareKindEvent && !share.UploadOnly() {
+		return Share{}, fmt.Errorf("%w: uploads are only possible to a shared event", ErrInvalidShare)
+	}
+
+	share.Label = strings.TrimSpace(share.Label)
+	if len(share.Label) > maxShareLabelLength {
+		return Share{}, fmt.Errorf("%w: label is longer than %d bytes", ErrInvalidShare, maxShareLabelLength)
+	}
+
+	now := time.Now()
+	if share.Expires.IsZero() {
+		share.Expires = now.Add(defaultShareLifetime)
+	}
+	if share.Expired(now) {
+		return Share{}, fmt.Errorf("%w: expiry must be in the future", ErrInvalidShare)
+	}
+	if share.Expires.After(now.Add(maxShareLifetime)) {
+		return Share{}, fmt.Errorf("%w: share links expire after a year at most", ErrInvalidShare)
+	}
+	share.Expires = share.Expires.Truncate(time.Second)
+	return s.store.CreateShare(share)
+}
+
+// ListShares returns all share links, including expired ones, newest first.
+func (s *GalleryService) ListShares() ([]Share, error) {
+	return s.store.ListShares()
+}
+
+// GetShare returns a share link or ErrShareNotFound.
+func (s *GalleryService) GetShare(id int64) (Share, error) {
+	return s.store.GetShare(id)
+}
+
+// DeleteShare revokes a share link; sessions opened with it end immediately.
+func (s *GalleryService) DeleteShare(id int64) error {
+	return s.store.DeleteShare(id)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShares(t *testing.T) {
+	service, secret, _ := newTestServiceWithSecretEvent(t)
+	album, err := service.CreateAlbum("Best of")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddToAlbum(album.ID, []string{"bob.png"}); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []struct {
+		name  string
+		share Share
+	}{
+		{"no target", Share{}},
+		{"two targets", Share{Photo: "public.png", AlbumID: album.ID}},
+		{"upload to a photo", Share{Photo: "public.png", AllowUpload: true}},
+		{"expired", Share{Photo: "public.png", Expires: time.Now().Add(-time.Hour)}},
+		{"too long", Share{Photo: "public.png", Expires: time.Now().Add(2 * maxShareLifetime)}},
+	}
+	for _, tt := range invalid {
+		if _, err := service.CreateShare(tt.share); !errors.Is(err, ErrInvalidShare) {
+			t.Errorf("%s: expected ErrInvalidShare, got %v", tt.name, err)
+		}
+	}
+	if _, err := service.CreateShare(Share{Kind: ShareKindUpload, EventID: secret.ID}); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Expected ErrInvalidShare for an upload link with a target, got %v", err)
+	}
+	if _, err := service.CreateShare(Share{Photo: "missing.png"}); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected ErrPhotoNotFound, got %v", err)
+	}
+
+	tests := []struct {
+		share Share
+		kind  ShareKind
+		want  []string
+	}{
+		{Share{Photo: "public.png"}, ShareKindPhoto, []string{"public.png"}},
+		{Share{EventID: secret.ID, AllowUpload: true}, ShareKindEvent, []string{"bob.png", "secret.png"}},
+		{Share{AlbumID: album.ID}, ShareKindAlbum, []string{"bob.png"}},
+		{Share{Kind: ShareKindUpload}, ShareKindUpload, []string{}},
+	}
+	for _, tt := range tests {
+		share, err := service.CreateShare(tt.share)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if share.ID == 0 || share.Kind != tt.kind || share.Expired(time.Now()) || share.Expired(time.Now().Add(defaultShareLifetime-time.Minute)) {
+			t.Errorf("Unexpected share %+v", share)
+		}
+
+		access, err := service.PhotoAccess(share.Scope())
+		if err != nil {
+			t.Fatal(err)
+		}
+		photos, err := service.QueryPhotos(PhotoFilter{Access: access})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := photoNames(photos); !slices.Equal(got, tt.want) {
+			t.Errorf("%s share: index returned %v, want %v", tt.kind, got, tt.want)
+		}
+		stored, err := service.store.ListPhotos(PhotoFilter{Access: access})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := photoNames(stored); !slices.Equal(got, tt.want) {
+			t.Errorf("%s share: store returned %v, want %v", tt.kind, got, tt.want)
+		}
+		if access.AllowsAlbum(album.ID) != (tt.kind == ShareKindAlbum) {
+			t.Errorf("%s share: unexpected access to the album", tt.kind)
+		}
+	}
+
+	shares, err := service.ListShares()
+	if err != nil || len(shares) != 4 || shares[0].Kind != ShareKindUpload || !shares[0].AllowUpload {
+		t.Fatalf("Expected 4 shares newest first, got %+v (%v)", shares, err)
+	}
+	shares = shares[1:]
+
+	// Shares are deleted with what they share
+	if err := service.DeleteAlbum(album.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.GetShare(shares[0].ID); !errors.Is(err, ErrShareNotFound) {
+		t.Errorf("Expected share of a deleted album to be gone, got %v", err)
+	}
+	if err := service.DeleteShare(shares[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeleteShare(shares[1].ID); !errors.Is(err, ErrShareNotFound) {
+		t.Errorf("Expected ErrShareNotFound, got %v", err)
+	}
+}
+
+func TestShareLogin(t *testing.T) {
+	gallery := newTestServiceWithPhoto(t, "photo.png")
+	service := NewAuthService("password", "test-session-key-32-bytes-long!!", WithShares(gallery))
+
+	share, err := gallery.CreateShare(Share{Photo: "photo.png", Label: "Aunt May"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := service.ShareToken(share)
+
+	tampered := []string{"", "garbage", token + "x", strings.Replace(token, ".", "A.", 1)}
+	for _, bad := range tampered {
+		if service.LoginShare(httptest.NewRecorder(), httptest.NewRequest("GET", "/", http.NoBody), bad) {
+			t.Errorf("Expected login to fail with token %q", bad)
+		}
+	}
+	other := NewAuthService("password", "another-session-key-32-bytes!!!!", WithShares(gallery))
+	if other.LoginShare(httptest.NewRecorder(), httptest.NewRequest("GET", "/", http.NoBody), token) {
+		t.Error("Expected tokens signed with another key to be rejected")
+	}
+
+	w := httptest.NewRecorder()
+	if !service.LoginShare(w, httptest.NewRequest("GET", "/share/"+token, http.NoBody), token) {
+		t.Fatal("Expected login to succeed with a valid token")
+	}
+	r := httptest.NewRequest("GET", "/", http.NoBody)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	user, ok := service.CurrentUser(r)
+	if !ok || user.Username != "Aunt May" || user.HasRole(RoleContributor) {
+		t.Errorf("Expected viewer session named after the label, got %+v (%v)", user, ok)
+	}
+	scope, ok := service.Access(r)
+	if !ok || scope.Public || !slices.Equal(scope.Photos, []string{"photo.png"}) {
+		t.Errorf("Expected session scoped to the shared photo, got %+v (%v)", scope, ok)
+	}
+
+	// The token also works as query parameter without a session
+	query := httptest.NewRequest("GET", "/uploads/photo.png?share="+token, http.NoBody)
+	if got, ok := service.CurrentShare(query); !ok || got.ID != share.ID {
+		t.Errorf("Expected share from the query parameter, got %+v (%v)", got, ok)
+	}
+
+	// Revoked links end existing sessions
+	if err := gallery.DeleteShare(share.ID); err != nil {
+		t.Fatal(err)
+	}
+	if service.IsAuthenticated(r) || service.IsAuthenticated(query) {
+		t.Error("Expected revoked share link to be rejected")
+	}
+
+	// Upload links are logged in but may not browse the gallery
+	upload, err := gallery.CreateShare(Share{Kind: ShareKindUpload, Label: "Table 5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	if !service.LoginShare(w, httptest.NewRequest("GET", "/", http.NoBody), service.ShareToken(upload)) {
+		t.Fatal("Expected login to succeed with an upload link")
+	}
+	r = httptest.NewRequest("GET", "/", http.NoBody)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	if _, ok := service.CurrentUser(r); !ok || service.IsAuthenticated(r) {
+		t.Errorf("Expected upload link to be logged in without browsing (%v)", ok)
+	}
+	if scope, _ := service.Access(r); len(scope.Photos)+len(scope.Events)+len(scope.Albums) != 0 || scope.Public {
+		t.Errorf("Expected upload link to see nothing, got %+v", scope)
+	}
+
+	// Expired links are rejected
+	expired, err := gallery.store.CreateShare(Share{Kind: ShareKindPhoto, Photo: "photo.png", Expires: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.LoginShare(httptest.NewRecorder(), httptest.NewRequest("GET", "/", http.NoBody), service.ShareToken(expired)) {
+		t.Error("Expected expired share link to be rejected")
+	}
+}
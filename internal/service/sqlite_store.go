@@ -0,0 +1,1588 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Register SQLite driver
+)
+
+const (
+	databaseFilename     = "gallery.db"
+	sidecarsImportedFlag = "sidecars_imported"
+)
+
+// migrations are applied in order; the index of the last applied migration
+// plus one is stored in PRAGMA user_version. Never edit an existing entry,
+// append a new one instead.
+var migrations = []string{
+	`CREATE TABLE photos (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		name        TEXT NOT NULL UNIQUE,
+		path        TEXT NOT NULL,
+		uploader    TEXT NOT NULL DEFAULT '',
+		event       TEXT NOT NULL DEFAULT '',
+		upload_date TEXT NOT NULL,
+		photo_time  TEXT,
+		sort_time   INTEGER NOT NULL
+	);
+	CREATE INDEX idx_photos_sort_time ON photos (sort_time DESC, name);
+	CREATE INDEX idx_photos_event ON photos (event, sort_time DESC);
+	CREATE INDEX idx_photos_uploader ON photos (uploader, sort_time DESC);
+	CREATE TABLE meta (
+		key   TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);`,
+	`ALTER TABLE photos ADD COLUMN hash TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_photos_hash ON photos (hash) WHERE hash != '';
+	CREATE TABLE photo_uploaders (
+		photo_id INTEGER NOT NULL REFERENCES photos (id) ON DELETE CASCADE,
+		uploader TEXT NOT NULL,
+		added_at TEXT NOT NULL,
+		PRIMARY KEY (photo_id, uploader)
+	);
+	CREATE INDEX idx_photo_uploaders_uploader ON photo_uploaders (uploader);`,
+	`ALTER TABLE photos ADD COLUMN perceptual_hash INTEGER;
+	CREATE TABLE duplicate_dismissals (
+		photo_a INTEGER NOT NULL REFERENCES photos (id) ON DELETE CASCADE,
+		photo_b INTEGER NOT NULL REFERENCES photos (id) ON DELETE CASCADE,
+		PRIMARY KEY (photo_a, photo_b)
+	);`,
+	`ALTER TABLE photos ADD COLUMN deleted_at TEXT;
+	CREATE INDEX idx_photos_deleted_at ON photos (deleted_at) WHERE deleted_at IS NOT NULL;`,
+	`ALTER TABLE photos ADD COLUMN caption TEXT NOT NULL DEFAULT '';`,
+	`CREATE TABLE events (
+		id             INTEGER PRIMARY KEY AUTOINCREMENT,
+		name           TEXT NOT NULL UNIQUE,
+		description    TEXT NOT NULL DEFAULT '',
+		start_date     TEXT,
+		end_date       TEXT,
+		cover_photo_id INTEGER REFERENCES photos (id) ON DELETE SET NULL
+	);
+	INSERT INTO events (name) SELECT DISTINCT event FROM photos WHERE event != '' ORDER BY event;`,
+	`CREATE TABLE albums (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		name       TEXT NOT NULL,
+		created_at TEXT NOT NULL
+	);
+	CREATE TABLE album_photos (
+		album_id INTEGER NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+		photo_id INTEGER NOT NULL REFERENCES photos (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		PRIMARY KEY (album_id, photo_id)
+	);
+	CREATE INDEX idx_album_photos_photo ON album_photos (photo_id);`,
+	`CREATE TABLE photo_tags (
+		photo_id INTEGER NOT NULL REFERENCES photos (id) ON DELETE CASCADE,
+		tag      TEXT NOT NULL,
+		PRIMARY KEY (photo_id, tag)
+	);
+	CREATE INDEX idx_photo_tags_tag ON photo_tags (tag);`,
+	`CREATE TABLE users (
+		id            INTEGER PRIMARY KEY AUTOINCREMENT,
+		username      TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		password_hash TEXT NOT NULL,
+		role          TEXT NOT NULL,
+		created_at    TEXT NOT NULL
+	);
+	ALTER TABLE photos ADD COLUMN owner_id INTEGER REFERENCES users (id) ON DELETE SET NULL;
+	CREATE INDEX idx_photos_owner ON photos (owner_id) WHERE owner_id IS NOT NULL;`,
+	`ALTER TABLE events ADD COLUMN password_hash TEXT NOT NULL DEFAULT '';`,
+	`CREATE TABLE shares (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind         TEXT NOT NULL,
+		photo_id     INTEGER REFERENCES photos (id) ON DELETE CASCADE,
+		event_id     INTEGER REFERENCES events (id) ON DELETE CASCADE,
+		album_id     INTEGER REFERENCES albums (id) ON DELETE CASCADE,
+		label        TEXT NOT NULL DEFAULT '',
+		allow_upload INTEGER NOT NULL DEFAULT 0,
+		expires_at   TEXT NOT NULL,
+		created_at   TEXT NOT NULL
+	);`,
+	`CREATE TABLE sessions (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		token_hash   TEXT NOT NULL UNIQUE,
+		kind         TEXT NOT NULL,
+		user_id      INTEGER REFERENCES users (id) ON DELETE CASCADE,
+		share_id     INTEGER REFERENCES shares (id) ON DELETE CASCADE,
+		data         BLOB NOT NULL,
+		last_ip      TEXT NOT NULL DEFAULT '',
+		user_agent   TEXT NOT NULL DEFAULT '',
+		created_at   TEXT NOT NULL,
+		last_seen_at TEXT NOT NULL,
+		expires_at   TEXT NOT NULL
+	);
+	CREATE INDEX idx_sessions_user ON sessions (user_id) WHERE user_id IS NOT NULL;
+	CREATE INDEX idx_sessions_expires ON sessions (expires_at);`,
+	`CREATE TABLE api_tokens (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		token_hash   TEXT NOT NULL UNIQUE,
+		name         TEXT NOT NULL,
+		scope        TEXT NOT NULL,
+		created_at   TEXT NOT NULL,
+		last_used_at TEXT NOT NULL DEFAULT ''
+	);`,
+}
+
+// SQLiteStore is a MetadataStore backed by an embedded SQLite database.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLiteStore opens (and if necessary creates and migrates) the metadata
+// database inside metadataDir.
+func OpenSQLiteStore(metadataDir string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(metadataDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+
+	dsn := "file:" + filepath.Join(metadataDir, databaseFilename) + "?_busy_timeout=5000&_journal_mode=WAL&_foreign_keys=on"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metadata database: %w", err)
+	}
+
+	store := &SQLiteStore{db: db}
+	if err := store.migrate(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *SQLiteStore) migrate() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to start migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+		// PRAGMA does not support placeholders; i is a trusted loop index
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", i+1, err)
+		}
+		log.Printf("Applied metadata database migration %d", i+1)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+const photoColumns = "id, name, path, uploader, event, upload_date, photo_time, hash, perceptual_hash, deleted_at, caption, owner_id"
+
+// livePhotos restricts a query to photos that are not in the trash.
+const livePhotos = "deleted_at IS NULL"
+
+func (s *SQLiteStore) SavePhoto(info *PhotoInfo) error {
+	if err := ensureEvent(s.db, info.Event); err != nil {
+		return err
+	}
+
+	var photoTime sql.NullString
+	if !info.PhotoTime.IsZero() {
+		photoTime = sql.NullString{String: info.PhotoTime.Format(time.RFC3339Nano), Valid: true}
+	}
+
+	var ownerID sql.NullInt64
+	if info.OwnerID != 0 {
+		ownerID = sql.NullInt64{Int64: info.OwnerID, Valid: true}
+	}
+
+	err := s.db.QueryRow(`INSERT INTO photos (name, path, uploader, event, upload_date, photo_time, sort_time, hash, perceptual_hash, caption, owner_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			path = excluded.path,
+			uploader = excluded.uploader,
+			event = excluded.event,
+			upload_date = excluded.upload_date,
+			photo_time = excluded.photo_time,
+			sort_time = excluded.sort_time,
+			hash = excluded.hash,
+			perceptual_hash = excluded.perceptual_hash,
+			caption = excluded.caption,
+			owner_id = excluded.owner_id,
+			deleted_at = NULL
+		RETURNING id`,
+		info.Name, info.Path, info.Uploader, info.Event,
+		info.Date.Format(time.RFC3339Nano), photoTime, sortTime(*info), info.Hash,
+		perceptualHashValue(info.PerceptualHash), info.Caption, ownerID,
+	).Scan(&info.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save metadata for %s: %w", info.Name, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GetPhoto(name string) (PhotoInfo, error) {
+	return s.getPhoto("name = ? AND "+livePhotos, name)
+}
+
+func (s *SQLiteStore) GetTrashedPhoto(name string) (PhotoInfo, error) {
+	return s.getPhoto("name = ? AND deleted_at IS NOT NULL", name)
+}
+
+func (s *SQLiteStore) FindPhotoByHash(hash, event string) (PhotoInfo, error) {
+	if hash == "" {
+		return PhotoInfo{}, ErrPhotoNotFound
+	}
+	return s.getPhoto("hash = ? AND event = ? AND "+livePhotos+" ORDER BY id LIMIT 1", hash, event)
+}
+
+// getPhoto returns the first photo matching condition together with its
+// additional uploaders; condition is never user input.
+func (s *SQLiteStore) getPhoto(condition string, args ...any) (PhotoInfo, error) {
+	row := s.db.QueryRow("SELECT "+photoColumns+" FROM photos WHERE "+condition, args...)
+	info, err := scanPhoto(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return PhotoInfo{}, ErrPhotoNotFound
+	}
+	if err != nil {
+		return PhotoInfo{}, err
+	}
+
+	photos := []PhotoInfo{info}
+	if err := s.loadDetails(photos); err != nil {
+		return PhotoInfo{}, err
+	}
+	return photos[0], nil
+}
+
+func (s *SQLiteStore) AddContributor(name, uploader string) error {
+	return addContributor(s.db, name, uploader)
+}
+
+func addContributor(db execer, name, uploader string) error {
+	_, err := db.Exec(`INSERT OR IGNORE INTO photo_uploaders (photo_id, uploader, added_at)
+		SELECT id, ?, ? FROM photos WHERE name = ? AND uploader != ? AND `+livePhotos,
+		uploader, time.Now().Format(time.RFC3339Nano), name, uploader)
+	if err != nil {
+		return fmt.Errorf("failed to add uploader %s to %s: %w", uploader, name, err)
+	}
+	return nil
+}
+
+// loadDetails fills in the additional uploaders and the tags of photos.
+func (s *SQLiteStore) loadDetails(photos []PhotoInfo) error {
+	if len(photos) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*PhotoInfo, len(photos))
+	for i := range photos {
+		byID[photos[i].ID] = &photos[i]
+	}
+
+	err := s.loadPhotoValues(photos, "SELECT photo_id, uploader FROM photo_uploaders", "added_at, uploader", func(photoID int64, uploader string) {
+		if photo, ok := byID[photoID]; ok {
+			photo.Contributors = append(photo.Contributors, uploader)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query uploaders: %w", err)
+	}
+
+	err = s.loadPhotoValues(photos, "SELECT photo_id, tag FROM photo_tags", "tag", func(photoID int64, tag string) {
+		if photo, ok := byID[photoID]; ok {
+			photo.Tags = append(photo.Tags, tag)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query tags: %w", err)
+	}
+	return nil
+}
+
+// loadPhotoValues runs query, which selects a photo ID and a value, for photos
+// and passes each row to add. For a single photo only its rows are queried.
+func (s *SQLiteStore) loadPhotoValues(photos []PhotoInfo, query, order string, add func(photoID int64, value string)) error {
+	var args []any
+	if len(photos) == 1 {
+		query += " WHERE photo_id = ?"
+		args = append(args, photos[0].ID)
+	}
+	rows, err := s.db.Query(query+" ORDER BY "+order, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var photoID int64
+		var value string
+		if err := rows.Scan(&photoID, &value); err != nil {
+			return err
+		}
+		add(photoID, value)
+	}
+	return rows.Err()
+}
+
+func (s *SQLiteStore) UpdatePhotos(names []string, update PhotoUpdate) error {
+	var assignments []string
+	var args []any
+	if update.Event != nil {
+		assignments = append(assignments, "event = ?")
+		args = append(args, *update.Event)
+	}
+	if update.Uploader != nil {
+		assignments = append(assignments, "uploader = ?")
+		args = append(args, *update.Uploader)
+	}
+	if update.Caption != nil {
+		assignments = append(assignments, "caption = ?")
+		args = append(args, *update.Caption)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start metadata update: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if update.Event != nil {
+		if err := ensureEvent(tx, *update.Event); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		var photoID int64
+		err := tx.QueryRow("SELECT id FROM photos WHERE name = ? AND "+livePhotos, name).Scan(&photoID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", name, ErrPhotoNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up %s: %w", name, err)
+		}
+
+		if len(assignments) > 0 {
+			if _, err := tx.Exec("UPDATE photos SET "+strings.Join(assignments, ", ")+" WHERE id = ?", append(args, photoID)...); err != nil {
+				return fmt.Errorf("failed to update metadata for %s: %w", name, err)
+			}
+		}
+		if err := updateTags(tx, photoID, update); err != nil {
+			return fmt.Errorf("failed to update tags for %s: %w", name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit metadata update: %w", err)
+	}
+	return nil
+}
+
+// updateTags applies the tag changes of update to a photo.
+func updateTags(tx *sql.Tx, photoID int64, update PhotoUpdate) error {
+	if update.Tags != nil {
+		if _, err := tx.Exec("DELETE FROM photo_tags WHERE photo_id = ?", photoID); err != nil {
+			return err
+		}
+	}
+
+	var added []string
+	if update.Tags != nil {
+		added = append(added, *update.Tags...)
+	}
+	added = append(added, update.AddTags...)
+	for _, tag := range added {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO photo_tags (photo_id, tag) VALUES (?, ?)", photoID, tag); err != nil {
+			return err
+		}
+	}
+	for _, tag := range update.RemoveTags {
+		if _, err := tx.Exec("DELETE FROM photo_tags WHERE photo_id = ? AND tag = ?", photoID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// ensureEvent creates the event row for a new event name.
+func ensureEvent(db execer, name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, err := db.Exec("INSERT OR IGNORE INTO events (name) VALUES (?)", name); err != nil {
+		return fmt.Errorf("failed to create event %s: %w", name, err)
+	}
+	return nil
+}
+
+const eventColumns = "e.id, e.name, e.description, e.start_date, e.end_date, COALESCE(p.name, ''), e.password_hash != ''"
+
+// eventTables joins the cover photo, which is ignored while it is in the trash.
+const eventTables = "events e LEFT JOIN photos p ON p.id = e.cover_photo_id AND p." + livePhotos
+
+func (s *SQLiteStore) ListEvents() ([]Event, error) {
+	rows, err := s.db.Query("SELECT " + eventColumns + " FROM " + eventTables + " ORDER BY e.name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (s *SQLiteStore) GetEvent(id int64) (Event, error) {
+	event, err := scanEvent(s.db.QueryRow("SELECT "+eventColumns+" FROM "+eventTables+" WHERE e.id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Event{}, ErrEventNotFound
+	}
+	return event, err
+}
+
+func (s *SQLiteStore) UpdateEvent(id int64, update EventUpdate) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start event update: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	name, err := eventName(tx, id)
+	if err != nil {
+		return err
+	}
+
+	var assignments []string
+	var args []any
+	if update.Name != nil && *update.Name != name {
+		var existing int64
+		err := tx.QueryRow("SELECT id FROM events WHERE name = ?", *update.Name).Scan(&existing)
+		if err == nil {
+			return fmt.Errorf("%s: %w", *update.Name, ErrEventExists)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to look up event %s: %w", *update.Name, err)
+		}
+
+		// Photos in the trash are renamed too so they return to the right event
+		if _, err := tx.Exec("UPDATE photos SET event = ? WHERE event = ?", *update.Name, name); err != nil {
+			return fmt.Errorf("failed to rename event %s: %w", name, err)
+		}
+		assignments = append(assignments, "name = ?")
+		args = append(args, *update.Name)
+	}
+	if update.Description != nil {
+		assignments = append(assignments, "description = ?")
+		args = append(args, *update.Description)
+	}
+	if update.StartDate != nil {
+		assignments = append(assignments, "start_date = ?")
+		args = append(args, eventDateValue(*update.StartDate))
+	}
+	if update.EndDate != nil {
+		assignments = append(assignments, "end_date = ?")
+		args = append(args, eventDateValue(*update.EndDate))
+	}
+	if update.CoverPhoto != nil {
+		assignments = append(assignments, "cover_photo_id = (SELECT id FROM photos WHERE name = ? AND "+livePhotos+")")
+		args = append(args, *update.CoverPhoto)
+	}
+	if update.Password != nil {
+		assignments = append(assignments, "password_hash = ?")
+		args = append(args, *update.Password)
+	}
+
+	if len(assignments) > 0 {
+		if _, err := tx.Exec("UPDATE events SET "+strings.Join(assignments, ", ")+" WHERE id = ?", append(args, id)...); err != nil {
+			return fmt.Errorf("failed to update event %s: %w", name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit event update: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) EventPasswordHashes() (map[int64]string, error) {
+	rows, err := s.db.Query("SELECT id, password_hash FROM events WHERE password_hash != ''")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event passwords: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			return nil, err
+		}
+		hashes[id] = hash
+	}
+	return hashes, rows.Err()
+}
+
+func (s *SQLiteStore) MergeEvents(target int64, sources []int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start event merge: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	targetName, err := eventName(tx, target)
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		sourceName, err := eventName(tx, source)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE photos SET event = ? WHERE event = ?", targetName, sourceName); err != nil {
+			return fmt.Errorf("failed to move photos of %s to %s: %w", sourceName, targetName, err)
+		}
+		if _, err := tx.Exec("DELETE FROM events WHERE id = ?", source); err != nil {
+			return fmt.Errorf("failed to delete event %s: %w", sourceName, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit event merge: %w", err)
+	}
+	return nil
+}
+
+// eventName returns the name of an event or ErrEventNotFound.
+func eventName(tx *sql.Tx, id int64) (string, error) {
+	var name string
+	err := tx.QueryRow("SELECT name FROM events WHERE id = ?", id).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("event %d: %w", id, ErrEventNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up event %d: %w", id, err)
+	}
+	return name, nil
+}
+
+// albumColumns selects an album with the number of its live photos and the
+// first of them as cover.
+const albumColumns = `a.id, a.name, a.created_at,
+	(SELECT COUNT(*) FROM album_photos ap JOIN photos p ON p.id = ap.photo_id
+		WHERE ap.album_id = a.id AND p.` + livePhotos + `),
+	COALESCE((SELECT p.name FROM album_photos ap JOIN photos p ON p.id = ap.photo_id
+		WHERE ap.album_id = a.id AND p.` + livePhotos + ` ORDER BY ap.position, p.name LIMIT 1), '')`
+
+func (s *SQLiteStore) CreateAlbum(name string) (Album, error) {
+	var id int64
+	err := s.db.QueryRow("INSERT INTO albums (name, created_at) VALUES (?, ?) RETURNING id",
+		name, time.Now().Format(time.RFC3339Nano)).Scan(&id)
+	if err != nil {
+		return Album{}, fmt.Errorf("failed to create album %s: %w", name, err)
+	}
+	return s.GetAlbum(id)
+}
+
+func (s *SQLiteStore) ListAlbums() ([]Album, error) {
+	rows, err := s.db.Query("SELECT " + albumColumns + " FROM albums a ORDER BY a.name, a.id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query albums: %w", err)
+	}
+	defer rows.Close()
+
+	var albums []Album
+	for rows.Next() {
+		album, err := scanAlbum(rows)
+		if err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+	return albums, rows.Err()
+}
+
+func (s *SQLiteStore) GetAlbum(id int64) (Album, error) {
+	album, err := scanAlbum(s.db.QueryRow("SELECT "+albumColumns+" FROM albums a WHERE a.id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Album{}, fmt.Errorf("album %d: %w", id, ErrAlbumNotFound)
+	}
+	return album, err
+}
+
+func (s *SQLiteStore) RenameAlbum(id int64, name string) error {
+	result, err := s.db.Exec("UPDATE albums SET name = ? WHERE id = ?", name, id)
+	if err != nil {
+		return fmt.Errorf("failed to rename album %d: %w", id, err)
+	}
+	return expectAlbumAffected(result, id)
+}
+
+func (s *SQLiteStore) DeleteAlbum(id int64) error {
+	result, err := s.db.Exec("DELETE FROM albums WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete album %d: %w", id, err)
+	}
+	return expectAlbumAffected(result, id)
+}
+
+func (s *SQLiteStore) AlbumPhotos(id int64) ([]PhotoInfo, error) {
+	if _, err := s.GetAlbum(id); err != nil {
+		return nil, err
+	}
+	return s.queryPhotos(`SELECT `+prefixColumns("p", photoColumns)+` FROM album_photos ap JOIN photos p ON p.id = ap.photo_id
+		WHERE ap.album_id = ? AND p.`+livePhotos+` ORDER BY ap.position, p.name`, id)
+}
+
+func (s *SQLiteStore) AddAlbumPhotos(id int64, names []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start album update: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var position int
+	err = tx.QueryRow("SELECT COALESCE((SELECT MAX(position) + 1 FROM album_photos WHERE album_id = a.id), 0) FROM albums a WHERE a.id = ?", id).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("album %d: %w", id, ErrAlbumNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up album %d: %w", id, err)
+	}
+
+	for _, name := range names {
+		var photoID int64
+		err := tx.QueryRow("SELECT id FROM photos WHERE name = ? AND "+livePhotos, name).Scan(&photoID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", name, ErrPhotoNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up %s: %w", name, err)
+		}
+
+		// Photos already in the album keep their position
+		result, err := tx.Exec("INSERT OR IGNORE INTO album_photos (album_id, photo_id, position) VALUES (?, ?, ?)", id, photoID, position)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to album %d: %w", name, id, err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			position++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit album update: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) RemoveAlbumPhoto(id int64, name string) error {
+	if _, err := s.GetAlbum(id); err != nil {
+		return err
+	}
+	result, err := s.db.Exec("DELETE FROM album_photos WHERE album_id = ? AND photo_id = (SELECT id FROM photos WHERE name = ?)", id, name)
+	if err != nil {
+		return fmt.Errorf("failed to remove %s from album %d: %w", name, id, err)
+	}
+	return expectAffected(result, name)
+}
+
+func (s *SQLiteStore) ReorderAlbum(id int64, names []string) error {
+	// Together with the count below, this makes names a permutation of the album
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return fmt.Errorf("%w: %s is listed twice", ErrInvalidAlbum, name)
+		}
+		seen[name] = true
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start album update: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var count int
+	err = tx.QueryRow(`SELECT COUNT(p.id) FROM albums a
+		LEFT JOIN album_photos ap ON ap.album_id = a.id
+		LEFT JOIN photos p ON p.id = ap.photo_id AND p.`+livePhotos+`
+		WHERE a.id = ? GROUP BY a.id`, id).Scan(&count)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("album %d: %w", id, ErrAlbumNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up album %d: %w", id, err)
+	}
+	if count != len(names) {
+		return fmt.Errorf("%w: the new order must list all %d photos of the album", ErrInvalidAlbum, count)
+	}
+
+	// Photos in the trash move behind the listed ones, keeping their relative order
+	if _, err := tx.Exec(`UPDATE album_photos SET position = position + ? WHERE album_id = ?
+		AND photo_id IN (SELECT id FROM photos WHERE deleted_at IS NOT NULL)`, len(names), id); err != nil {
+		return fmt.Errorf("failed to reorder album %d: %w", id, err)
+	}
+
+	stmt, err := tx.Prepare("UPDATE album_photos SET position = ? WHERE album_id = ? AND photo_id = (SELECT id FROM photos WHERE name = ? AND " + livePhotos + ")")
+	if err != nil {
+		return fmt.Errorf("failed to prepare album reorder: %w", err)
+	}
+	defer stmt.Close()
+
+	for position, name := range names {
+		result, err := stmt.Exec(position, id, name)
+		if err != nil {
+			return fmt.Errorf("failed to move %s in album %d: %w", name, id, err)
+		}
+		if err := expectAffected(result, name); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit album reorder: %w", err)
+	}
+	return nil
+}
+
+// expectAlbumAffected returns ErrAlbumNotFound if result did not touch any row.
+func expectAlbumAffected(result sql.Result, id int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("album %d: %w", id, ErrAlbumNotFound)
+	}
+	return nil
+}
+
+const userColumns = "id, username, role, created_at"
+
+func (s *SQLiteStore) CreateUser(username, passwordHash string, role Role) (User, error) {
+	var id int64
+	err := s.db.QueryRow(`INSERT INTO users (username, password_hash, role, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (username) DO NOTHING RETURNING id`,
+		username, passwordHash, string(role), time.Now().Format(time.RFC3339Nano)).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, fmt.Errorf("%s: %w", username, ErrUserExists)
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("failed to create user %s: %w", username, err)
+	}
+	return s.GetUser(id)
+}
+
+func (s *SQLiteStore) UserCredentials(username string) (User, string, error) {
+	var id int64
+	var passwordHash string
+	err := s.db.QueryRow("SELECT id, password_hash FROM users WHERE username = ?", username).Scan(&id, &passwordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, "", fmt.Errorf("%s: %w", username, ErrUserNotFound)
+	}
+	if err != nil {
+		return User{}, "", fmt.Errorf("failed to query user %s: %w", username, err)
+	}
+
+	user, err := s.GetUser(id)
+	if err != nil {
+		return User{}, "", err
+	}
+	return user, passwordHash, nil
+}
+
+func (s *SQLiteStore) GetUser(id int64) (User, error) {
+	user, err := scanUser(s.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	return user, err
+}
+
+func (s *SQLiteStore) ListUsers() ([]User, error) {
+	rows, err := s.db.Query("SELECT " + userColumns + " FROM users ORDER BY username COLLATE NOCASE, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (s *SQLiteStore) UpdateUser(id int64, role *Role, passwordHash *string) error {
+	var assignments []string
+	var args []any
+	if role != nil {
+		assignments = append(assignments, "role = ?")
+		args = append(args, string(*role))
+	}
+	if passwordHash != nil {
+		assignments = append(assignments, "password_hash = ?")
+		args = append(args, *passwordHash)
+	}
+	if len(assignments) == 0 {
+		_, err := s.GetUser(id)
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start user update: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.Exec("UPDATE users SET "+strings.Join(assignments, ", ")+" WHERE id = ?", append(args, id)...)
+	if err != nil {
+		return fmt.Errorf("failed to update user %d: %w", id, err)
+	}
+	if err := expectUserAffected(result, id); err != nil {
+		return err
+	}
+	// A new password ends all sessions logged in with the old one
+	if passwordHash != nil {
+		if _, err := tx.Exec("DELETE FROM sessions WHERE user_id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete sessions of user %d: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user update: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteUser(id int64) error {
+	result, err := s.db.Exec("DELETE FROM users WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user %d: %w", id, err)
+	}
+	return expectUserAffected(result, id)
+}
+
+func expectUserAffected(result sql.Result, id int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("user %d: %w", id, ErrUserNotFound)
+	}
+	return nil
+}
+
+// shareColumns selects a share link with the name of its photo.
+const shareColumns = `s.id, s.kind, COALESCE(p.name, ''), COALESCE(s.event_id, 0), COALESCE(s.album_id, 0),
+	s.label, s.allow_upload, s.expires_at, s.created_at`
+
+const shareTables = "shares s LEFT JOIN photos p ON p.id = s.photo_id"
+
+func (s *SQLiteStore) CreateShare(share Share) (Share, error) {
+	var id int64
+	err := s.db.QueryRow(`INSERT INTO shares (kind, photo_id, event_id, album_id, label, allow_upload, expires_at, created_at)
+		VALUES (?, (SELECT id FROM photos WHERE name = ?), NULLIF(?, 0), NULLIF(?, 0), ?, ?, ?, ?) RETURNING id`,
+		share.Kind, share.Photo, share.EventID, share.AlbumID, share.Label, share.AllowUpload,
+		share.Expires.UTC().Format(time.RFC3339Nano), time.Now().UTC().Format(time.RFC3339Nano)).Scan(&id)
+	if err != nil {
+		return Share{}, fmt.Errorf("failed to create share: %w", err)
+	}
+	return s.GetShare(id)
+}
+
+func (s *SQLiteStore) ListShares() ([]Share, error) {
+	rows, err := s.db.Query("SELECT " + shareColumns + " FROM " + shareTables + " ORDER BY s.created_at DESC, s.id DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shares: %w", err)
+	}
+	defer rows.Close()
+
+	var shares []Share
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, rows.Err()
+}
+
+func (s *SQLiteStore) GetShare(id int64) (Share, error) {
+	share, err := scanShare(s.db.QueryRow("SELECT "+shareColumns+" FROM "+shareTables+" WHERE s.id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Share{}, fmt.Errorf("share %d: %w", id, ErrShareNotFound)
+	}
+	return share, err
+}
+
+func (s *SQLiteStore) DeleteShare(id int64) error {
+	result, err := s.db.Exec("DELETE FROM shares WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete share %d: %w", id, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("share %d: %w", id, ErrShareNotFound)
+	}
+	return nil
+}
+
+// sessionColumns selects a session with the name of its user or share link.
+const sessionColumns = `se.id, se.kind, COALESCE(se.user_id, 0), COALESCE(se.share_id, 0),
+	COALESCE(u.username, sh.label, ''), se.data, se.last_ip, se.user_agent, se.created_at, se.last_seen_at, se.expires_at`
+
+const sessionTables = "sessions se LEFT JOIN users u ON u.id = se.user_id LEFT JOIN shares sh ON sh.id = se.share_id"
+
+func (s *SQLiteStore) CreateSession(tokenHash string, session Session) (Session, error) {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	// Expired sessions are cleaned up whenever a new one starts
+	if _, err := s.db.Exec("DELETE FROM sessions WHERE expires_at <= ?", now); err != nil {
+		return Session{}, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	var id int64
+	err := s.db.QueryRow(`INSERT INTO sessions (token_hash, kind, user_id, share_id, data, last_ip, user_agent, created_at, last_seen_at, expires_at)
+		VALUES (?, ?, NULLIF(?, 0), NULLIF(?, 0), ?, ?, ?, ?, ?, ?) RETURNING id`,
+		tokenHash, session.Kind, session.UserID, session.ShareID, session.Data, session.LastIP, session.UserAgent,
+		now, now, session.Expires.UTC().Format(time.RFC3339Nano)).Scan(&id)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to create session: %w", err)
+	}
+	return scanSession(s.db.QueryRow("SELECT "+sessionColumns+" FROM "+sessionTables+" WHERE se.id = ?", id))
+}
+
+func (s *SQLiteStore) SessionByToken(tokenHash string) (Session, error) {
+	session, err := scanSession(s.db.QueryRow("SELECT "+sessionColumns+" FROM "+sessionTables+" WHERE se.token_hash = ? AND se.expires_at > ?",
+		tokenHash, time.Now().UTC().Format(time.RFC3339Nano)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, ErrSessionNotFound
+	}
+	return session, err
+}
+
+func (s *SQLiteStore) UpdateSession(tokenHash string, data []byte, expires time.Time) error {
+	result, err := s.db.Exec("UPDATE sessions SET data = ?, expires_at = ? WHERE token_hash = ?",
+		data, expires.UTC().Format(time.RFC3339Nano), tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	return expectSessionAffected(result, "session")
+}
+
+func (s *SQLiteStore) TouchSession(tokenHash string, lastIP, userAgent string) error {
+	result, err := s.db.Exec("UPDATE sessions SET last_seen_at = ?, last_ip = ?, user_agent = ? WHERE token_hash = ?",
+		time.Now().UTC().Format(time.RFC3339Nano), lastIP, userAgent, tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	return expectSessionAffected(result, "session")
+}
+
+func (s *SQLiteStore) ListSessions() ([]Session, error) {
+	rows, err := s.db.Query("SELECT "+sessionColumns+" FROM "+sessionTables+" WHERE se.expires_at > ? ORDER BY se.last_seen_at DESC, se.id DESC",
+		time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func (s *SQLiteStore) DeleteSession(id int64) error {
+	result, err := s.db.Exec("DELETE FROM sessions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete session %d: %w", id, err)
+	}
+	return expectSessionAffected(result, fmt.Sprintf("session %d", id))
+}
+
+func (s *SQLiteStore) DeleteSessionByToken(tokenHash string) error {
+	result, err := s.db.Exec("DELETE FROM sessions WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return expectSessionAffected(result, "session")
+}
+
+const apiTokenColumns = "id, name, scope, created_at, last_used_at"
+
+func (s *SQLiteStore) CreateAPIToken(tokenHash string, token APIToken) (APIToken, error) {
+	var id int64
+	err := s.db.QueryRow("INSERT INTO api_tokens (token_hash, name, scope, created_at) VALUES (?, ?, ?, ?) RETURNING id",
+		tokenHash, token.Name, token.Scope, time.Now().UTC().Format(time.RFC3339Nano)).Scan(&id)
+	if err != nil {
+		return APIToken{}, fmt.Errorf("failed to create API token: %w", err)
+	}
+	return scanAPIToken(s.db.QueryRow("SELECT "+apiTokenColumns+" FROM api_tokens WHERE id = ?", id))
+}
+
+func (s *SQLiteStore) APITokenByHash(tokenHash string) (APIToken, error) {
+	token, err := scanAPIToken(s.db.QueryRow("SELECT "+apiTokenColumns+" FROM api_tokens WHERE token_hash = ?", tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return APIToken{}, ErrAPITokenNotFound
+	}
+	return token, err
+}
+
+func (s *SQLiteStore) TouchAPIToken(tokenHash string) error {
+	if _, err := s.db.Exec("UPDATE api_tokens SET last_used_at = ? WHERE token_hash = ?",
+		time.Now().UTC().Format(time.RFC3339Nano), tokenHash); err != nil {
+		return fmt.Errorf("failed to update API token: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListAPITokens() ([]APIToken, error) {
+	rows, err := s.db.Query("SELECT " + apiTokenColumns + " FROM api_tokens ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (s *SQLiteStore) DeleteAPIToken(id int64) error {
+	result, err := s.db.Exec("DELETE FROM api_tokens WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete API token %d: %w", id, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("API token %d: %w", id, ErrAPITokenNotFound)
+	}
+	return nil
+}
+
+func expectSessionAffected(result sql.Result, name string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", name, ErrSessionNotFound)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) SetPerceptualHash(name string, hash uint64) error {
+	if _, err := s.db.Exec("UPDATE photos SET perceptual_hash = ? WHERE name = ?", perceptualHashValue(hash), name); err != nil {
+		return fmt.Errorf("failed to save perceptual hash for %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ResolveDuplicates(kept, removed []PhotoInfo, deletedAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start duplicate resolution: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for i := range kept {
+		for j := i + 1; j < len(kept); j++ {
+			pair := duplicatePair(kept[i].ID, kept[j].ID)
+			if _, err := tx.Exec("INSERT OR IGNORE INTO duplicate_dismissals (photo_a, photo_b) VALUES (?, ?)", pair[0], pair[1]); err != nil {
+				return fmt.Errorf("failed to dismiss duplicate: %w", err)
+			}
+		}
+	}
+	for _, photo := range removed {
+		for _, uploader := range append([]string{photo.Uploader}, photo.Contributors...) {
+			if err := addContributor(tx, kept[0].Name, uploader); err != nil {
+				return err
+			}
+		}
+		if err := trashPhoto(tx, photo.Name, deletedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit duplicate resolution: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DismissedDuplicates() (map[[2]int64]bool, error) {
+	rows, err := s.db.Query("SELECT photo_a, photo_b FROM duplicate_dismissals")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dismissed duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	dismissed := make(map[[2]int64]bool)
+	for rows.Next() {
+		var pair [2]int64
+		if err := rows.Scan(&pair[0], &pair[1]); err != nil {
+			return nil, err
+		}
+		dismissed[pair] = true
+	}
+	return dismissed, rows.Err()
+}
+
+func (s *SQLiteStore) TrashPhoto(name string, deletedAt time.Time) error {
+	return trashPhoto(s.db, name, deletedAt)
+}
+
+func trashPhoto(db execer, name string, deletedAt time.Time) error {
+	result, err := db.Exec("UPDATE photos SET deleted_at = ? WHERE name = ? AND "+livePhotos, deletedAt.Format(time.RFC3339Nano), name)
+	if err != nil {
+		return fmt.Errorf("failed to move metadata for %s to the trash: %w", name, err)
+	}
+	return expectAffected(result, name)
+}
+
+func (s *SQLiteStore) RestorePhoto(name string) error {
+	result, err := s.db.Exec("UPDATE photos SET deleted_at = NULL WHERE name = ? AND deleted_at IS NOT NULL", name)
+	if err != nil {
+		return fmt.Errorf("failed to restore metadata for %s: %w", name, err)
+	}
+	return expectAffected(result, name)
+}
+
+func (s *SQLiteStore) ListTrash() ([]PhotoInfo, error) {
+	return s.queryPhotos("SELECT " + photoColumns + " FROM photos WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, name")
+}
+
+// expectAffected returns ErrPhotoNotFound if result did not touch any row.
+func expectAffected(result sql.Result, name string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", name, ErrPhotoNotFound)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeletePhoto(name string) error {
+	if _, err := s.db.Exec("DELETE FROM photos WHERE name = ?", name); err != nil {
+		return fmt.Errorf("failed to delete metadata for %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListPhotos(filter PhotoFilter) ([]PhotoInfo, error) {
+	where, args := filterClause(filter)
+	return s.queryPhotos("SELECT "+photoColumns+" FROM photos"+where+" ORDER BY sort_time DESC, name", args...)
+}
+
+// queryPhotos runs a photo query and loads the contributors of the result.
+func (s *SQLiteStore) queryPhotos(query string, args ...any) ([]PhotoInfo, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query photos: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []PhotoInfo
+	for rows.Next() {
+		info, err := scanPhoto(rows)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.loadDetails(photos); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func (s *SQLiteStore) CountPhotos(filter PhotoFilter) (int, error) {
+	where, args := filterClause(filter)
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM photos"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count photos: %w", err)
+	}
+	return count, nil
+}
+
+func (s *SQLiteStore) DistinctEvents() ([]string, error) {
+	return s.distinct("event")
+}
+
+func (s *SQLiteStore) DistinctUploaders() ([]string, error) {
+	rows, err := s.db.Query(`SELECT uploader FROM photos WHERE uploader != '' AND ` + livePhotos + `
+		UNION SELECT pu.uploader FROM photo_uploaders pu JOIN photos p ON p.id = pu.photo_id WHERE p.` + livePhotos + `
+		ORDER BY uploader`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query distinct uploader values: %w", err)
+	}
+	return scanStrings(rows)
+}
+
+// distinct returns the sorted non-empty values of a column; column is never user input.
+func (s *SQLiteStore) distinct(column string) ([]string, error) {
+	rows, err := s.db.Query("SELECT DISTINCT " + column + " FROM photos WHERE " + column + " != '' AND " + livePhotos + " ORDER BY " + column)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query distinct %s values: %w", column, err)
+	}
+	return scanStrings(rows)
+}
+
+func scanStrings(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// ImportSidecars performs the one-time import of the legacy <name>.json
+// metadata files in dir. Sidecars are left in place so the import can be
+// verified; they are no longer read once the import has completed.
+func (s *SQLiteStore) ImportSidecars(dir string) (int, error) {
+	var done string
+	err := s.db.QueryRow("SELECT value FROM meta WHERE key = ?", sidecarsImportedFlag).Scan(&done)
+	if err == nil {
+		return 0, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to read import state: %w", err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to read metadata directory: %w", err)
+	}
+
+	importedCount := 0
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		// #nosec G304 - path is constructed from the controlled metadata directory
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			log.Printf("Failed to read metadata sidecar %s: %v", file.Name(), err)
+			continue
+		}
+
+		var info PhotoInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			log.Printf("Failed to unmarshal metadata sidecar %s: %v", file.Name(), err)
+			continue
+		}
+		if info.Name == "" {
+			info.Name = strings.TrimSuffix(file.Name(), ".json")
+		}
+		if info.Path == "" {
+			info.Path = "/uploads/" + info.Name
+		}
+
+		if err := s.SavePhoto(&info); err != nil {
+			log.Printf("Failed to import metadata sidecar %s: %v", file.Name(), err)
+			continue
+		}
+		importedCount++
+	}
+
+	if _, err := s.db.Exec("INSERT INTO meta (key, value) VALUES (?, ?)", sidecarsImportedFlag, time.Now().Format(time.RFC3339)); err != nil {
+		return importedCount, fmt.Errorf("failed to record import state: %w", err)
+	}
+	return importedCount, nil
+}
+
+func filterClause(filter PhotoFilter) (string, []any) {
+	conditions := []string{livePhotos}
+	var args []any
+	if filter.Event != "" {
+		conditions = append(conditions, "event = ?")
+		args = append(args, filter.Event)
+	}
+	if filter.Uploader != "" {
+		conditions = append(conditions, "(uploader = ? OR id IN (SELECT photo_id FROM photo_uploaders WHERE uploader = ?))")
+		args = append(args, filter.Uploader, filter.Uploader)
+	}
+	if tags := uniqueTags(filter.Tags); len(tags) > 0 {
+		condition := "id IN (SELECT photo_id FROM photo_tags WHERE tag IN (?" + strings.Repeat(", ?", len(tags)-1) + ")"
+		for _, tag := range tags {
+			args = append(args, tag)
+		}
+		if filter.AllTags {
+			condition += " GROUP BY photo_id HAVING COUNT(*) = ?"
+			args = append(args, len(tags))
+		}
+		conditions = append(conditions, condition+")")
+	}
+	if access := filter.Access; access != nil {
+		condition, accessArgs := accessCondition(access)
+		conditions = append(conditions, condition)
+		args = append(args, accessArgs...)
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// accessCondition restricts a photo query to the photos allowed by access.
+func accessCondition(access *PhotoAccess) (string, []any) {
+	alternatives := []string{"FALSE"}
+	var args []any
+	if access.ownerID != 0 {
+		alternatives = append(alternatives, "owner_id = ?")
+		args = append(args, access.ownerID)
+	}
+	if len(access.photos) > 0 {
+		alternatives = append(alternatives, "name IN (?"+strings.Repeat(", ?", len(access.photos)-1)+")")
+		for name := range access.photos {
+			args = append(args, name)
+		}
+	}
+	if len(access.unlocked) > 0 {
+		alternatives = append(alternatives, "event IN (?"+strings.Repeat(", ?", len(access.unlocked)-1)+")")
+		for name := range access.unlocked {
+			args = append(args, name)
+		}
+	}
+	if access.public {
+		alternatives = append(alternatives, "event NOT IN ("+strings.TrimPrefix(strings.Repeat(", ?", len(access.protected)), ", ")+")")
+		for name := range access.protected {
+			args = append(args, name)
+		}
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPhoto(row rowScanner) (PhotoInfo, error) {
+	var info PhotoInfo
+	var uploadDate string
+	var photoTime sql.NullString
+	var perceptualHash sql.NullInt64
+	var deletedAt sql.NullString
+	var ownerID sql.NullInt64
+	if err := row.Scan(&info.ID, &info.Name, &info.Path, &info.Uploader, &info.Event, &uploadDate, &photoTime, &info.Hash, &perceptualHash, &deletedAt, &info.Caption, &ownerID); err != nil {
+		return PhotoInfo{}, err
+	}
+	info.OwnerID = ownerID.Int64
+	if perceptualHash.Valid {
+		info.PerceptualHash = uint64(perceptualHash.Int64) // #nosec G115 - stored as the bit pattern of the uint64
+	}
+
+	var err error
+	if info.Date, err = time.Parse(time.RFC3339Nano, uploadDate); err != nil {
+		return PhotoInfo{}, fmt.Errorf("invalid upload date for %s: %w", info.Name, err)
+	}
+	if photoTime.Valid {
+		if info.PhotoTime, err = time.Parse(time.RFC3339Nano, photoTime.String); err != nil {
+			return PhotoInfo{}, fmt.Errorf("invalid photo time for %s: %w", info.Name, err)
+		}
+	}
+	if deletedAt.Valid {
+		if info.DeletedAt, err = time.Parse(time.RFC3339Nano, deletedAt.String); err != nil {
+			return PhotoInfo{}, fmt.Errorf("invalid deletion time for %s: %w", info.Name, err)
+		}
+	}
+	return info, nil
+}
+
+func scanAlbum(row rowScanner) (Album, error) {
+	var album Album
+	var created string
+	if err := row.Scan(&album.ID, &album.Name, &created, &album.PhotoCount, &album.CoverPhoto); err != nil {
+		return Album{}, err
+	}
+
+	var err error
+	if album.Created, err = time.Parse(time.RFC3339Nano, created); err != nil {
+		return Album{}, fmt.Errorf("invalid creation time for album %s: %w", album.Name, err)
+	}
+	return album, nil
+}
+
+func scanSession(row rowScanner) (Session, error) {
+	var session Session
+	var created, lastSeen, expires string
+	if err := row.Scan(&session.ID, &session.Kind, &session.UserID, &session.ShareID, &session.Name, &session.Data,
+		&session.LastIP, &session.UserAgent, &created, &lastSeen, &expires); err != nil {
+		return Session{}, err
+	}
+	var err error
+	if session.Created, err = time.Parse(time.RFC3339Nano, created); err != nil {
+		return Session{}, fmt.Errorf("invalid creation time for session %d: %w", session.ID, err)
+	}
+	if session.LastSeen, err = time.Parse(time.RFC3339Nano, lastSeen); err != nil {
+		return Session{}, fmt.Errorf("invalid last seen time for session %d: %w", session.ID, err)
+	}
+	if session.Expires, err = time.Parse(time.RFC3339Nano, expires); err != nil {
+		return Session{}, fmt.Errorf("invalid expiry time for session %d: %w", session.ID, err)
+	}
+	return session, nil
+}
+
+func scanAPIToken(row rowScanner) (APIToken, error) {
+	var token APIToken
+	var created, lastUsed string
+	if err := row.Scan(&token.ID, &token.Name, &token.Scope, &created, &lastUsed); err != nil {
+		return APIToken{}, err
+	}
+	var err error
+	if token.Created, err = time.Parse(time.RFC3339Nano, created); err != nil {
+		return APIToken{}, fmt.Errorf("invalid creation time for API token %d: %w", token.ID, err)
+	}
+	if lastUsed != "" {
+		if token.LastUsed, err = time.Parse(time.RFC3339Nano, lastUsed); err != nil {
+			return APIToken{}, fmt.Errorf("invalid last use time for API token %d: %w", token.ID, err)
+		}
+	}
+	return token, nil
+}
+
+func scanShare(row rowScanner) (Share, error) {
+	var share Share
+	var expires, created string
+	if err := row.Scan(&share.ID, &share.Kind, &share.Photo, &share.EventID, &share.AlbumID,
+		&share.Label, &share.AllowUpload, &expires, &created); err != nil {
+		return Share{}, err
+	}
+
+	var err error
+	if share.Expires, err = time.Parse(time.RFC3339Nano, expires); err != nil {
+		return Share{}, fmt.Errorf("invalid expiry time for share %d: %w", share.ID, err)
+	}
+	if share.Created, err = time.Parse(time.RFC3339Nano, created); err != nil {
+		return Share{}, fmt.Errorf("invalid creation time for share %d: %w", share.ID, err)
+	}
+	return share, nil
+}
+
+func scanUser(row rowScanner) (User, error) {
+	var user User
+	var created string
+	if err := row.Scan(&user.ID, &user.Username, &user.Role, &created); err != nil {
+		return User{}, err
+	}
+
+	var err error
+	if user.Created, err = time.Parse(time.RFC3339Nano, created); err != nil {
+		return User{}, fmt.Errorf("invalid creation time for user %s: %w", user.Username, err)
+	}
+	return user, nil
+}
+
+// prefixColumns qualifies each column of a comma-separated list with a table alias.
+func prefixColumns(alias, columns string) string {
+	parts := strings.Split(columns, ", ")
+	for i, column := range parts {
+		parts[i] = alias + "." + column
+	}
+	return strings.Join(parts, ", ")
+}
+
+func scanEvent(row rowScanner) (Event, error) {
+	var event Event
+	var startDate, endDate sql.NullString
+	if err := row.Scan(&event.ID, &event.Name, &event.Description, &startDate, &endDate, &event.CoverPhoto, &event.Protected); err != nil {
+		return Event{}, err
+	}
+
+	var err error
+	if startDate.Valid {
+		if event.StartDate, err = time.Parse(time.DateOnly, startDate.String); err != nil {
+			return Event{}, fmt.Errorf("invalid start date for event %s: %w", event.Name, err)
+		}
+	}
+	if endDate.Valid {
+		if event.EndDate, err = time.Parse(time.DateOnly, endDate.String); err != nil {
+			return Event{}, fmt.Errorf("invalid end date for event %s: %w", event.Name, err)
+		}
+	}
+	return event, nil
+}
+
+// eventDateValue maps an event date to its column value; the zero time clears it.
+func eventDateValue(date time.Time) sql.NullString {
+	if date.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: date.Format(time.DateOnly), Valid: true}
+}
+
+// perceptualHashValue maps a perceptual hash to its column value. SQLite has
+// no unsigned integers, so the bit pattern is stored as int64; 0 means unknown.
+func perceptualHashValue(hash uint64) sql.NullInt64 {
+	if hash == 0 {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(hash), Valid: true} // #nosec G115 - bit pattern is preserved
+}
+
+// sortTime is the key photos are ordered by: the photo time, or the upload
+// time for photos without a photo time.
+func sortTime(info PhotoInfo) int64 {
+	if !info.PhotoTime.IsZero() {
+		return info.PhotoTime.UnixNano()
+	}
+	return info.Date.UnixNano()
+}
@@ -0,0 +1,602 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T, metadataDir string) *SQLiteStore {
+	t.Helper()
+
+	store, err := OpenSQLiteStore(metadataDir)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+func TestSQLiteStoreSaveAndGet(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+
+	photoTime := time.Date(2023, 12, 24, 18, 30, 0, 0, time.UTC)
+	info := PhotoInfo{
+		Path:      "/uploads/tree.jpg",
+		Name:      "tree.jpg",
+		Uploader:  "Alice",
+		Event:     "Christmas",
+		Date:      time.Now(),
+		PhotoTime: photoTime,
+	}
+	if err := store.SavePhoto(&info); err != nil {
+		t.Fatal(err)
+	}
+	if info.ID == 0 {
+		t.Error("Expected SavePhoto to assign an ID")
+	}
+
+	loaded, err := store.GetPhoto("tree.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ID != info.ID || loaded.Uploader != "Alice" || loaded.Event != "Christmas" {
+		t.Errorf("Unexpected metadata: %+v", loaded)
+	}
+	if !loaded.PhotoTime.Equal(photoTime) {
+		t.Errorf("Expected photo time %v, got %v", photoTime, loaded.PhotoTime)
+	}
+
+	// Saving again updates the existing row
+	info.Event = "Xmas"
+	if err := store.SavePhoto(&info); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = store.GetPhoto("tree.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ID != info.ID || loaded.Event != "Xmas" {
+		t.Errorf("Expected updated event with same ID, got %+v", loaded)
+	}
+
+	if err := store.DeletePhoto("tree.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetPhoto("tree.jpg"); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected ErrPhotoNotFound after delete, got %v", err)
+	}
+}
+
+func TestSQLiteStoreListPhotos(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+
+	now := time.Now()
+	photos := []PhotoInfo{
+		{Name: "a.jpg", Uploader: "Alice", Event: "Birthday", Date: now, PhotoTime: now.Add(-48 * time.Hour)},
+		{Name: "b.jpg", Uploader: "Bob", Event: "Wedding", Date: now, PhotoTime: now.Add(24 * time.Hour)},
+		{Name: "c.jpg", Uploader: "Alice", Event: "Birthday", Date: now.Add(-12 * time.Hour)},
+	}
+	for i := range photos {
+		photos[i].Path = "/uploads/" + photos[i].Name
+		if err := store.SavePhoto(&photos[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := store.ListPhotos(PhotoFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, photo := range all {
+		names = append(names, photo.Name)
+	}
+	if len(names) != 3 || names[0] != "b.jpg" || names[1] != "c.jpg" || names[2] != "a.jpg" {
+		t.Errorf("Expected order [b.jpg c.jpg a.jpg], got %v", names)
+	}
+
+	filtered, err := store.ListPhotos(PhotoFilter{Event: "Birthday", Uploader: "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 2 {
+		t.Errorf("Expected 2 photos for Birthday/Alice, got %d", len(filtered))
+	}
+
+	count, err := store.CountPhotos(PhotoFilter{Uploader: "Bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 photo for Bob, got %d", count)
+	}
+
+	events, err := store.DistinctEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0] != "Birthday" || events[1] != "Wedding" {
+		t.Errorf("Expected [Birthday Wedding], got %v", events)
+	}
+
+	uploaders, err := store.DistinctUploaders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploaders) != 2 || uploaders[0] != "Alice" || uploaders[1] != "Bob" {
+		t.Errorf("Expected [Alice Bob], got %v", uploaders)
+	}
+}
+
+func TestSQLiteStoreImportSidecars(t *testing.T) {
+	metadataDir := t.TempDir()
+
+	sidecar := PhotoInfo{
+		Path:     "/uploads/legacy.jpg",
+		Name:     "legacy.jpg",
+		Uploader: "Carol",
+		Event:    "Party",
+		Date:     time.Now(),
+	}
+	data, err := json.Marshal(sidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sidecarPath := filepath.Join(metadataDir, "legacy.jpg.json")
+	if err := os.WriteFile(sidecarPath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store := openTestStore(t, metadataDir)
+
+	imported, err := store.ImportSidecars(metadataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 1 {
+		t.Errorf("Expected 1 imported sidecar, got %d", imported)
+	}
+
+	loaded, err := store.GetPhoto("legacy.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Uploader != "Carol" || loaded.Event != "Party" {
+		t.Errorf("Unexpected imported metadata: %+v", loaded)
+	}
+
+	// The import only runs once, later sidecar changes are ignored
+	sidecar.Event = "Changed"
+	data, _ = json.Marshal(sidecar)
+	if err := os.WriteFile(sidecarPath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	imported, err = store.ImportSidecars(metadataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 0 {
+		t.Errorf("Expected second import to be skipped, imported %d", imported)
+	}
+	loaded, _ = store.GetPhoto("legacy.jpg")
+	if loaded.Event != "Party" {
+		t.Errorf("Expected event to remain 'Party', got %s", loaded.Event)
+	}
+}
+
+func TestSQLiteStoreReopen(t *testing.T) {
+	metadataDir := t.TempDir()
+
+	store, err := OpenSQLiteStore(metadataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := PhotoInfo{Path: "/uploads/x.png", Name: "x.png", Date: time.Now()}
+	if err := store.SavePhoto(&info); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening must not re-run migrations or lose data
+	store = openTestStore(t, metadataDir)
+	if _, err := store.GetPhoto("x.png"); err != nil {
+		t.Errorf("Expected photo to survive reopen, got %v", err)
+	}
+}
+
+func TestSQLiteStoreContributors(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+
+	info := PhotoInfo{Path: "/uploads/group.jpg", Name: "group.jpg", Uploader: "Alice", Event: "Wedding", Date: time.Now(), Hash: "abc123"}
+	if err := store.SavePhoto(&info); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := store.FindPhotoByHash("abc123", "Wedding")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != "group.jpg" {
+		t.Errorf("Expected group.jpg for hash, got %s", found.Name)
+	}
+	if _, err := store.FindPhotoByHash("", "Wedding"); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected ErrPhotoNotFound for empty hash, got %v", err)
+	}
+	if _, err := store.FindPhotoByHash("abc123", "Party"); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected ErrPhotoNotFound in another event, got %v", err)
+	}
+
+	for _, uploader := range []string{"Bob", "Bob", "Alice"} {
+		if err := store.AddContributor("group.jpg", uploader); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := store.GetPhoto("group.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Contributors) != 1 || loaded.Contributors[0] != "Bob" {
+		t.Errorf("Expected contributors [Bob], got %v", loaded.Contributors)
+	}
+
+	photos, err := store.ListPhotos(PhotoFilter{Uploader: "Bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 1 || photos[0].Name != "group.jpg" {
+		t.Errorf("Expected contributor filter to match group.jpg, got %+v", photos)
+	}
+
+	uploaders, err := store.DistinctUploaders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploaders) != 2 || uploaders[0] != "Alice" || uploaders[1] != "Bob" {
+		t.Errorf("Expected [Alice Bob], got %v", uploaders)
+	}
+}
+
+func TestSQLiteStoreResolveDuplicatesAtomic(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+
+	photos := make(map[string]PhotoInfo)
+	for _, name := range []string{"keep.jpg", "copy.jpg", "gone.jpg"} {
+		info := PhotoInfo{Path: "/uploads/" + name, Name: name, Uploader: strings.TrimSuffix(name, ".jpg"), Date: time.Now()}
+		if err := store.SavePhoto(&info); err != nil {
+			t.Fatal(err)
+		}
+		photos[name] = info
+	}
+	if err := store.TrashPhoto("gone.jpg", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A photo that is no longer live fails the whole resolution
+	err := store.ResolveDuplicates([]PhotoInfo{photos["keep.jpg"]}, []PhotoInfo{photos["copy.jpg"], photos["gone.jpg"]}, time.Now())
+	if !errors.Is(err, ErrPhotoNotFound) {
+		t.Fatalf("Expected ErrPhotoNotFound, got %v", err)
+	}
+	if _, err := store.GetPhoto("copy.jpg"); err != nil {
+		t.Errorf("Expected copy.jpg to stay live, got %v", err)
+	}
+	kept, err := store.GetPhoto("keep.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kept.Contributors) != 0 {
+		t.Errorf("Expected no contributors credited, got %v", kept.Contributors)
+	}
+
+	if err := store.ResolveDuplicates([]PhotoInfo{photos["keep.jpg"]}, []PhotoInfo{photos["copy.jpg"]}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetTrashedPhoto("copy.jpg"); err != nil {
+		t.Errorf("Expected copy.jpg in the trash, got %v", err)
+	}
+	if kept, err = store.GetPhoto("keep.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if len(kept.Contributors) != 1 || kept.Contributors[0] != "copy" {
+		t.Errorf("Expected the uploader of copy.jpg to be credited, got %v", kept.Contributors)
+	}
+}
+
+func TestSQLiteStoreUpdatePhotos(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+
+	for _, name := range []string{"a.jpg", "b.jpg"} {
+		info := PhotoInfo{Path: "/uploads/" + name, Name: name, Uploader: "Alice", Event: "Weding", Date: time.Now()}
+		if err := store.SavePhoto(&info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	event, caption := "Wedding", "First dance"
+	if err := store.UpdatePhotos([]string{"a.jpg", "missing.jpg"}, PhotoUpdate{Event: &event}); !errors.Is(err, ErrPhotoNotFound) {
+		t.Fatalf("Expected ErrPhotoNotFound, got %v", err)
+	}
+	loaded, err := store.GetPhoto("a.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Event != "Weding" {
+		t.Errorf("Expected failed update to change nothing, got event %q", loaded.Event)
+	}
+
+	if err := store.UpdatePhotos([]string{"a.jpg", "b.jpg"}, PhotoUpdate{Event: &event, Caption: &caption}); err != nil {
+		t.Fatal(err)
+	}
+	events, err := store.DistinctEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0] != "Wedding" {
+		t.Errorf("Expected [Wedding], got %v", events)
+	}
+	loaded, err = store.GetPhoto("b.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Caption != "First dance" || loaded.Uploader != "Alice" {
+		t.Errorf("Expected caption set and uploader unchanged, got %+v", loaded)
+	}
+}
+
+func TestSQLiteStoreEvents(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+
+	for _, photo := range []struct{ name, event string }{
+		{"a.jpg", "Weding"},
+		{"b.jpg", "Wedding"},
+		{"c.jpg", "Party"},
+	} {
+		info := PhotoInfo{Path: "/uploads/" + photo.name, Name: photo.name, Uploader: "Alice", Event: photo.event, Date: time.Now()}
+		if err := store.SavePhoto(&info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := store.ListEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 || events[0].Name != "Party" || events[1].Name != "Wedding" || events[2].Name != "Weding" {
+		t.Fatalf("Expected events created from the photos, got %+v", events)
+	}
+	party, wedding, typo := events[0], events[1], events[2]
+
+	name := "Wedding"
+	if err := store.UpdateEvent(typo.ID, EventUpdate{Name: &name}); !errors.Is(err, ErrEventExists) {
+		t.Errorf("Expected ErrEventExists when renaming to a taken name, got %v", err)
+	}
+
+	name, description := "Garden Party", "In the back yard"
+	startDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	cover := "c.jpg"
+	if err := store.UpdateEvent(party.ID, EventUpdate{Name: &name, Description: &description, StartDate: &startDate, CoverPhoto: &cover}); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.GetEvent(party.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Name != "Garden Party" || loaded.Description != description || !loaded.StartDate.Equal(startDate) || loaded.CoverPhoto != "c.jpg" {
+		t.Errorf("Unexpected event after update: %+v", loaded)
+	}
+	if photo, err := store.GetPhoto("c.jpg"); err != nil || photo.Event != "Garden Party" {
+		t.Errorf("Expected rename to rewrite the photo's event, got %+v (%v)", photo, err)
+	}
+
+	if err := store.MergeEvents(wedding.ID, []int64{typo.ID, 999}); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Expected ErrEventNotFound for unknown source, got %v", err)
+	}
+	if photo, err := store.GetPhoto("a.jpg"); err != nil || photo.Event != "Weding" {
+		t.Errorf("Expected failed merge to change nothing, got %+v (%v)", photo, err)
+	}
+
+	if err := store.MergeEvents(wedding.ID, []int64{typo.ID}); err != nil {
+		t.Fatal(err)
+	}
+	photos, err := store.ListPhotos(PhotoFilter{Event: "Wedding"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 2 {
+		t.Errorf("Expected 2 photos after merge, got %d", len(photos))
+	}
+	if _, err := store.GetEvent(typo.ID); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Expected merged event to be deleted, got %v", err)
+	}
+}
+
+func TestSQLiteStoreAlbums(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+
+	for _, name := range []string{"a.jpg", "b.jpg", "c.jpg"} {
+		info := PhotoInfo{Path: "/uploads/" + name, Name: name, Uploader: "Alice", Date: time.Now()}
+		if err := store.SavePhoto(&info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	album, err := store.CreateAlbum("Best of")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddAlbumPhotos(album.ID, []string{"c.jpg", "missing.jpg"}); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected ErrPhotoNotFound, got %v", err)
+	}
+	if err := store.AddAlbumPhotos(album.ID, []string{"c.jpg", "a.jpg", "c.jpg", "b.jpg"}); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func() []string {
+		t.Helper()
+		photos, err := store.AlbumPhotos(album.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, photo := range photos {
+			names = append(names, photo.Name)
+		}
+		return names
+	}
+	if got := names(); strings.Join(got, ",") != "c.jpg,a.jpg,b.jpg" {
+		t.Errorf("Expected album order c,a,b, got %v", got)
+	}
+
+	if err := store.TrashPhoto("a.jpg", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ReorderAlbum(album.ID, []string{"c.jpg"}); !errors.Is(err, ErrInvalidAlbum) {
+		t.Errorf("Expected ErrInvalidAlbum for an incomplete order, got %v", err)
+	}
+	if err := store.ReorderAlbum(album.ID, []string{"b.jpg", "b.jpg"}); !errors.Is(err, ErrInvalidAlbum) {
+		t.Errorf("Expected ErrInvalidAlbum for a duplicate entry, got %v", err)
+	}
+	if err := store.ReorderAlbum(album.ID, []string{"b.jpg", "c.jpg"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RestorePhoto("a.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(); strings.Join(got, ",") != "b.jpg,c.jpg,a.jpg" {
+		t.Errorf("Expected restored photo behind the reordered ones, got %v", got)
+	}
+
+	if err := store.RemoveAlbumPhoto(album.ID, "c.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RemoveAlbumPhoto(album.ID, "c.jpg"); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected ErrPhotoNotFound for a photo not in the album, got %v", err)
+	}
+	loaded, err := store.GetAlbum(album.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.PhotoCount != 2 || loaded.CoverPhoto != "b.jpg" {
+		t.Errorf("Expected 2 photos with cover b.jpg, got %+v", loaded)
+	}
+
+	if err := store.DeleteAlbum(album.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AlbumPhotos(album.ID); !errors.Is(err, ErrAlbumNotFound) {
+		t.Errorf("Expected ErrAlbumNotFound after delete, got %v", err)
+	}
+	if _, err := store.GetPhoto("b.jpg"); err != nil {
+		t.Errorf("Expected photos to survive the album, got %v", err)
+	}
+}
+
+func TestSQLiteStoreTags(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+
+	photoTags := map[string][]string{
+		"a.jpg": {"kids", "speech"},
+		"b.jpg": {"kids"},
+		"c.jpg": nil,
+	}
+	for name, tags := range photoTags {
+		info := PhotoInfo{Path: "/uploads/" + name, Name: name, Uploader: "Alice", Date: time.Now()}
+		if err := store.SavePhoto(&info); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.UpdatePhotos([]string{name}, PhotoUpdate{Tags: &tags}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		filter PhotoFilter
+		want   int
+	}{
+		{PhotoFilter{Tags: []string{"kids"}}, 2},
+		{PhotoFilter{Tags: []string{"kids", "speech"}}, 2},
+		{PhotoFilter{Tags: []string{"kids", "speech"}, AllTags: true}, 1},
+		{PhotoFilter{Tags: []string{"kids", "kids"}, AllTags: true}, 2},
+		{PhotoFilter{Tags: []string{"dancefloor"}}, 0},
+	}
+	for _, test := range tests {
+		count, err := store.CountPhotos(test.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != test.want {
+			t.Errorf("Expected %d photos for %+v, got %d", test.want, test.filter, count)
+		}
+	}
+
+	if err := store.UpdatePhotos([]string{"a.jpg", "b.jpg"}, PhotoUpdate{AddTags: []string{"dancefloor"}, RemoveTags: []string{"kids"}}); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.GetPhoto("a.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(loaded.Tags, ",") != "dancefloor,speech" {
+		t.Errorf("Expected tags [dancefloor speech], got %v", loaded.Tags)
+	}
+}
+
+func TestSQLiteStoreUsers(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+
+	alice, err := store.CreateUser("alice", "hash-a", RoleContributor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.ID == 0 || alice.Username != "alice" || alice.Role != RoleContributor || alice.Created.IsZero() {
+		t.Errorf("Unexpected user %+v", alice)
+	}
+	if _, err := store.CreateUser("Alice", "hash-b", RoleViewer); !errors.Is(err, ErrUserExists) {
+		t.Errorf("Expected ErrUserExists for a name differing only in case, got %v", err)
+	}
+
+	user, hash, err := store.UserCredentials("ALICE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != alice.ID || hash != "hash-a" {
+		t.Errorf("Expected credentials of alice, got %+v %q", user, hash)
+	}
+
+	info := PhotoInfo{Path: "/uploads/a.jpg", Name: "a.jpg", Uploader: "alice", Date: time.Now(), OwnerID: alice.ID}
+	if err := store.SavePhoto(&info); err != nil {
+		t.Fatal(err)
+	}
+	if photo, err := store.GetPhoto("a.jpg"); err != nil || photo.OwnerID != alice.ID {
+		t.Errorf("Expected photo owned by alice, got %+v (%v)", photo, err)
+	}
+
+	role, newHash := RoleAdmin, "hash-c"
+	if err := store.UpdateUser(alice.ID, &role, &newHash); err != nil {
+		t.Fatal(err)
+	}
+	if _, hash, _ := store.UserCredentials("alice"); hash != "hash-c" {
+		t.Errorf("Expected updated password hash, got %q", hash)
+	}
+	if err := store.UpdateUser(999, &role, nil); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+
+	if err := store.DeleteUser(alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetUser(alice.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected deleted user to be gone, got %v", err)
+	}
+	if photo, err := store.GetPhoto("a.jpg"); err != nil || photo.OwnerID != 0 {
+		t.Errorf("Expected photo to be kept without owner, got %+v (%v)", photo, err)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	maxTagLength          = 50 // Maximum tag length in bytes
+	defaultTagSuggestions = 10 // Number of tags suggested if no limit is given
+)
+
+// TagCount is a tag together with the number of photos carrying it.
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTag returns the canonical form of a tag: lower case, with
+// surrounding whitespace removed and inner whitespace collapsed.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// normalizeTags normalizes and deduplicates tags and rejects invalid ones.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tags must not be empty", ErrInvalidMetadata)
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d bytes", ErrInvalidMetadata, tag, maxTagLength)
+		}
+		normalized = append(normalized, tag)
+	}
+	return uniqueTags(normalized), nil
+}
+
+// uniqueTags returns tags without duplicates, keeping the first occurrence.
+func uniqueTags(tags []string) []string {
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !slices.Contains(unique, tag) {
+			unique = append(unique, tag)
+		}
+	}
+	return unique
+}
+
+// normalized returns the filter with its tags in canonical form, so tag
+// filters from user input match regardless of case and spacing.
+func (f PhotoFilter) normalized() PhotoFilter {
+	var tags []string
+	for _, tag := range f.Tags {
+		if tag = NormalizeTag(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	f.Tags = uniqueTags(tags)
+	return f
+}
+
+// SuggestTags returns the existing tags starting with prefix, most used first,
+// for tag autocompletion. Only photos the access allows are counted, all for
+// nil access. A limit of 0 or less returns defaultTagSuggestions tags.
+func (s *GalleryService) SuggestTags(prefix string, limit int, access *PhotoAccess) []TagCount {
+	if limit <= 0 {
+		limit = defaultTagSuggestions
+	}
+	prefix = NormalizeTag(prefix)
+
+	counts := make(map[string]int)
+	for _, photo := range s.index.list(PhotoFilter{Access: access}) {
+		for _, tag := range photo.Tags {
+			if strings.HasPrefix(tag, prefix) {
+				counts[tag]++
+			}
+		}
+	}
+
+	suggestions := make([]TagCount, 0, len(counts))
+	for name, count := range counts {
+		suggestions = append(suggestions, TagCount{Name: name, Count: count})
+	}
+	slices.SortFunc(suggestions, func(a, b TagCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
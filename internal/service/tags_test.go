@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := map[string]string{
+		"Speech":            "speech",
+		"  Dance   Floor  ": "dance floor",
+		"\t":                "",
+	}
+	for input, want := range tests {
+		if got := NormalizeTag(input); got != want {
+			t.Errorf("NormalizeTag(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestPhotoFilterMatchesTags(t *testing.T) {
+	photo := PhotoInfo{Name: "a.jpg", Tags: []string{"kids", "speech"}}
+
+	tests := []struct {
+		filter PhotoFilter
+		want   bool
+	}{
+		{PhotoFilter{}, true},
+		{PhotoFilter{Tags: []string{"kids", "dancefloor"}}, true},
+		{PhotoFilter{Tags: []string{"kids", "dancefloor"}, AllTags: true}, false},
+		{PhotoFilter{Tags: []string{"kids", "speech"}, AllTags: true}, true},
+		{PhotoFilter{Tags: []string{"dancefloor"}}, false},
+	}
+	for _, test := range tests {
+		if got := test.filter.Matches(photo); got != test.want {
+			t.Errorf("Matches(%+v) = %v, want %v", test.filter, got, test.want)
+		}
+	}
+}
+
+func TestTagPhotosAndSuggest(t *testing.T) {
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+
+	for i, filename := range []string{"a.png", "b.png", "c.png"} {
+		data := encodeTestImage(t, createPatternImage(20+i, 20, i%2 == 0), "png")
+		if _, err := service.SavePhoto(newTestFileHeader(t, filename, "image/png", data), User{Username: "Alice"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tags := []string{"Speech", " speech ", "Kids"}
+	photo, err := service.UpdatePhotoMetadata("a.png", PhotoUpdate{Tags: &tags})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(photo.Tags, ",") != "kids,speech" {
+		t.Errorf("Expected normalized, deduplicated tags [kids speech], got %v", photo.Tags)
+	}
+	if _, err := service.UpdatePhotosMetadata([]string{"b.png", "c.png"}, PhotoUpdate{AddTags: []string{"kids"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.UpdatePhotoMetadata("a.png", PhotoUpdate{AddTags: []string{" "}}); !errors.Is(err, ErrInvalidMetadata) {
+		t.Errorf("Expected ErrInvalidMetadata for an empty tag, got %v", err)
+	}
+
+	photos, err := service.QueryPhotos(PhotoFilter{Tags: []string{"KIDS", "speech"}, AllTags: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photos) != 1 || photos[0].Name != "a.png" {
+		t.Errorf("Expected only a.png to have both tags, got %+v", photos)
+	}
+
+	suggestions := service.SuggestTags("", 0, nil)
+	if len(suggestions) != 2 || suggestions[0] != (TagCount{Name: "kids", Count: 3}) {
+		t.Errorf("Expected kids as most used tag, got %+v", suggestions)
+	}
+	suggestions = service.SuggestTags("Sp", 5, nil)
+	if len(suggestions) != 1 || suggestions[0].Name != "speech" {
+		t.Errorf("Expected speech for prefix Sp, got %+v", suggestions)
+	}
+}
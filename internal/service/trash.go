@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// trashPurgeInterval is how often the trash purger looks for expired photos.
+const trashPurgeInterval = time.Hour
+
+// ErrPhotoExists is returned when restoring a photo whose name has been
+// taken by another photo in the meantime.
+var ErrPhotoExists = errors.New("a photo with this name already exists")
+
+// ErrDuplicatePhoto is returned when restoring a photo that has been uploaded
+// again to its event while it was in the trash.
+var ErrDuplicatePhoto = errors.New("an identical photo is already in the gallery")
+
+// trashPurger periodically removes photos that have been in the trash for
+// longer than the retention period.
+type trashPurger struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// DeletePhoto moves a photo's original, thumbnail and metadata to the trash,
+// from where it can be restored until it is purged. The JPEG version of a
+// converted photo is deleted.
+func (s *GalleryService) DeletePhoto(filename string) error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	return s.trashPhoto(filename)
+}
+
+// trashPhoto implements DeletePhoto; the caller must hold saveMu.
+func (s *GalleryService) trashPhoto(filename string) error {
+	if _, err := s.store.GetPhoto(filename); err != nil {
+		return err
+	}
+
+	// Mark the metadata first so a failed move leaves nothing half-deleted
+	// that the startup cleanup would treat as an orphan
+	if err := s.store.TrashPhoto(filename, time.Now()); err != nil {
+		return err
+	}
+	return s.moveToTrash(filename)
+}
+
+// moveToTrash moves the blobs of a photo whose metadata is already in the
+// trash there too. If the original cannot be moved, the metadata is restored.
+// The caller must hold saveMu.
+func (s *GalleryService) moveToTrash(filename string) error {
+	if err := moveBlob(s.photos, s.trashPhotos, filename); err != nil && !errors.Is(err, ErrBlobNotFound) {
+		if restoreErr := s.store.RestorePhoto(filename); restoreErr != nil {
+			log.Printf("Failed to restore metadata for %s: %v", filename, restoreErr)
+		}
+		return fmt.Errorf("failed to move %s to the trash: %w", filename, err)
+	}
+	if err := moveBlob(s.thumbnails, s.trashThumbnails, filename); err != nil && !errors.Is(err, ErrBlobNotFound) {
+		log.Printf("Failed to move thumbnail for %s to the trash: %v", filename, err)
+	}
+	// JPEG versions, rotated copies and renditions are created again on restore
+	if err := s.derivatives.Delete(filename); err != nil {
+		log.Printf("Failed to delete JPEG version of %s: %v", filename, err)
+	}
+	s.deleteRenditions(filename)
+
+	s.index.remove(filename)
+	log.Printf("Moved %s to the trash", filename)
+	return nil
+}
+
+// RestorePhoto moves a photo out of the trash back into the gallery. A photo
+// that has been uploaded again to its event in the meantime is not restored,
+// uploads are only stored once per event.
+func (s *GalleryService) RestorePhoto(filename string) error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	trashed, err := s.store.GetTrashedPhoto(filename)
+	if err != nil {
+		return err
+	}
+	if _, err := s.photos.Stat(filename); err == nil {
+		return ErrPhotoExists
+	}
+	live, err := s.store.FindPhotoByHash(trashed.Hash, trashed.Event)
+	if err == nil {
+		return fmt.Errorf("%w: %s", ErrDuplicatePhoto, live.Name)
+	}
+	if !errors.Is(err, ErrPhotoNotFound) {
+		return err
+	}
+
+	if err := moveBlob(s.trashPhotos, s.photos, filename); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", filename, err)
+	}
+	if err := moveBlob(s.trashThumbnails, s.thumbnails, filename); err != nil && !errors.Is(err, ErrBlobNotFound) {
+		log.Printf("Failed to restore thumbnail for %s: %v", filename, err)
+	}
+	if err := s.store.RestorePhoto(filename); err != nil {
+		return err
+	}
+	if needsConversion(filename) {
+		if s.converter != nil {
+			if err := s.generateThumbnailFromPhoto(filename); err != nil {
+				log.Printf("Failed to convert restored photo %s: %v", filename, err)
+			}
+		}
+	} else if err := s.generateRenditionsFromPhoto(filename); err != nil {
+		log.Printf("Failed to generate renditions for restored photo %s: %v", filename, err)
+	}
+
+	photo, err := s.store.GetPhoto(filename)
+	if err != nil {
+		return err
+	}
+	s.index.put(photo)
+	log.Printf("Restored %s from the trash", filename)
+	return nil
+}
+
+// ListTrash returns the photos in the trash, most recently deleted first.
+func (s *GalleryService) ListTrash() ([]PhotoInfo, error) {
+	return s.store.ListTrash()
+}
+
+// TrashRetention returns how long photos stay in the trash, 0 if they are
+// never purged automatically.
+func (s *GalleryService) TrashRetention() time.Duration {
+	return s.trashRetention
+}
+
+// PurgePhoto permanently deletes a photo in the trash.
+func (s *GalleryService) PurgePhoto(filename string) error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	if _, err := s.store.GetTrashedPhoto(filename); err != nil {
+		return err
+	}
+	return s.purgePhoto(filename)
+}
+
+// PurgeTrash permanently deletes all photos that were moved to the trash
+// more than retention ago and returns how many were deleted.
+func (s *GalleryService) PurgeTrash(retention time.Duration) (int, error) {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	trashed, err := s.store.ListTrash()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-retention)
+	purgedCount := 0
+	for _, photo := range trashed {
+		if photo.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := s.purgePhoto(photo.Name); err != nil {
+			log.Printf("Failed to purge %s: %v", photo.Name, err)
+			continue
+		}
+		purgedCount++
+	}
+
+	if purgedCount > 0 {
+		log.Printf("Purged %d photos from the trash", purgedCount)
+	}
+	return purgedCount, nil
+}
+
+// purgePhoto implements PurgePhoto; the caller must hold saveMu.
+func (s *GalleryService) purgePhoto(filename string) error {
+	if err := s.trashPhotos.Delete(filename); err != nil {
+		return err
+	}
+	if err := s.trashThumbnails.Delete(filename); err != nil {
+		log.Printf("Failed to delete trashed thumbnail for %s: %v", filename, err)
+	}
+	return s.store.DeletePhoto(filename)
+}
+
+// StartTrashPurge purges photos older than retention from the trash now and
+// then every trashPurgeInterval. The purger is stopped by Close.
+func (s *GalleryService) StartTrashPurge(retention time.Duration) {
+	if s.purger != nil {
+		return
+	}
+	s.trashRetention = retention
+
+	s.purger = &trashPurger{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go func(p *trashPurger) {
+		defer close(p.done)
+
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+		for {
+			if _, err := s.PurgeTrash(retention); err != nil {
+				log.Printf("Failed to purge trash: %v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-p.stop:
+				return
+			}
+		}
+	}(s.purger)
+}
+
+func (p *trashPurger) close() {
+	close(p.stop)
+	<-p.done
+}
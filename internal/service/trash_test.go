@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Helper function to create a service with one uploaded photo
+func newTestServiceWithPhoto(t *testing.T, filename string) *GalleryService {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	service := newTestGalleryService(t, filepath.Join(tempDir, "uploads"), filepath.Join(tempDir, "metadata"))
+	if _, err := service.SavePhoto(newTestFileHeader(t, filename, "image/png", testPNGBytes(t)), User{Username: "Alice"}, "Party"); err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+func TestDeleteAndRestorePhoto(t *testing.T) {
+	service := newTestServiceWithPhoto(t, "oops.png")
+
+	if err := service.DeletePhoto("oops.png"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeletePhoto("oops.png"); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected ErrPhotoNotFound for second delete, got %v", err)
+	}
+
+	if _, ok := service.index.get("oops.png"); ok {
+		t.Error("Expected deleted photo to leave the index")
+	}
+	if _, _, err := service.ServePhoto("oops.png"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected deleted photo not to be served, got %v", err)
+	}
+	if _, err := service.trashPhotos.Stat("oops.png"); err != nil {
+		t.Errorf("Expected original in the trash, got %v", err)
+	}
+	if _, err := service.trashThumbnails.Stat("oops.png"); err != nil {
+		t.Errorf("Expected thumbnail in the trash, got %v", err)
+	}
+
+	trash, err := service.ListTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 1 || trash[0].Name != "oops.png" || trash[0].DeletedAt.IsZero() {
+		t.Fatalf("Expected oops.png in the trash, got %+v", trash)
+	}
+
+	// The name stays reserved while the photo is in the trash
+	result, err := service.SavePhoto(newTestFileHeader(t, "oops.png", "image/png", encodeTestImage(t, createPatternImage(20, 20, false), "png")), User{Username: "Bob"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Photo.Name == "oops.png" {
+		t.Error("Expected new upload not to reuse the name of a trashed photo")
+	}
+
+	if err := service.RestorePhoto("oops.png"); err != nil {
+		t.Fatal(err)
+	}
+	photo, ok := service.index.get("oops.png")
+	if !ok || photo.Uploader != "Alice" || photo.Event != "Party" {
+		t.Errorf("Expected restored photo with its metadata, got %+v", photo)
+	}
+	if _, err := service.thumbnails.Stat("oops.png"); err != nil {
+		t.Errorf("Expected thumbnail to be restored, got %v", err)
+	}
+	if err := service.RestorePhoto("oops.png"); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected ErrPhotoNotFound when restoring a live photo, got %v", err)
+	}
+}
+
+func TestRestoreUploadedAgain(t *testing.T) {
+	service := newTestServiceWithPhoto(t, "oops.png")
+	if err := service.DeletePhoto("oops.png"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same file is uploaded again while the photo is in the trash
+	result, err := service.SavePhoto(newTestFileHeader(t, "oops.png", "image/png", testPNGBytes(t)), User{Username: "Bob"}, "Party")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Deduplicated {
+		t.Fatal("Expected upload not to be attributed to a photo in the trash")
+	}
+
+	if err := service.RestorePhoto("oops.png"); !errors.Is(err, ErrDuplicatePhoto) {
+		t.Errorf("Expected ErrDuplicatePhoto, got %v", err)
+	}
+	if _, err := service.store.GetTrashedPhoto("oops.png"); err != nil {
+		t.Errorf("Expected photo to stay in the trash, got %v", err)
+	}
+
+	// Once the new copy is gone, the original comes back
+	if err := service.DeletePhoto(result.Photo.Name); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.RestorePhoto("oops.png"); err != nil {
+		t.Errorf("Expected restore to succeed, got %v", err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	service := newTestServiceWithPhoto(t, "old.png")
+
+	if err := service.DeletePhoto("old.png"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not yet expired
+	purged, err := service.PurgeTrash(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 0 {
+		t.Errorf("Expected nothing to be purged, got %d", purged)
+	}
+
+	purged, err = service.PurgeTrash(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 purged photo, got %d", purged)
+	}
+
+	if _, err := service.trashPhotos.Stat("old.png"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected original to be purged, got %v", err)
+	}
+	if _, err := service.store.GetTrashedPhoto("old.png"); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected metadata to be purged, got %v", err)
+	}
+	if err := service.RestorePhoto("old.png"); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected purged photo not to be restorable, got %v", err)
+	}
+}
+
+func TestPurgePhoto(t *testing.T) {
+	service := newTestServiceWithPhoto(t, "gone.png")
+
+	if err := service.PurgePhoto("gone.png"); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Expected live photo not to be purged, got %v", err)
+	}
+	if err := service.DeletePhoto("gone.png"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.PurgePhoto("gone.png"); err != nil {
+		t.Fatal(err)
+	}
+
+	trash, err := service.ListTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 0 {
+		t.Errorf("Expected empty trash, got %+v", trash)
+	}
+}
+
+func TestTrashSurvivesRestart(t *testing.T) {
+	tempDir := t.TempDir()
+	uploadDir := filepath.Join(tempDir, "uploads")
+	metadataDir := filepath.Join(tempDir, "metadata")
+
+	service, err := NewGalleryService(uploadDir, metadataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.SavePhoto(newTestFileHeader(t, "kept.png", "image/png", testPNGBytes(t)), User{Username: "Alice"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeletePhoto("kept.png"); err != nil {
+		t.Fatal(err)
+	}
+	service.Close()
+
+	// Startup cleanup must neither resurrect nor drop trashed photos
+	service = newTestGalleryService(t, uploadDir, metadataDir)
+	if service.index.count() != 0 {
+		t.Errorf("Expected no live photos after restart, got %d", service.index.count())
+	}
+	if err := service.RestorePhoto("kept.png"); err != nil {
+		t.Fatalf("Expected trashed photo to be restorable after restart, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long a file has to stay untouched before the watcher
+// picks it up, so that files still being copied are not indexed half-written.
+const watchDebounce = time.Second
+
+// uploadWatcher keeps the photo index in sync with files that are added to or
+// removed from the upload directory by something other than SavePhoto.
+type uploadWatcher struct {
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// WatchUploadDir starts watching the upload directory for files dropped in or
+// removed directly on disk. The watcher is stopped by Close.
+func (s *GalleryService) WatchUploadDir() error {
+	if s.watcher != nil {
+		return nil
+	}
+	photos, ok := s.photos.(*FileBlobStore)
+	if !ok {
+		return fmt.Errorf("watching the upload directory requires the filesystem blob store")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create upload directory watcher: %w", err)
+	}
+	if err := watcher.Add(photos.dir); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch upload directory: %w", err)
+	}
+
+	s.watcher = &uploadWatcher{
+		watcher: watcher,
+		done:    make(chan struct{}),
+	}
+	go s.watchLoop(s.watcher)
+
+	log.Printf("Watching %s for changes", photos.dir)
+	return nil
+}
+
+func (s *GalleryService) watchLoop(w *uploadWatcher) {
+	defer close(w.done)
+
+	pending := make(map[string]*time.Timer)
+	ready := make(chan string)
+	stop := make(chan struct{})
+	defer func() {
+		close(stop)
+		for _, timer := range pending {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			name := filepath.Base(event.Name)
+			if !s.isImageFile(name) {
+				continue
+			}
+
+			// Restart the quiet period on every event for the file
+			if timer, ok := pending[name]; ok {
+				timer.Stop()
+			}
+			pending[name] = time.AfterFunc(watchDebounce, func() {
+				select {
+				case ready <- name:
+				case <-stop:
+				}
+			})
+		case name := <-ready:
+			delete(pending, name)
+			s.syncUploadedFile(name)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("Upload directory watcher error: %v", err)
+		}
+	}
+}
+
+func (w *uploadWatcher) close() error {
+	err := w.watcher.Close()
+	<-w.done
+	return err
+}
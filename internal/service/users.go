@@ -0,0 +1,235 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 8  // Minimum length of account and event passwords
+	maxUsernameLength = 64 // Maximum username length in bytes
+)
+
+var (
+	// ErrInvalidUser is returned for account changes with invalid values.
+	ErrInvalidUser = errors.New("invalid user")
+	// ErrInvalidCredentials is returned by Authenticate for an unknown
+	// username or a wrong password.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
+
+// Role is the permission level of a user.
+type Role string
+
+const (
+	// RoleViewer may browse and download photos.
+	RoleViewer Role = "viewer"
+	// RoleContributor may additionally upload photos and edit their own.
+	RoleContributor Role = "contributor"
+	// RoleAdmin may do everything, including managing users.
+	RoleAdmin Role = "admin"
+)
+
+// roleLevels orders the roles; a higher level includes the permissions of the lower ones.
+var roleLevels = map[Role]int{
+	RoleViewer:      1,
+	RoleContributor: 2,
+	RoleAdmin:       3,
+}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	return roleLevels[r] > 0
+}
+
+// User is an account that can log in to the gallery. The guest login with
+// the shared gallery password is represented by a viewer with ID 0.
+type User struct {
+	ID       int64     `json:"id"`
+	Username string    `json:"username"`
+	Role     Role      `json:"role"`
+	Created  time.Time `json:"created"`
+}
+
+// HasRole reports whether u has at least the permissions of role.
+func (u User) HasRole(role Role) bool {
+	return roleLevels[u.Role] >= roleLevels[role]
+}
+
+// CanEditPhoto reports whether u may change or delete photo. Admins may edit
+// all photos, contributors only the photos they uploaded.
+func (u User) CanEditPhoto(photo PhotoInfo) bool {
+	if u.HasRole(RoleAdmin) {
+		return true
+	}
+	return u.HasRole(RoleContributor) && u.ID != 0 && photo.OwnerID == u.ID
+}
+
+// UserUpdate changes an account. Nil fields are left unchanged.
+type UserUpdate struct {
+	Role     *Role
+	Password *string
+}
+
+// CreateUser creates an account with a bcrypt hash of password.
+func (s *GalleryService) CreateUser(username, password string, role Role) (User, error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return User{}, err
+	}
+	if !role.Valid() {
+		return User{}, fmt.Errorf("%w: unknown role %q", ErrInvalidUser, role)
+	}
+	hash, err := hashPassword(password, ErrInvalidUser)
+	if err != nil {
+		return User{}, err
+	}
+	return s.store.CreateUser(username, hash, role)
+}
+
+// EnsureAdmin creates an admin account unless a user with that name exists.
+// It is used to bootstrap the first account of a new gallery.
+func (s *GalleryService) EnsureAdmin(username, password string) error {
+	name, err := normalizeUsername(username)
+	if err != nil {
+		return err
+	}
+	if _, _, err := s.store.UserCredentials(name); !errors.Is(err, ErrUserNotFound) {
+		return err
+	}
+	_, err = s.CreateUser(name, password, RoleAdmin)
+	return err
+}
+
+// Authenticate returns the user with the given username and password or
+// ErrInvalidCredentials.
+func (s *GalleryService) Authenticate(username, password string) (User, error) {
+	user, hash, err := s.store.UserCredentials(strings.TrimSpace(username))
+	if errors.Is(err, ErrUserNotFound) {
+		// Compare anyway so unknown usernames take as long as wrong passwords
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return User{}, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// ListUsers returns all accounts sorted by username.
+func (s *GalleryService) ListUsers() ([]User, error) {
+	return s.store.ListUsers()
+}
+
+// GetUser returns an account or ErrUserNotFound.
+func (s *GalleryService) GetUser(id int64) (User, error) {
+	return s.store.GetUser(id)
+}
+
+// UpdateUser changes the role or password of an account. The last admin
+// cannot be demoted.
+func (s *GalleryService) UpdateUser(id int64, update UserUpdate) (User, error) {
+	var passwordHash *string
+	if update.Password != nil {
+		hash, err := hashPassword(*update.Password, ErrInvalidUser)
+		if err != nil {
+			return User{}, err
+		}
+		passwordHash = &hash
+	}
+	if update.Role != nil {
+		if !update.Role.Valid() {
+			return User{}, fmt.Errorf("%w: unknown role %q", ErrInvalidUser, *update.Role)
+		}
+		if *update.Role != RoleAdmin {
+			if err := s.checkNotLastAdmin(id); err != nil {
+				return User{}, err
+			}
+		}
+	}
+
+	if err := s.store.UpdateUser(id, update.Role, passwordHash); err != nil {
+		return User{}, err
+	}
+	return s.store.GetUser(id)
+}
+
+// DeleteUser deletes an account. Photos uploaded by the user are kept but
+// no longer have an owner. The last admin cannot be deleted.
+func (s *GalleryService) DeleteUser(id int64) error {
+	if err := s.checkNotLastAdmin(id); err != nil {
+		return err
+	}
+	if err := s.store.DeleteUser(id); err != nil {
+		return err
+	}
+
+	// Refresh the owner of the user's photos in the index
+	for _, photo := range s.index.list(PhotoFilter{}) {
+		if photo.OwnerID == id {
+			photo.OwnerID = 0
+			s.index.put(photo)
+		}
+	}
+	return nil
+}
+
+// checkNotLastAdmin returns ErrInvalidUser if id is the only admin account.
+func (s *GalleryService) checkNotLastAdmin(id int64) error {
+	users, err := s.store.ListUsers()
+	if err != nil {
+		return err
+	}
+	admins, isAdmin := 0, false
+	for _, user := range users {
+		if user.Role == RoleAdmin {
+			admins++
+			isAdmin = isAdmin || user.ID == id
+		}
+	}
+	if isAdmin && admins == 1 {
+		return fmt.Errorf("%w: the last admin cannot be removed", ErrInvalidUser)
+	}
+	return nil
+}
+
+// dummyPasswordHash is compared against for unknown usernames.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// hashPassword returns a bcrypt hash of password. Passwords that are too
+// short or too long are rejected with the error invalid.
+func hashPassword(password string, invalid error) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("%w: password must be at least %d characters", invalid, minPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return "", fmt.Errorf("%w: %w", invalid, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func normalizeUsername(username string) (string, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return "", fmt.Errorf("%w: username must not be empty", ErrInvalidUser)
+	}
+	if len(username) > maxUsernameLength {
+		return "", fmt.Errorf("%w: username is longer than %d bytes", ErrInvalidUser, maxUsernameLength)
+	}
+	return username, nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUserAccounts(t *testing.T) {
+	service := newTestServiceWithPhoto(t, "legacy.png")
+
+	if err := service.EnsureAdmin("root", "correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	// Bootstrapping again must not fail or reset the password
+	if err := service.EnsureAdmin("root", "other password"); err != nil {
+		t.Fatal(err)
+	}
+	admin, err := service.Authenticate("root", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admin.Role != RoleAdmin {
+		t.Errorf("Expected admin role, got %s", admin.Role)
+	}
+	if _, err := service.Authenticate("root", "other password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for a wrong password, got %v", err)
+	}
+	if _, err := service.Authenticate("nobody", "correct horse"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for an unknown user, got %v", err)
+	}
+
+	if _, err := service.CreateUser("bob", "short", RoleViewer); !errors.Is(err, ErrInvalidUser) {
+		t.Errorf("Expected ErrInvalidUser for a short password, got %v", err)
+	}
+	if _, err := service.CreateUser("bob", "long enough", "owner"); !errors.Is(err, ErrInvalidUser) {
+		t.Errorf("Expected ErrInvalidUser for an unknown role, got %v", err)
+	}
+	if _, err := service.CreateUser(" ", "long enough", RoleViewer); !errors.Is(err, ErrInvalidUser) {
+		t.Errorf("Expected ErrInvalidUser for an empty username, got %v", err)
+	}
+
+	viewer := RoleViewer
+	if _, err := service.UpdateUser(admin.ID, UserUpdate{Role: &viewer}); !errors.Is(err, ErrInvalidUser) {
+		t.Errorf("Expected the last admin not to be demoted, got %v", err)
+	}
+	if err := service.DeleteUser(admin.ID); !errors.Is(err, ErrInvalidUser) {
+		t.Errorf("Expected the last admin not to be deleted, got %v", err)
+	}
+
+	bob, err := service.CreateUser("bob", "long enough", RoleContributor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := service.SavePhoto(newTestFileHeader(t, "bob.png", "image/png", encodeTestImage(t, createPatternImage(24, 24, true), "png")), bob, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Photo.Uploader != "bob" || result.Photo.OwnerID != bob.ID {
+		t.Errorf("Expected photo uploaded and owned by bob, got %+v", result.Photo)
+	}
+
+	legacy, err := service.GetPhoto("legacy.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bob.CanEditPhoto(result.Photo) || bob.CanEditPhoto(legacy) {
+		t.Error("Expected contributors to edit only their own photos")
+	}
+	if !admin.CanEditPhoto(legacy) {
+		t.Error("Expected admins to edit all photos")
+	}
+	if (User{ID: bob.ID, Role: RoleViewer}).CanEditPhoto(result.Photo) {
+		t.Error("Expected viewers not to edit photos")
+	}
+
+	newPassword := "new password"
+	if _, err := service.UpdateUser(bob.ID, UserUpdate{Password: &newPassword}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate("bob", newPassword); err != nil {
+		t.Errorf("Expected login with the new password, got %v", err)
+	}
+
+	if err := service.DeleteUser(bob.ID); err != nil {
+		t.Fatal(err)
+	}
+	if photo, err := service.GetPhoto(result.Photo.Name); err != nil || photo.OwnerID != 0 {
+		t.Errorf("Expected photo of a deleted user to stay without owner, got %+v (%v)", photo, err)
+	}
+}
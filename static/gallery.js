@@ -61,8 +61,9 @@ function openUploadDialog() {
 function closeUploadDialog() {
     uploadDialog.style.display = 'none';
     fileInput.value = '';
-    document.getElementById('uploader-name').value = '';
-    document.getElementById('event-name').value = '';
+    // Keep an event name pre-filled by the page
+    const eventInput = document.getElementById('event-name');
+    eventInput.value = eventInput.defaultValue;
     updateFileList();
 }
 
@@ -103,7 +104,6 @@ function formatFileSize(bytes) {
 
 function uploadFiles() {
     const files = fileInput.files;
-    const uploaderName = document.getElementById('uploader-name').value.trim();
     const eventName = document.getElementById('event-name').value.trim();
 
     if (files.length === 0) return;
@@ -113,9 +113,6 @@ function uploadFiles() {
     for (let i = 0; i < files.length; i++) {
         formData.append('photos', files[i]);
     }
-    if (uploaderName) {
-        formData.append('uploader_name', uploaderName);
-    }
     if (eventName) {
         formData.append('event_name', eventName);
     }
@@ -133,15 +130,29 @@ function uploadFiles() {
 
     fetch('/upload', {
         method: 'POST',
+        headers: { 'Accept': 'application/json', 'X-CSRF-Token': csrfToken() },
         body: formData
     })
         .then(response => {
-            if (response.ok) {
-                // Success - reload the page to show new photos
-                window.location.reload();
-            } else {
+            if (!response.ok) {
                 throw new Error('Upload failed');
             }
+            return response.json();
+        })
+        .then(result => {
+            const notes = [];
+            if (result.deduplicated.length > 0) {
+                const names = result.deduplicated.map(file => file.filename).join(', ');
+                notes.push(`Already in the gallery, added you as uploader: ${names}`);
+            }
+            if (result.failed.length > 0) {
+                notes.push(`Could not be uploaded: ${result.failed.join(', ')}`);
+            }
+            if (notes.length > 0) {
+                alert(notes.join('\n\n'));
+            }
+            // Reload the page to show new photos
+            window.location.reload();
         })
         .catch(error => {
             console.error('Upload error:', error);
@@ -164,17 +175,78 @@ function resetUploadArea() {
     fileInput.value = '';
 }
 
+// CSRF token of the page, which every state-changing request has to send
+function csrfToken() {
+    return document.querySelector('input[name="csrf_token"]').value;
+}
+
+// Photo deletion
+function deletePhoto(name) {
+    if (!confirm(`Move ${name} to the trash?`)) return;
+
+    fetch(`/photos/${encodeURIComponent(name)}`, {
+        method: 'DELETE',
+        headers: { 'Accept': 'application/json', 'X-CSRF-Token': csrfToken() }
+    })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Delete failed');
+            }
+            window.location.reload();
+        })
+        .catch(error => {
+            console.error('Delete error:', error);
+            alert('Could not delete the photo. Please try again.');
+        });
+}
+
+// Tag filter: suggest existing tags while typing
+const tagFilter = document.getElementById('tag-filter');
+const tagSuggestions = document.getElementById('tag-suggestions');
+if (tagFilter && tagSuggestions) {
+    let suggestTimeout;
+    tagFilter.addEventListener('input', () => {
+        clearTimeout(suggestTimeout);
+        suggestTimeout = setTimeout(() => {
+            fetch(`/tags?q=${encodeURIComponent(tagFilter.value)}`)
+                .then(response => response.ok ? response.json() : { tags: [] })
+                .then(result => {
+                    tagSuggestions.innerHTML = '';
+                    result.tags.forEach(tag => {
+                        const option = document.createElement('option');
+                        option.value = tag.name;
+                        option.label = `${tag.name} (${tag.count})`;
+                        tagSuggestions.appendChild(option);
+                    });
+                })
+                .catch(error => console.error('Tag suggestion error:', error));
+        }, 200);
+    });
+}
+
+function removeTagFilter(button) {
+    const form = button.closest('form');
+    button.previousElementSibling.remove();
+    button.remove();
+    form.submit();
+}
+
 // Modal functionality
-function openModal(imageSrc) {
+function openModal(imageSrc, srcset) {
     const modal = document.getElementById('modal');
     const modalImg = document.getElementById('modal-img');
 
     modal.style.display = 'block';
+    // The renditions let the browser skip the full original on small screens
+    modalImg.srcset = srcset || '';
     modalImg.src = imageSrc;
 }
 
 function closeModal() {
-    document.getElementById('modal').style.display = 'none';
+    const modal = document.getElementById('modal');
+    if (modal) {
+        modal.style.display = 'none';
+    }
 }
 
 // Close modal with Escape key